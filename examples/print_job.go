@@ -23,6 +23,7 @@ func (pj *PrintJob) Key() int {
 }
 
 // Execute is called by a Scheduler when the Trigger associated with this job fires.
-func (pj *PrintJob) Execute(_ context.Context) {
+func (pj *PrintJob) Execute(_ context.Context) error {
 	fmt.Println("Executing " + pj.Description())
+	return nil
 }
@@ -0,0 +1,139 @@
+package quartz
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffTrigger implements the quartz.Trigger interface; increases the
+// interval between the fires exponentially, e.g. to retry a failing
+// operation. The BackoffTrigger expires after the maximum number of
+// attempts.
+type BackoffTrigger struct {
+	initial     time.Duration
+	max         time.Duration
+	factor      float64
+	maxAttempts int
+
+	mtx      sync.Mutex
+	rand     *rand.Rand
+	attempt  int
+	interval time.Duration
+}
+
+// Verify BackoffTrigger satisfies the Trigger interface.
+var _ Trigger = (*BackoffTrigger)(nil)
+
+// Verify BackoffTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*BackoffTrigger)(nil)
+
+// BackoffOption configures the BackoffTrigger.
+type BackoffOption func(*BackoffTrigger)
+
+// WithFullJitter randomizes each interval of the BackoffTrigger in the
+// range [0, interval), using the given random source. A nil source
+// defaults to a time seeded one.
+func WithFullJitter(source rand.Source) BackoffOption {
+	return func(bt *BackoffTrigger) {
+		if source == nil {
+			source = rand.NewSource(time.Now().UnixNano())
+		}
+		bt.rand = rand.New(source)
+	}
+}
+
+// NewBackoffTrigger returns a new BackoffTrigger, whose first interval is
+// initial. Each following interval is multiplied by the factor, capped at
+// max. A non-positive max leaves the intervals uncapped, a factor below 1
+// is treated as 1, and a non-positive maxAttempts does not limit the
+// number of attempts.
+func NewBackoffTrigger(initial, max time.Duration, factor float64, maxAttempts int,
+	opts ...BackoffOption) *BackoffTrigger {
+	if factor < 1 {
+		factor = 1
+	}
+
+	bt := &BackoffTrigger{
+		initial:     initial,
+		max:         max,
+		factor:      factor,
+		maxAttempts: maxAttempts,
+	}
+	for _, opt := range opts {
+		opt(bt)
+	}
+
+	return bt
+}
+
+// NextFireTime returns the next time at which the BackoffTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the maximum number of attempts is reached.
+func (bt *BackoffTrigger) NextFireTime(prev int64) (int64, error) {
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+
+	if bt.maxAttempts > 0 && bt.attempt >= bt.maxAttempts {
+		return 0, ErrTriggerExpired
+	}
+
+	if bt.attempt == 0 {
+		bt.interval = bt.initial
+	} else {
+		next := float64(bt.interval) * bt.factor
+		if next >= math.MaxInt64 {
+			bt.interval = math.MaxInt64
+		} else {
+			bt.interval = time.Duration(next)
+		}
+	}
+	if bt.max > 0 && bt.interval > bt.max {
+		bt.interval = bt.max
+	}
+	bt.attempt++
+
+	delay := bt.interval
+	if bt.rand != nil && delay > 0 {
+		delay = time.Duration(bt.rand.Int63n(int64(delay)))
+	}
+
+	if delay.Nanoseconds() > math.MaxInt64-prev {
+		return math.MaxInt64, nil
+	}
+
+	return prev + delay.Nanoseconds(), nil
+}
+
+// RemainingRepeats returns the number of the attempts left, and false if
+// the number of attempts is not limited.
+func (bt *BackoffTrigger) RemainingRepeats() (int, bool) {
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+
+	if bt.maxAttempts <= 0 {
+		return 0, false
+	}
+
+	return bt.maxAttempts - bt.attempt, true
+}
+
+// EndTime returns false, as the BackoffTrigger has no end time.
+func (bt *BackoffTrigger) EndTime() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Description returns the description of the trigger, including the
+// current attempt number.
+func (bt *BackoffTrigger) Description() string {
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+
+	if bt.maxAttempts > 0 {
+		return fmt.Sprintf("BackoffTrigger attempt %d/%d with interval: %s",
+			bt.attempt, bt.maxAttempts, bt.interval)
+	}
+
+	return fmt.Sprintf("BackoffTrigger attempt %d with interval: %s", bt.attempt, bt.interval)
+}
@@ -0,0 +1,66 @@
+package quartz_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestBackoffTrigger(t *testing.T) {
+	trigger := quartz.NewBackoffTrigger(time.Second, 5*time.Second, 2, 5)
+	assertEqual(t, trigger.Description(), "BackoffTrigger attempt 0/5 with interval: 0s")
+
+	prev := fromEpoch
+	for _, interval := range []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	} {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, time.Duration(next-prev), interval)
+		prev = next
+	}
+	assertEqual(t, trigger.Description(), "BackoffTrigger attempt 5/5 with interval: 5s")
+
+	remaining, limited := trigger.RemainingRepeats()
+	assertEqual(t, remaining, 0)
+	assertEqual(t, limited, true)
+
+	_, err := trigger.NextFireTime(prev)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestBackoffTriggerUnlimited(t *testing.T) {
+	trigger := quartz.NewBackoffTrigger(time.Second, 0, 10, 0)
+
+	prev := fromEpoch
+	for i := 0; i < 100; i++ {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		if next < prev {
+			t.Fatalf("next fire time %d is before the previous one %d", next, prev)
+		}
+		prev = next
+	}
+
+	_, limited := trigger.RemainingRepeats()
+	assertEqual(t, limited, false)
+	assertEqual(t, trigger.Description()[:27], "BackoffTrigger attempt 100 ")
+}
+
+func TestBackoffTriggerFullJitter(t *testing.T) {
+	trigger := quartz.NewBackoffTrigger(time.Second, time.Minute, 2, 6,
+		quartz.WithFullJitter(rand.NewSource(1)))
+
+	expected := rand.New(rand.NewSource(1))
+	prev := fromEpoch
+	interval := time.Second
+	for i := 0; i < 6; i++ {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, next-prev, expected.Int63n(int64(interval)))
+		prev = next
+		interval *= 2
+	}
+}
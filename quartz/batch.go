@@ -0,0 +1,156 @@
+package quartz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JobWithTrigger is a Job to be scheduled using ScheduleJobs.
+type JobWithTrigger struct {
+	// Job is the Job to schedule, identified by its Key.
+	Job Job
+
+	// Trigger is the Trigger of the Job.
+	Trigger Trigger
+
+	// Options are the ScheduleOptions of the Job.
+	Options []ScheduleOption
+}
+
+// JobError is the error of a Job of a batch operation.
+type JobError struct {
+	// Index is the position of the Job in the batch.
+	Index int
+
+	// Key is the JobKey of the Job.
+	Key JobKey
+
+	// Err is the error of the Job.
+	Err error
+}
+
+// Error returns the description of the error.
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job %d (%s): %v", e.Index, e.Key, e.Err)
+}
+
+// Unwrap returns the error of the Job.
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by a batch operation which failed for some of
+// its jobs, while it was applied to the other ones.
+type BatchError struct {
+	// Errors are the errors of the failed jobs, in the order of
+	// the batch.
+	Errors []*JobError
+}
+
+// Error returns the description of the errors.
+func (e *BatchError) Error() string {
+	descriptions := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		descriptions = append(descriptions, err.Error())
+	}
+
+	return fmt.Sprintf("%d jobs failed: %s", len(e.Errors), strings.Join(descriptions, "; "))
+}
+
+// Is reports whether the error of any of the failed jobs matches the
+// target.
+func (e *BatchError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScheduleJobs schedules the jobs under a single lock, waking up the
+// execution loop once. The jobs which fail to schedule, e.g. because of
+// an expired Trigger or an existing key, are reported in a BatchError,
+// while the other jobs are scheduled.
+func (sched *StdScheduler) ScheduleJobs(ctx context.Context, jobs []JobWithTrigger) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var batchErr BatchError
+	var collisions []func(SchedulerListener)
+	scheduled := make([]ScheduledJob, 0, len(jobs))
+	sched.mtx.Lock()
+	for i, entry := range jobs {
+		key := intJobKey(entry.Job.Key())
+		if existing := sched.collision(key, entry.Job); existing != nil {
+			job := entry.Job
+			collisions = append(collisions, func(l SchedulerListener) { l.JobKeyCollision(*existing, job) })
+		}
+		job, err := sched.schedule(key, entry.Job, entry.Trigger, newScheduleOptions(entry.Options))
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: i, Key: key, Err: err})
+			continue
+		}
+		scheduled = append(scheduled, job)
+	}
+	if len(scheduled) > 0 {
+		sched.reportQueueLength()
+		if sched.isRunning() {
+			sched.resetHead()
+		}
+	}
+	sched.mtx.Unlock()
+
+	for _, collision := range collisions {
+		sched.notify(collision)
+	}
+	for _, job := range scheduled {
+		job := job
+		sched.opts.Metrics.IncCounter(MetricJobsScheduled)
+		sched.notify(func(l SchedulerListener) { l.JobScheduled(job) })
+	}
+	if len(batchErr.Errors) > 0 {
+		return &batchErr
+	}
+
+	return nil
+}
+
+// DeleteJobs removes the jobs with the specified keys in a single pass,
+// and returns the number of the removed jobs. The keys which are not
+// scheduled are ignored. The jobs which the JobQueue fails to remove are
+// reported in a BatchError, while the other jobs are removed.
+func (sched *StdScheduler) DeleteJobs(keys ...int) (int, error) {
+	var batchErr BatchError
+	deleted := make([]JobKey, 0, len(keys))
+	sched.mtx.Lock()
+	for i, key := range keys {
+		it := sched.findItem(intJobKey(key))
+		if it == nil {
+			continue
+		}
+		if err := sched.remove(it); err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: i, Key: it.key, Err: err})
+			continue
+		}
+		deleted = append(deleted, it.key)
+	}
+	if len(deleted) > 0 {
+		sched.resetHead()
+	}
+	sched.mtx.Unlock()
+
+	for _, key := range deleted {
+		key := key
+		sched.notify(func(l SchedulerListener) { l.JobDeleted(key) })
+	}
+	if len(batchErr.Errors) > 0 {
+		return len(deleted), &batchErr
+	}
+
+	return len(deleted), nil
+}
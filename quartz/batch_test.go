@@ -0,0 +1,82 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerScheduleJobs(t *testing.T) {
+	ctx := context.Background()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	listener := &recordingListener{}
+	sched.AddListener(listener)
+
+	jobs := make([]quartz.JobWithTrigger, 0, 102)
+	keys := make([]int, 0, 100)
+	for i := 0; i < 100; i++ {
+		job := quartz.NewShellJob(fmt.Sprintf("echo %d", i))
+		jobs = append(jobs, quartz.JobWithTrigger{Job: job, Trigger: quartz.NewSimpleTrigger(time.Minute)})
+		keys = append(keys, job.Key())
+	}
+	duplicate := jobs[0]
+	jobs = append(jobs, duplicate, quartz.JobWithTrigger{
+		Job:     quartz.NewShellJob("ls"),
+		Trigger: quartz.NewSimpleTrigger(time.Minute),
+		Options: []quartz.ScheduleOption{quartz.WithPool("unknown")},
+	})
+
+	err := sched.ScheduleJobs(ctx, jobs)
+	var batchErr *quartz.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, len(batchErr.Errors), 2)
+	assertEqual(t, batchErr.Errors[0].Index, 100)
+	assertEqual(t, errors.Is(batchErr.Errors[0], quartz.ErrJobAlreadyExists), true)
+	assertEqual(t, batchErr.Errors[1].Index, 101)
+	assertEqual(t, errors.Is(err, quartz.ErrPoolNotFound), true)
+	assertEqual(t, sched.JobCount(), 100)
+	assertEqual(t, len(listener.scheduled), 100)
+
+	// the keys which are not scheduled are ignored
+	deleted, err := sched.DeleteJobs(append(keys[:50:50], quartz.NewShellJob("ls").Key())...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, deleted, 50)
+	assertEqual(t, sched.JobCount(), 50)
+	assertEqual(t, len(listener.deleted), 50)
+
+	if err := sched.ScheduleJobs(ctx, jobs[:50]); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sched.JobCount(), 100)
+}
+
+func BenchmarkScheduleJobs(b *testing.B) {
+	ctx := context.Background()
+	jobs := make([]quartz.JobWithTrigger, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		jobs = append(jobs, quartz.JobWithTrigger{
+			Job:     quartz.NewShellJob(fmt.Sprintf("echo %d", i)),
+			Trigger: quartz.NewSimpleTrigger(time.Hour),
+		})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+			Logger: quartz.NewNoopLogger(),
+		})
+		if err := sched.ScheduleJobs(ctx, jobs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package quartz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BusinessHoursTrigger implements the quartz.Trigger interface; fires at
+// a fixed interval within a daily time window, on the given days of the
+// week, e.g. every 15 minutes Monday to Friday from 08:00 to 18:00.
+//
+// The fire times are counted from the opening of each window, which
+// follows the wall clock of the location across the daylight saving
+// transitions. A window opening at a skipped wall clock time opens at
+// the moment of the transition. Outside of the windows, the next fire
+// time is the next window opening.
+//
+// A window ending before its start spans midnight, and belongs to the
+// day of the week on which it opens.
+type BusinessHoursTrigger struct {
+	Interval time.Duration
+	weekdays [7]bool
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+	limits   triggerLimits
+}
+
+// Verify BusinessHoursTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*BusinessHoursTrigger)(nil)
+
+// Verify BusinessHoursTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*BusinessHoursTrigger)(nil)
+
+// NewBusinessHoursTrigger returns a new BusinessHoursTrigger firing at the
+// interval within the [start, end) window of the given days of the week.
+// The window bounds are given as the wall clock offsets from midnight in
+// the location, a nil location defaults to UTC. The options can limit the
+// BusinessHoursTrigger by an end time or a repeat count.
+func NewBusinessHoursTrigger(interval time.Duration, weekdays []time.Weekday, start, end time.Duration,
+	location *time.Location, opts ...TriggerOption) (*BusinessHoursTrigger, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid business hours trigger interval: %s", interval)
+	}
+	if len(weekdays) == 0 {
+		return nil, errors.New("empty business hours weekday mask")
+	}
+
+	day := 24 * time.Hour
+	if start < 0 || start > day || end < 0 || end > day || start == end {
+		return nil, fmt.Errorf("invalid business hours window: %s-%s", start, end)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	trigger := &BusinessHoursTrigger{
+		Interval: interval,
+		start:    start,
+		end:      end,
+		location: location,
+		limits:   newTriggerLimits(opts),
+	}
+	for _, weekday := range weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return nil, fmt.Errorf("invalid business hours weekday: %d", weekday)
+		}
+		trigger.weekdays[weekday] = true
+	}
+
+	return trigger, nil
+}
+
+// NextFireTime returns the next time at which the BusinessHoursTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the limits of the BusinessHoursTrigger are exhausted.
+func (bt *BusinessHoursTrigger) NextFireTime(prev int64) (int64, error) {
+	return bt.limits.nextFireTime(bt.next, prev)
+}
+
+// NextN returns the next n fire times of the BusinessHoursTrigger following from.
+// If the BusinessHoursTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (bt *BusinessHoursTrigger) NextN(from int64, n int) ([]int64, error) {
+	return bt.limits.nextN(bt.next, from, n)
+}
+
+// Location returns the location of the BusinessHoursTrigger.
+func (bt *BusinessHoursTrigger) Location() *time.Location {
+	return bt.location
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (bt *BusinessHoursTrigger) RemainingRepeats() (int, bool) {
+	return bt.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the BusinessHoursTrigger, and false if it has none.
+func (bt *BusinessHoursTrigger) EndTime() (time.Time, bool) {
+	return bt.limits.end()
+}
+
+func (bt *BusinessHoursTrigger) next(prev int64) (int64, error) {
+	t := time.Unix(0, prev).In(bt.location)
+	year, month, day := t.Date()
+
+	// start from the previous day, whose window may span midnight
+	for i := -1; i <= 7; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, time.UTC)
+		if !bt.weekdays[date.Weekday()] {
+			continue
+		}
+
+		open := resolveWallTime(date.Add(bt.start), bt.location).UnixNano()
+		closeDate := date
+		if bt.end < bt.start {
+			closeDate = date.AddDate(0, 0, 1)
+		}
+		closing := resolveWallTime(closeDate.Add(bt.end), bt.location).UnixNano()
+
+		if prev < open {
+			return open, nil
+		}
+		if next := open + ((prev-open)/int64(bt.Interval)+1)*int64(bt.Interval); next < closing {
+			return next, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no business hours window following %s", t)
+}
+
+// Description returns the description of the trigger.
+func (bt *BusinessHoursTrigger) Description() string {
+	weekdays := make([]string, 0, len(bt.weekdays))
+	for weekday, ok := range bt.weekdays {
+		if ok {
+			weekdays = append(weekdays, time.Weekday(weekday).String()[:3])
+		}
+	}
+
+	return fmt.Sprintf("BusinessHoursTrigger with interval: %s %s %s-%s %s", bt.Interval,
+		strings.Join(weekdays, ","), clockOffset(bt.start), clockOffset(bt.end), bt.location)
+}
+
+// clockOffset formats the offset from midnight as a wall clock time.
+func clockOffset(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
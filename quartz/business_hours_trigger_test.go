@@ -0,0 +1,195 @@
+package quartz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestBusinessHoursTrigger(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	everyDay := append([]time.Weekday{time.Sunday, time.Saturday}, weekdays...)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		weekdays []time.Weekday
+		start    time.Duration
+		end      time.Duration
+		prev     time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "inside the window",
+			interval: 15 * time.Minute,
+			weekdays: weekdays,
+			start:    8 * time.Hour,
+			end:      18 * time.Hour,
+			prev:     time.Date(2024, 6, 7, 17, 40, 0, 0, berlin), // Friday
+			expected: []time.Time{
+				time.Date(2024, 6, 7, 17, 45, 0, 0, berlin),
+				time.Date(2024, 6, 10, 8, 0, 0, 0, berlin),
+				time.Date(2024, 6, 10, 8, 15, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "before the window",
+			interval: 15 * time.Minute,
+			weekdays: weekdays,
+			start:    8 * time.Hour,
+			end:      18 * time.Hour,
+			prev:     time.Date(2024, 6, 10, 6, 0, 0, 0, berlin), // Monday
+			expected: []time.Time{
+				time.Date(2024, 6, 10, 8, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "on the weekend",
+			interval: 15 * time.Minute,
+			weekdays: weekdays,
+			start:    8 * time.Hour,
+			end:      18 * time.Hour,
+			prev:     time.Date(2024, 6, 8, 12, 0, 0, 0, berlin), // Saturday
+			expected: []time.Time{
+				time.Date(2024, 6, 10, 8, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "interval not dividing the hour",
+			interval: 25 * time.Minute,
+			weekdays: everyDay,
+			start:    8 * time.Hour,
+			end:      9 * time.Hour,
+			prev:     time.Date(2024, 6, 10, 7, 0, 0, 0, berlin),
+			expected: []time.Time{
+				time.Date(2024, 6, 10, 8, 0, 0, 0, berlin),
+				time.Date(2024, 6, 10, 8, 25, 0, 0, berlin),
+				time.Date(2024, 6, 10, 8, 50, 0, 0, berlin),
+				time.Date(2024, 6, 11, 8, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "window spanning midnight",
+			interval: time.Hour,
+			weekdays: []time.Weekday{time.Friday},
+			start:    22 * time.Hour,
+			end:      2 * time.Hour,
+			prev:     time.Date(2024, 6, 7, 21, 0, 0, 0, berlin),
+			expected: []time.Time{
+				time.Date(2024, 6, 7, 22, 0, 0, 0, berlin),
+				time.Date(2024, 6, 7, 23, 0, 0, 0, berlin),
+				time.Date(2024, 6, 8, 0, 0, 0, 0, berlin),
+				time.Date(2024, 6, 8, 1, 0, 0, 0, berlin),
+				time.Date(2024, 6, 14, 22, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "inside a window spanning midnight",
+			interval: time.Hour,
+			weekdays: []time.Weekday{time.Friday},
+			start:    22 * time.Hour,
+			end:      2 * time.Hour,
+			prev:     time.Date(2024, 6, 8, 0, 30, 0, 0, berlin),
+			expected: []time.Time{
+				time.Date(2024, 6, 8, 1, 0, 0, 0, berlin),
+				time.Date(2024, 6, 14, 22, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "window following the DST shift",
+			interval: 4 * time.Hour,
+			weekdays: everyDay,
+			start:    8 * time.Hour,
+			end:      13 * time.Hour,
+			prev:     time.Date(2024, 3, 30, 9, 0, 0, 0, berlin),
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 8, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 12, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "window opening at a skipped time",
+			interval: 30 * time.Minute,
+			weekdays: everyDay,
+			start:    2*time.Hour + 30*time.Minute,
+			end:      4 * time.Hour,
+			prev:     time.Date(2024, 3, 31, 0, 0, 0, 0, berlin),
+			expected: []time.Time{
+				time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 3, 30, 0, 0, berlin),
+				time.Date(2024, 4, 1, 2, 30, 0, 0, berlin),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			trigger, err := quartz.NewBusinessHoursTrigger(test.interval, test.weekdays,
+				test.start, test.end, berlin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next, err := trigger.NextN(test.prev.UnixNano(), len(test.expected))
+			assertEqual(t, err, nil)
+			for i, expected := range test.expected {
+				assertEqual(t, time.Unix(0, next[i]).In(berlin).String(), expected.String())
+			}
+		})
+	}
+}
+
+func TestBusinessHoursTriggerDescription(t *testing.T) {
+	trigger, err := quartz.NewBusinessHoursTrigger(15*time.Minute,
+		[]time.Weekday{time.Friday, time.Monday}, 8*time.Hour, 18*time.Hour+30*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, trigger.Description(),
+		"BusinessHoursTrigger with interval: 15m0s Mon,Fri 08:00-18:30 UTC")
+}
+
+func TestBusinessHoursTriggerRepeatCount(t *testing.T) {
+	trigger, err := quartz.NewBusinessHoursTrigger(time.Hour, []time.Weekday{time.Monday},
+		9*time.Hour, 17*time.Hour, nil, quartz.WithRepeatCount(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := trigger.NextN(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC).UnixNano(), 3)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+	assertEqual(t, len(next), 2)
+}
+
+func TestBusinessHoursTriggerError(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday}
+	tests := []struct {
+		name     string
+		interval time.Duration
+		weekdays []time.Weekday
+		start    time.Duration
+		end      time.Duration
+	}{
+		{"empty mask", time.Minute, nil, 8 * time.Hour, 18 * time.Hour},
+		{"invalid weekday", time.Minute, []time.Weekday{7}, 8 * time.Hour, 18 * time.Hour},
+		{"zero interval", 0, weekdays, 8 * time.Hour, 18 * time.Hour},
+		{"empty window", time.Minute, weekdays, 8 * time.Hour, 8 * time.Hour},
+		{"negative start", time.Minute, weekdays, -time.Hour, 8 * time.Hour},
+		{"end after midnight", time.Minute, weekdays, 8 * time.Hour, 25 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			_, err := quartz.NewBusinessHoursTrigger(test.interval, test.weekdays,
+				test.start, test.end, nil)
+			assertNotEqual(t, err, nil)
+		})
+	}
+}
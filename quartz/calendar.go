@@ -0,0 +1,218 @@
+package quartz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCalendarExhausted is returned by the CalendarTrigger when no fire
+// time outside of the Calendar exclusions is found.
+var ErrCalendarExhausted = errors.New("no fire time outside of the calendar exclusions")
+
+// calendarMaxIterations bounds the number of the excluded fire times
+// skipped by a single NextFireTime call of the CalendarTrigger.
+const calendarMaxIterations = 100000
+
+// Calendar excludes instants from the fire times of a Trigger.
+type Calendar interface {
+	// IsExcluded checks if the instant is excluded by the Calendar.
+	IsExcluded(t time.Time) bool
+}
+
+// DateCalendar implements the quartz.Calendar interface; excludes a
+// static list of dates, e.g. public holidays.
+type DateCalendar struct {
+	dates    map[date]struct{}
+	location *time.Location
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// Verify DateCalendar satisfies the Calendar interface.
+var _ Calendar = (*DateCalendar)(nil)
+
+// NewDateCalendar returns a new DateCalendar excluding the whole days of
+// the given dates. The instants are checked in the given location, a nil
+// location defaults to UTC.
+func NewDateCalendar(location *time.Location, dates ...time.Time) *DateCalendar {
+	if location == nil {
+		location = time.UTC
+	}
+
+	excluded := make(map[date]struct{}, len(dates))
+	for _, t := range dates {
+		year, month, day := t.Date()
+		excluded[date{year, month, day}] = struct{}{}
+	}
+
+	return &DateCalendar{
+		dates:    excluded,
+		location: location,
+	}
+}
+
+// IsExcluded checks if the instant falls on one of the excluded dates.
+func (c *DateCalendar) IsExcluded(t time.Time) bool {
+	year, month, day := t.In(c.location).Date()
+	_, ok := c.dates[date{year, month, day}]
+	return ok
+}
+
+// excludedUntil returns the start of the day following the excluded
+// instant.
+func (c *DateCalendar) excludedUntil(t time.Time) time.Time {
+	year, month, day := t.In(c.location).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, c.location)
+}
+
+// DailyCalendar implements the quartz.Calendar interface; excludes a
+// time range within each day, e.g. a nightly maintenance window.
+type DailyCalendar struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+// Verify DailyCalendar satisfies the Calendar interface.
+var _ Calendar = (*DailyCalendar)(nil)
+
+// NewDailyCalendar returns a new DailyCalendar excluding the [start, end)
+// range of each day, given as the offsets from midnight. The range wraps
+// around midnight if end is before start. The instants are checked in the
+// given location, a nil location defaults to UTC.
+func NewDailyCalendar(start, end time.Duration, location *time.Location) (*DailyCalendar, error) {
+	day := 24 * time.Hour
+	if start < 0 || start > day || end < 0 || end > day {
+		return nil, fmt.Errorf("invalid daily range: %s-%s", start, end)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &DailyCalendar{
+		start:    start,
+		end:      end,
+		location: location,
+	}, nil
+}
+
+// IsExcluded checks if the time of day of the instant falls within the
+// excluded range.
+func (c *DailyCalendar) IsExcluded(t time.Time) bool {
+	offset := timeOfDay(t.In(c.location))
+	if c.start <= c.end {
+		return offset >= c.start && offset < c.end
+	}
+
+	return offset >= c.start || offset < c.end
+}
+
+// excludedUntil returns the end of the excluded range containing the
+// excluded instant.
+func (c *DailyCalendar) excludedUntil(t time.Time) time.Time {
+	t = t.In(c.location)
+	year, month, day := t.Date()
+	if c.start > c.end && timeOfDay(t) >= c.start {
+		// the range wraps around midnight
+		day++
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, int(c.end), c.location)
+}
+
+// timeOfDay returns the offset of the instant from midnight.
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// CompositeCalendar implements the quartz.Calendar interface; excludes
+// the instants excluded by any of the stacked calendars.
+type CompositeCalendar struct {
+	calendars []Calendar
+}
+
+// Verify CompositeCalendar satisfies the Calendar interface.
+var _ Calendar = (*CompositeCalendar)(nil)
+
+// NewCompositeCalendar returns a new CompositeCalendar stacking the given
+// calendars.
+func NewCompositeCalendar(calendars ...Calendar) *CompositeCalendar {
+	return &CompositeCalendar{
+		calendars: calendars,
+	}
+}
+
+// IsExcluded checks if the instant is excluded by any of the calendars.
+func (c *CompositeCalendar) IsExcluded(t time.Time) bool {
+	for _, calendar := range c.calendars {
+		if calendar.IsExcluded(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CalendarTrigger implements the quartz.Trigger interface; wraps a Trigger,
+// skipping its fire times excluded by the Calendar. The skipped fire times
+// count towards the repeat count of the wrapped Trigger.
+type CalendarTrigger struct {
+	trigger  Trigger
+	calendar Calendar
+}
+
+// Verify CalendarTrigger satisfies the Trigger interface.
+var _ Trigger = (*CalendarTrigger)(nil)
+
+// Verify CalendarTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*CalendarTrigger)(nil)
+
+// NewCalendarTrigger returns a new CalendarTrigger wrapping the given
+// Trigger.
+func NewCalendarTrigger(trigger Trigger, calendar Calendar) *CalendarTrigger {
+	return &CalendarTrigger{
+		trigger:  trigger,
+		calendar: calendar,
+	}
+}
+
+// NextFireTime returns the next time at which the CalendarTrigger is scheduled to fire.
+// ErrCalendarExhausted is returned if the Calendar excludes all of the fire times
+// checked.
+func (ct *CalendarTrigger) NextFireTime(prev int64) (int64, error) {
+	next := prev
+	for i := 0; i < calendarMaxIterations; i++ {
+		var err error
+		next, err = ct.trigger.NextFireTime(next)
+		if err != nil {
+			return 0, err
+		}
+		if !ct.calendar.IsExcluded(time.Unix(0, next)) {
+			return next, nil
+		}
+	}
+
+	return 0, ErrCalendarExhausted
+}
+
+// RemainingRepeats returns the remaining repeats of the wrapped Trigger.
+func (ct *CalendarTrigger) RemainingRepeats() (int, bool) {
+	return remainingRepeats(ct.trigger)
+}
+
+// EndTime returns the end time of the wrapped Trigger, and false if it
+// has none.
+func (ct *CalendarTrigger) EndTime() (time.Time, bool) {
+	return triggerEndTime(ct.trigger)
+}
+
+// Description returns the description of the trigger.
+func (ct *CalendarTrigger) Description() string {
+	return fmt.Sprintf("%s with calendar", ct.trigger.Description())
+}
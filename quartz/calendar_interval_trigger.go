@@ -0,0 +1,245 @@
+package quartz
+
+import (
+	"fmt"
+	"time"
+)
+
+// MonthlyTrigger implements the quartz.Trigger interface; fires on a day
+// of every month at a wall clock time in its location.
+//
+// The months are advanced on the civil calendar, so the wall clock time
+// is kept across the daylight saving transitions. A fire time skipped by
+// a transition fires at the moment of the transition.
+type MonthlyTrigger struct {
+	DayOfMonth int
+	At         time.Duration
+
+	// SkipShortMonths makes the MonthlyTrigger skip the months which
+	// are shorter than the DayOfMonth. Otherwise, the day is clamped to
+	// the last day of the short months, e.g. 31 fires on April 30.
+	SkipShortMonths bool
+
+	location *time.Location
+	limits   triggerLimits
+}
+
+// Verify MonthlyTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*MonthlyTrigger)(nil)
+
+// Verify MonthlyTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*MonthlyTrigger)(nil)
+
+// NewMonthlyTrigger returns a new MonthlyTrigger firing on the day of the
+// month, at the given offset from midnight in the location. A nil location
+// defaults to UTC. The options can limit the MonthlyTrigger by an end time
+// or a repeat count.
+func NewMonthlyTrigger(dayOfMonth int, at time.Duration, location *time.Location,
+	opts ...TriggerOption) (*MonthlyTrigger, error) {
+	if !inScope(dayOfMonth, 1, 31) {
+		return nil, fmt.Errorf("invalid monthly trigger day: %d", dayOfMonth)
+	}
+	if at < 0 || at >= 24*time.Hour {
+		return nil, fmt.Errorf("invalid monthly trigger time: %s", at)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &MonthlyTrigger{
+		DayOfMonth: dayOfMonth,
+		At:         at,
+		location:   location,
+		limits:     newTriggerLimits(opts),
+	}, nil
+}
+
+// NextFireTime returns the next time at which the MonthlyTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the limits of the MonthlyTrigger are exhausted.
+func (mt *MonthlyTrigger) NextFireTime(prev int64) (int64, error) {
+	return mt.limits.nextFireTime(mt.next, prev)
+}
+
+// NextN returns the next n fire times of the MonthlyTrigger following from.
+// If the MonthlyTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (mt *MonthlyTrigger) NextN(from int64, n int) ([]int64, error) {
+	return mt.limits.nextN(mt.next, from, n)
+}
+
+// Location returns the location of the MonthlyTrigger.
+func (mt *MonthlyTrigger) Location() *time.Location {
+	return mt.location
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (mt *MonthlyTrigger) RemainingRepeats() (int, bool) {
+	return mt.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the MonthlyTrigger, and false if it has none.
+func (mt *MonthlyTrigger) EndTime() (time.Time, bool) {
+	return mt.limits.end()
+}
+
+func (mt *MonthlyTrigger) next(prev int64) (int64, error) {
+	year, month, _ := time.Unix(0, prev).In(mt.location).Date()
+
+	// a day of the month is found within every two months
+	for i := 0; i <= 2; i++ {
+		first := time.Date(year, month+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		day := mt.DayOfMonth
+		if days := daysIn(first.Year(), first.Month()); day > days {
+			if mt.SkipShortMonths {
+				continue
+			}
+			day = days
+		}
+
+		wall := first.AddDate(0, 0, day-1).Add(mt.At)
+		if next := resolveWallTime(wall, mt.location).UnixNano(); next > prev {
+			return next, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no monthly trigger fire time following %d", prev)
+}
+
+// Description returns the description of the trigger.
+func (mt *MonthlyTrigger) Description() string {
+	return fmt.Sprintf("MonthlyTrigger on day %d at %s %s", mt.DayOfMonth, clockOffset(mt.At), mt.location)
+}
+
+// WeeklyTrigger implements the quartz.Trigger interface; fires on a day of
+// the week every n weeks, at a wall clock time in its location. The weeks
+// are counted from the first fire time of the WeeklyTrigger.
+//
+// The weeks are advanced on the civil calendar, so the wall clock time is
+// kept across the daylight saving transitions. A fire time skipped by a
+// transition fires at the moment of the transition.
+type WeeklyTrigger struct {
+	Weekday     time.Weekday
+	At          time.Duration
+	EveryNWeeks int
+
+	location *time.Location
+	limits   triggerLimits
+	anchor   time.Time // the date of the first fire time
+}
+
+// Verify WeeklyTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*WeeklyTrigger)(nil)
+
+// Verify WeeklyTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*WeeklyTrigger)(nil)
+
+// NewWeeklyTrigger returns a new WeeklyTrigger firing on the day of the week
+// every n weeks, at the given offset from midnight in the location. A nil
+// location defaults to UTC. The options can limit the WeeklyTrigger by an
+// end time or a repeat count.
+func NewWeeklyTrigger(weekday time.Weekday, at time.Duration, everyNWeeks int,
+	location *time.Location, opts ...TriggerOption) (*WeeklyTrigger, error) {
+	if weekday < time.Sunday || weekday > time.Saturday {
+		return nil, fmt.Errorf("invalid weekly trigger weekday: %d", weekday)
+	}
+	if at < 0 || at >= 24*time.Hour {
+		return nil, fmt.Errorf("invalid weekly trigger time: %s", at)
+	}
+	if everyNWeeks < 1 {
+		return nil, fmt.Errorf("invalid weekly trigger interval: %d", everyNWeeks)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &WeeklyTrigger{
+		Weekday:     weekday,
+		At:          at,
+		EveryNWeeks: everyNWeeks,
+		location:    location,
+		limits:      newTriggerLimits(opts),
+	}, nil
+}
+
+// NextFireTime returns the next time at which the WeeklyTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the limits of the WeeklyTrigger are exhausted.
+func (wt *WeeklyTrigger) NextFireTime(prev int64) (int64, error) {
+	if wt.anchor.IsZero() {
+		wt.anchor = wt.firstDate(prev)
+	}
+
+	return wt.limits.nextFireTime(wt.nextFunc(wt.anchor), prev)
+}
+
+// NextN returns the next n fire times of the WeeklyTrigger following from.
+// If the WeeklyTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (wt *WeeklyTrigger) NextN(from int64, n int) ([]int64, error) {
+	anchor := wt.anchor
+	if anchor.IsZero() {
+		anchor = wt.firstDate(from)
+	}
+
+	return wt.limits.nextN(wt.nextFunc(anchor), from, n)
+}
+
+// Location returns the location of the WeeklyTrigger.
+func (wt *WeeklyTrigger) Location() *time.Location {
+	return wt.location
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (wt *WeeklyTrigger) RemainingRepeats() (int, bool) {
+	return wt.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the WeeklyTrigger, and false if it has none.
+func (wt *WeeklyTrigger) EndTime() (time.Time, bool) {
+	return wt.limits.end()
+}
+
+// firstDate returns the date of the first fire time following prev, as a
+// UTC midnight.
+func (wt *WeeklyTrigger) firstDate(prev int64) time.Time {
+	year, month, day := time.Unix(0, prev).In(wt.location).Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	date = date.AddDate(0, 0, (int(wt.Weekday)-int(date.Weekday())+7)%7)
+	if wt.fireTime(date) <= prev {
+		date = date.AddDate(0, 0, 7)
+	}
+
+	return date
+}
+
+// fireTime returns the fire time on the date, given as a UTC midnight.
+func (wt *WeeklyTrigger) fireTime(date time.Time) int64 {
+	return resolveWallTime(date.Add(wt.At), wt.location).UnixNano()
+}
+
+// nextFunc returns the function computing the next fire time, counting
+// the weeks from the anchor date.
+func (wt *WeeklyTrigger) nextFunc(anchor time.Time) func(int64) (int64, error) {
+	return func(prev int64) (int64, error) {
+		year, month, day := time.Unix(0, prev).In(wt.location).Date()
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+		period := 7 * wt.EveryNWeeks
+		var periods int
+		if date.After(anchor) {
+			periods = int(date.Sub(anchor).Hours()/24) / period
+		}
+		for i := periods; i <= periods+1; i++ {
+			if next := wt.fireTime(anchor.AddDate(0, 0, i*period)); next > prev {
+				return next, nil
+			}
+		}
+
+		return 0, fmt.Errorf("no weekly trigger fire time following %d", prev)
+	}
+}
+
+// Description returns the description of the trigger.
+func (wt *WeeklyTrigger) Description() string {
+	return fmt.Sprintf("WeeklyTrigger on %s at %s every %d weeks %s", wt.Weekday,
+		clockOffset(wt.At), wt.EveryNWeeks, wt.location)
+}
@@ -0,0 +1,157 @@
+package quartz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func assertFireTimes(t *testing.T, trigger quartz.PreviewTrigger, from time.Time, expected ...time.Time) {
+	t.Helper()
+	next, err := trigger.NextN(from.UnixNano(), len(expected))
+	assertEqual(t, err, nil)
+	for i, fireTime := range next {
+		assertEqual(t, time.Unix(0, fireTime).In(expected[i].Location()).String(), expected[i].String())
+	}
+}
+
+func TestMonthlyTrigger(t *testing.T) {
+	trigger, err := quartz.NewMonthlyTrigger(31, 6*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, trigger.Description(), "MonthlyTrigger on day 31 at 06:00 UTC")
+
+	from := time.Date(2024, 1, 31, 6, 0, 0, 0, time.UTC)
+	assertFireTimes(t, trigger, from.Add(-time.Second),
+		time.Date(2024, 1, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 6, 0, 0, 0, time.UTC),
+	)
+
+	trigger.SkipShortMonths = true
+	assertFireTimes(t, trigger, from,
+		time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 8, 31, 6, 0, 0, 0, time.UTC),
+	)
+
+	trigger, err = quartz.NewMonthlyTrigger(29, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFireTimes(t, trigger, time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 29, 0, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestMonthlyTriggerDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the wall clock time is kept across the transitions
+	trigger, err := quartz.NewMonthlyTrigger(1, 6*time.Hour, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFireTimes(t, trigger, time.Date(2024, 2, 15, 0, 0, 0, 0, loc),
+		time.Date(2024, 3, 1, 6, 0, 0, 0, loc),
+		time.Date(2024, 4, 1, 6, 0, 0, 0, loc),
+	)
+
+	// the skipped time fires at the moment of the transition
+	trigger, err = quartz.NewMonthlyTrigger(10, 2*time.Hour+30*time.Minute, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFireTimes(t, trigger, time.Date(2024, 3, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, 3, 10, 3, 0, 0, 0, loc),
+		time.Date(2024, 4, 10, 2, 30, 0, 0, loc),
+	)
+}
+
+func TestWeeklyTrigger(t *testing.T) {
+	trigger, err := quartz.NewWeeklyTrigger(time.Monday, 9*time.Hour, 6, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, trigger.Description(), "WeeklyTrigger on Monday at 09:00 every 6 weeks UTC")
+
+	from := time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC) // Wednesday
+	expected := []time.Time{
+		time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 22, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC),
+	}
+	assertFireTimes(t, trigger, from, expected...)
+
+	prev := from.UnixNano()
+	for _, fireTime := range expected {
+		prev, err = trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, time.Unix(0, prev).UTC(), fireTime)
+	}
+
+	// the weeks are counted from the first fire time
+	assertFireTimes(t, trigger, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 10, 14, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestWeeklyTriggerSameDay(t *testing.T) {
+	trigger, err := quartz.NewWeeklyTrigger(time.Monday, 9*time.Hour, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFireTimes(t, trigger, time.Date(2024, 6, 10, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC),
+	)
+	assertFireTimes(t, trigger, time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 17, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestWeeklyTriggerDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trigger, err := quartz.NewWeeklyTrigger(time.Sunday, 2*time.Hour+30*time.Minute, 1, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFireTimes(t, trigger, time.Date(2024, 3, 20, 0, 0, 0, 0, loc),
+		time.Date(2024, 3, 24, 2, 30, 0, 0, loc),
+		time.Date(2024, 3, 31, 3, 0, 0, 0, loc),
+		time.Date(2024, 4, 7, 2, 30, 0, 0, loc),
+	)
+	assertFireTimes(t, trigger, time.Date(2024, 10, 20, 3, 0, 0, 0, loc),
+		time.Date(2024, 10, 27, 2, 30, 0, 0, time.FixedZone("CEST", 2*60*60)).In(loc),
+		time.Date(2024, 11, 3, 2, 30, 0, 0, loc),
+	)
+}
+
+func TestCalendarIntervalTriggerError(t *testing.T) {
+	_, err := quartz.NewMonthlyTrigger(0, 0, nil)
+	assertNotEqual(t, err, nil)
+	_, err = quartz.NewMonthlyTrigger(32, 0, nil)
+	assertNotEqual(t, err, nil)
+	_, err = quartz.NewMonthlyTrigger(1, 24*time.Hour, nil)
+	assertNotEqual(t, err, nil)
+
+	_, err = quartz.NewWeeklyTrigger(7, 0, 1, nil)
+	assertNotEqual(t, err, nil)
+	_, err = quartz.NewWeeklyTrigger(time.Monday, -time.Hour, 1, nil)
+	assertNotEqual(t, err, nil)
+	_, err = quartz.NewWeeklyTrigger(time.Monday, 0, 0, nil)
+	assertNotEqual(t, err, nil)
+}
@@ -0,0 +1,104 @@
+package quartz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestDateCalendar(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := quartz.NewDateCalendar(loc,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+	)
+
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 12, 25, 23, 0, 0, 0, loc)), true)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 12, 26, 0, 0, 0, 0, loc)), false)
+	// 2024-01-01 03:00 UTC is still 2023-12-31 in New York
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)), false)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)), true)
+}
+
+func TestDailyCalendar(t *testing.T) {
+	calendar, err := quartz.NewDailyCalendar(time.Hour, 3*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 0, 59, 59, 0, time.UTC)), false)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)), true)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 2, 59, 59, 0, time.UTC)), true)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)), false)
+
+	// the range wraps around midnight
+	calendar, err = quartz.NewDailyCalendar(23*time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 22, 59, 0, 0, time.UTC)), false)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)), true)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 2, 1, 30, 0, 0, time.UTC)), true)
+	assertEqual(t, calendar.IsExcluded(time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)), false)
+
+	_, err = quartz.NewDailyCalendar(-time.Hour, time.Hour, nil)
+	assertNotEqual(t, err, nil)
+	_, err = quartz.NewDailyCalendar(time.Hour, 25*time.Hour, nil)
+	assertNotEqual(t, err, nil)
+}
+
+func TestCalendarTrigger(t *testing.T) {
+	holidays := quartz.NewDateCalendar(nil,
+		time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC),
+	)
+	cronTrigger, err := quartz.NewCronTrigger("0 0 9 * * ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := quartz.NewCalendarTrigger(cronTrigger, holidays)
+	assertEqual(t, trigger.Description(), "CronTrigger 0 0 9 * * ? UTC with calendar")
+
+	prev := time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC).UnixNano()
+	next, err := trigger.NextFireTime(prev)
+	assertEqual(t, err, nil)
+	assertEqual(t, time.Unix(0, next).UTC(), time.Date(2024, 12, 27, 9, 0, 0, 0, time.UTC))
+
+	// the stacked calendars
+	maintenance, err := quartz.NewDailyCalendar(time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger = quartz.NewCalendarTrigger(quartz.NewSimpleTrigger(30*time.Minute),
+		quartz.NewCompositeCalendar(holidays, maintenance))
+
+	prev = time.Date(2024, 12, 23, 0, 30, 0, 0, time.UTC).UnixNano()
+	next, err = trigger.NextFireTime(prev)
+	assertEqual(t, err, nil)
+	assertEqual(t, time.Unix(0, next).UTC(), time.Date(2024, 12, 23, 2, 0, 0, 0, time.UTC))
+
+	prev = time.Date(2024, 12, 24, 23, 30, 0, 0, time.UTC).UnixNano()
+	next, err = trigger.NextFireTime(prev)
+	assertEqual(t, err, nil)
+	assertEqual(t, time.Unix(0, next).UTC(), time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC))
+}
+
+func TestCalendarTriggerExhausted(t *testing.T) {
+	always, err := quartz.NewDailyCalendar(0, 24*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := quartz.NewCalendarTrigger(quartz.NewSimpleTrigger(time.Minute), always)
+
+	_, err = trigger.NextFireTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	assertEqual(t, err, quartz.ErrCalendarExhausted)
+
+	// the errors of the wrapped trigger are returned as is
+	trigger = quartz.NewCalendarTrigger(quartz.NewSimpleTrigger(time.Minute,
+		quartz.WithRepeatCount(3)), always)
+	_, err = trigger.NextFireTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
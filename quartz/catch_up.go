@@ -0,0 +1,157 @@
+package quartz
+
+import "sort"
+
+// catchUpAll catches up the fires of the scheduled jobs missed while the
+// scheduler was not running, and returns the catch-up fires in the order
+// of their scheduled times. The caller must hold the lock.
+func (sched *StdScheduler) catchUpAll() []*fire {
+	var fires []*fire
+	now := sched.nowNano()
+	for _, it := range sched.items() {
+		fires = append(fires, sched.catchUp(it, now)...)
+	}
+
+	return sortFires(fires)
+}
+
+// catchUp returns the catch-up fires of the item according to its
+// CatchUpPolicy, for the fires recorded while it was paused, and for
+// the fires missed until now, which are found by advancing its Trigger.
+// The caller must hold the lock.
+func (sched *StdScheduler) catchUp(it *item, now int64) []*fire {
+	policy := it.opts.catchUp
+	if policy == CatchUpDisabled || it.paused || it.removed {
+		return nil
+	}
+
+	missed := it.missed
+	it.missed = nil
+	_, inflight := sched.inflight[it]
+	if !inflight && !it.startNow && !it.triggerRetry && it.priority <= now {
+		missed = append(missed, sched.advanceMissed(it, now)...)
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case CatchUpSkip:
+		sched.opts.Logger.Info("Skipping the missed Job fires",
+			"key", it.key,
+			"missed", len(missed),
+		)
+		return nil
+	case CatchUpOnce:
+		sched.opts.Logger.Info("Catching up the missed Job fires once",
+			"key", it.key,
+			"missed", len(missed),
+		)
+		missed = missed[len(missed)-1:]
+	default:
+		sched.opts.Logger.Info("Catching up the missed Job fires",
+			"key", it.key,
+			"missed", len(missed),
+		)
+	}
+
+	fires := make([]*fire, 0, len(missed))
+	for _, fireTime := range missed {
+		job := it.scheduledJob()
+		job.NextRunTime = fireTime
+		f := newFire(it, job)
+		f.catchUp = true
+		fires = append(fires, f)
+	}
+
+	return fires
+}
+
+// advanceMissed advances the Trigger of the queued item from its fire
+// time to the first fire time after now, and returns the missed fire
+// times. Once the Trigger fails, or the Job expires, the last missed fire
+// is left to the execution loop, which handles the error of the Trigger. The caller
+// must hold the lock.
+func (sched *StdScheduler) advanceMissed(it *item, now int64) []int64 {
+	if err := sched.dequeue(it); err != nil {
+		sched.queueFailed("remove", err, "key", it.key)
+		return nil
+	}
+
+	var missed []int64
+	fireTime, prev := it.priority, it.priority
+	if it.held != 0 {
+		fireTime, prev = it.held, it.held
+		it.held = 0
+	}
+	if it.jitterBase != 0 {
+		// the Trigger is advanced from its own fire time
+		prev = it.jitterBase
+	}
+	for {
+		next, err := it.Trigger.NextFireTime(prev)
+		if err == nil && it.opts.expired(it.opts.bounded(next)) {
+			err = ErrJobExpired
+		}
+		if err != nil {
+			if len(missed) > 0 {
+				it.priority, it.jitterBase = fireTime, 0
+			}
+			break
+		}
+		missed = append(missed, fireTime)
+		if next > now {
+			it.jitterBase = 0
+			sched.setPriority(it, next)
+			break
+		}
+		fireTime, prev = next, next
+	}
+	sched.push(it)
+	sched.reportQueueLength()
+
+	return missed
+}
+
+// recordMissed records the fire of the paused item, to be caught up once
+// the item is resumed. The caller must hold the lock.
+func (sched *StdScheduler) recordMissed(it *item) {
+	switch it.opts.catchUp {
+	case CatchUpOnce:
+		it.missed = append(it.missed[:0], it.priority)
+	case CatchUpAll:
+		it.missed = append(it.missed, it.priority)
+	}
+}
+
+// dispatchCatchUp hands the catch-up fires over to the execution loop,
+// from a separate goroutine. The fires which are not handed over before
+// the scheduler is stopped are dropped. The caller must hold the lock.
+func (sched *StdScheduler) dispatchCatchUp(fires []*fire) {
+	if len(fires) == 0 {
+		return
+	}
+
+	done := sched.done
+	sched.wg.Add(1)
+	go func() {
+		defer sched.wg.Done()
+		for _, f := range fires {
+			select {
+			case sched.immediate <- f:
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// sortFires sorts the fires by their scheduled times, keeping the order
+// of the fires of each Job.
+func sortFires(fires []*fire) []*fire {
+	sort.SliceStable(fires, func(i, j int) bool {
+		return fires[i].job.NextRunTime < fires[j].job.NextRunTime
+	})
+
+	return fires
+}
@@ -0,0 +1,146 @@
+package quartz_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type catchUpRecorder struct {
+	mtx  sync.Mutex
+	runs map[string][]quartz.ExecutionContext
+}
+
+func (r *catchUpRecorder) job(name string) quartz.Job {
+	return quartz.NewFunctionJobWithDesc(name, func(ctx context.Context) (bool, error) {
+		execCtx, _ := quartz.ExecutionContextFrom(ctx)
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		r.runs[name] = append(r.runs[name], *execCtx)
+		return true, nil
+	})
+}
+
+func (r *catchUpRecorder) catchUps(name string) []time.Time {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var times []time.Time
+	for _, execCtx := range r.runs[name] {
+		if execCtx.CatchUp {
+			times = append(times, execCtx.ScheduledTime.UTC())
+		}
+	}
+	return times
+}
+
+func TestSchedulerCatchUpOnStart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  quartz.NewMockClock(now),
+		Logger: quartz.NewNoopLogger(),
+	})
+	recorder := &catchUpRecorder{runs: make(map[string][]quartz.ExecutionContext)}
+
+	// the jobs last fired three hours and a half ago
+	lastFire := now.Add(-3*time.Hour - 30*time.Minute)
+	policies := map[string]quartz.CatchUpPolicy{
+		"all":  quartz.CatchUpAll,
+		"once": quartz.CatchUpOnce,
+		"skip": quartz.CatchUpSkip,
+	}
+	keys := make(map[string]int)
+	for name, policy := range policies {
+		job := recorder.job(name)
+		keys[name] = job.Key()
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour),
+			quartz.WithCatchUp(policy, lastFire)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job, err := sched.GetScheduledJob(keys["all"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.NextRunTime, lastFire.Add(time.Hour).UnixNano())
+	assertEqual(t, job.CatchUpPolicy, quartz.CatchUpAll)
+
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	for len(recorder.catchUps("all")) < 3 || len(recorder.catchUps("once")) < 1 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("the missed fires were not caught up")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// the catch-up runs are dispatched in order, but may overlap
+	all := recorder.catchUps("all")
+	sort.Slice(all, func(i, j int) bool { return all[i].Before(all[j]) })
+	assertEqual(t, all, []time.Time{
+		lastFire.Add(time.Hour),
+		lastFire.Add(2 * time.Hour),
+		lastFire.Add(3 * time.Hour),
+	})
+	assertEqual(t, recorder.catchUps("once"), []time.Time{lastFire.Add(3 * time.Hour)})
+	assertEqual(t, len(recorder.catchUps("skip")), 0)
+
+	// the jobs continue from the first fire time after now
+	for name, key := range keys {
+		job, err := sched.GetScheduledJob(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.NextRunTime != now.Add(30*time.Minute).UnixNano() {
+			t.Fatalf("unexpected next run time of %s: %s", name, job.NextRun())
+		}
+	}
+}
+
+func TestSchedulerCatchUpOnResume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger:            quartz.NewNoopLogger(),
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+	})
+	recorder := &catchUpRecorder{runs: make(map[string][]quartz.ExecutionContext)}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	job := recorder.job("once")
+	if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKeyWithGroup("once", "batch"), job,
+		quartz.NewSimpleTrigger(10*time.Millisecond), quartz.WithPaused(),
+		quartz.WithCatchUp(quartz.CatchUpOnce, time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(55 * time.Millisecond)
+	assertEqual(t, len(recorder.catchUps("once")), 0)
+	assertEqual(t, sched.ResumeGroup("batch"), 1)
+
+	for len(recorder.catchUps("once")) == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("the paused fires were not caught up")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	assertEqual(t, len(recorder.catchUps("once")), 1)
+}
@@ -0,0 +1,163 @@
+package quartz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CircuitState represents the state of the circuit of a CircuitBreakerJob.
+type CircuitState int8
+
+const (
+	// CircuitClosed is the initial state, the fires execute the Job.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen skips the fires until the cooldown elapses.
+	CircuitOpen
+
+	// CircuitHalfOpen executes a single probe fire, which decides
+	// whether the circuit is closed or opened again.
+	CircuitHalfOpen
+)
+
+// String returns the name of the CircuitState.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// CircuitBreakerListener is implemented by the SchedulerListeners interested
+// in the fires skipped by the CircuitBreakerJobs.
+type CircuitBreakerListener interface {
+	// JobSkippedCircuitOpen is called when a fire of a CircuitBreakerJob
+	// is skipped because its circuit is open.
+	JobSkippedCircuitOpen(job ScheduledJob)
+}
+
+// CircuitBreakerJob wraps a Job, skipping its fires after a number of the
+// consecutive failed executions, implements the quartz.Job interface.
+//
+// Once the failure threshold is reached, the circuit opens and the fires are
+// skipped until the cooldown elapses. The following fire is a probe: the
+// circuit closes if it succeeds, and opens again otherwise. A panic of the
+// wrapped Job is a failure. The state of the circuit is observable using the
+// Job of the ScheduledJob.
+type CircuitBreakerJob struct {
+	job              Job
+	failureThreshold int
+	cooldown         time.Duration
+
+	mtx      sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// Verify CircuitBreakerJob satisfies the Job interface.
+var _ Job = (*CircuitBreakerJob)(nil)
+
+// NewCircuitBreakerJob returns a new CircuitBreakerJob wrapping the given
+// Job. A failureThreshold below 1 is treated as 1.
+func NewCircuitBreakerJob(job Job, failureThreshold int, cooldown time.Duration) *CircuitBreakerJob {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreakerJob{
+		job:              job,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+}
+
+// Description returns the description of the wrapped Job.
+func (cb *CircuitBreakerJob) Description() string {
+	return cb.job.Description()
+}
+
+// Key returns the key of the wrapped Job.
+func (cb *CircuitBreakerJob) Key() int {
+	return cb.job.Key()
+}
+
+// State returns the state of the circuit. An open circuit becomes half-open
+// at the first fire after the cooldown.
+func (cb *CircuitBreakerJob) State() CircuitState {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	return cb.state
+}
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+// It executes the wrapped Job unless the circuit is open, in which case the fire is
+// skipped without an error and reported to the CircuitBreakerListeners of the
+// StdScheduler executing the CircuitBreakerJob. The time is measured using the fire
+// times of the StdScheduler.
+func (cb *CircuitBreakerJob) Execute(ctx context.Context) error {
+	now := time.Now()
+	if execCtx, ok := ExecutionContextFrom(ctx); ok {
+		now = execCtx.FireTime
+	}
+
+	if !cb.allow(now) {
+		notifyListeners(ctx, func(l SchedulerListener, job ScheduledJob) {
+			if listener, ok := l.(CircuitBreakerListener); ok {
+				listener.JobSkippedCircuitOpen(job)
+			}
+		})
+		return nil
+	}
+
+	err := executeJob(ctx, cb.job)
+	cb.record(now, err)
+
+	return err
+}
+
+// allow reports whether the fire at the time executes the wrapped Job.
+func (cb *CircuitBreakerJob) allow(now time.Time) bool {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// the probe is in flight
+		return false
+	}
+
+	return true
+}
+
+// record updates the circuit with the outcome of the fire at the time.
+func (cb *CircuitBreakerJob) record(now time.Time, err error) {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	if err == nil {
+		cb.state = CircuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = now
+	}
+}
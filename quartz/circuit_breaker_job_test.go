@@ -0,0 +1,117 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type circuitListener struct {
+	quartz.NoopListener
+	skipped int32
+}
+
+func (l *circuitListener) JobSkippedCircuitOpen(_ quartz.ScheduledJob) {
+	atomic.AddInt32(&l.skipped, 1)
+}
+
+func TestCircuitBreakerJob(t *testing.T) {
+	ctx := context.Background()
+
+	var runs int32
+	var failing int32 = 1
+	inner := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	})
+	job := quartz.NewCircuitBreakerJob(inner, 3, 50*time.Millisecond)
+	assertEqual(t, job.Key(), inner.Key())
+	assertEqual(t, job.Description(), inner.Description())
+
+	// the consecutive failures are reset by a success
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertNotEqual(t, job.Execute(ctx), nil)
+	atomic.StoreInt32(&failing, 0)
+	assertEqual(t, job.Execute(ctx), nil)
+	atomic.StoreInt32(&failing, 1)
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.State(), quartz.CircuitClosed)
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.State(), quartz.CircuitOpen)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(6))
+
+	// the fires are skipped while the circuit is open
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(6))
+
+	// a failed probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.State(), quartz.CircuitOpen)
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(7))
+
+	// a successful probe closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&failing, 0)
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.State(), quartz.CircuitClosed)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(8))
+}
+
+func TestCircuitBreakerJobPanic(t *testing.T) {
+	job := quartz.NewCircuitBreakerJob(quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		panic("boom")
+	}), 0, time.Hour)
+	assertEqual(t, job.Execute(context.Background()).Error(), "job panicked: boom")
+	assertEqual(t, job.State(), quartz.CircuitOpen)
+	assertEqual(t, job.State().String(), "open")
+}
+
+func TestCircuitBreakerJobScheduler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := &circuitListener{}
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	sched.AddListener(listener)
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var runs int32
+	job := quartz.NewCircuitBreakerJob(quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		return false, errors.New("failed")
+	}), 2, 10*time.Minute)
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cooldown is measured on the clock of the scheduler
+	for i := 0; i < 12; i++ {
+		clock.Advance(time.Minute)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEqual(t, atomic.LoadInt32(&runs), int32(3))
+	assertEqual(t, atomic.LoadInt32(&listener.skipped), int32(9))
+
+	scheduled, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.Job.(*quartz.CircuitBreakerJob).State(), quartz.CircuitOpen)
+}
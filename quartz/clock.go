@@ -0,0 +1,204 @@
+package quartz
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time source of the StdScheduler.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new Timer that will send the current
+	// time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer represents a single event, as the time.Timer does.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It returns false if
+	// the Timer has already expired or been stopped.
+	Stop() bool
+
+	// Reset changes the Timer to expire after duration d. It
+	// returns true if the Timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// monotonicClock is implemented by the Clocks whose timers run on a
+// monotonic clock, which does not jump along with their wall clock, e.g.
+// when the system is suspended or its clock is set.
+type monotonicClock interface {
+	// monotonic returns the monotonic time elapsed since an arbitrary
+	// point in time.
+	monotonic() time.Duration
+}
+
+// monotonicBase is the point the monotonic time of the realClock is
+// measured from.
+var monotonicBase = time.Now()
+
+// NewRealClock returns a Clock backed by the time package.
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) monotonic() time.Duration {
+	return time.Since(monotonicBase)
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+// MockClock implements the Clock interface with a manually advanced
+// time, allowing the scheduler to be tested deterministically. As with
+// the real clock, its timers run on a monotonic time, which is moved by
+// Advance, but not by Jump.
+type MockClock struct {
+	mtx    sync.Mutex
+	now    time.Time
+	mono   time.Duration
+	timers []*mockTimer
+}
+
+// Verify MockClock satisfies the Clock interface.
+var _ Clock = (*MockClock)(nil)
+
+// NewMockClock returns a new MockClock set to the given time.
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+// Now returns the current time of the MockClock.
+func (c *MockClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now
+}
+
+// NewTimer creates a new Timer that fires once the MockClock is
+// advanced by at least duration d.
+func (c *MockClock) NewTimer(d time.Duration) Timer {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	t := &mockTimer{
+		clock: c,
+		c:     make(chan time.Time, 1),
+	}
+	c.timers = append(c.timers, t)
+	t.reset(d)
+
+	return t
+}
+
+// Advance moves the MockClock forward by duration d, firing the
+// timers which expire in the meantime.
+func (c *MockClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = c.now.Add(d)
+	c.mono += d
+	for _, t := range c.timers {
+		if t.active && t.deadline <= c.mono {
+			t.fire()
+		}
+	}
+}
+
+// Jump moves the wall time of the MockClock by duration d, which may be
+// negative, without moving its monotonic time or firing the timers, as
+// when the system is suspended or its clock is set.
+func (c *MockClock) Jump(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func (c *MockClock) monotonic() time.Duration {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.mono
+}
+
+type mockTimer struct {
+	clock    *MockClock
+	c        chan time.Time
+	deadline time.Duration // the monotonic time of the expiration
+	active   bool
+}
+
+func (t *mockTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *mockTimer) Stop() bool {
+	t.clock.mtx.Lock()
+	defer t.clock.mtx.Unlock()
+
+	active := t.active
+	t.active = false
+
+	return active
+}
+
+func (t *mockTimer) Reset(d time.Duration) bool {
+	t.clock.mtx.Lock()
+	defer t.clock.mtx.Unlock()
+
+	return t.reset(d)
+}
+
+// reset arms the timer, firing it immediately if d is not positive.
+// The caller must hold the clock lock.
+func (t *mockTimer) reset(d time.Duration) bool {
+	active := t.active
+	t.deadline = t.clock.mono + d
+	t.active = true
+	if d <= 0 {
+		t.fire()
+	}
+
+	return active
+}
+
+// fire delivers the current time, dropping it if the channel is full
+// as the time.Timer does. The caller must hold the clock lock.
+func (t *mockTimer) fire() {
+	t.active = false
+	select {
+	case t.c <- t.clock.now:
+	default:
+	}
+}
@@ -0,0 +1,82 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestMockClockTimer(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(start)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case fired := <-timer.C():
+		assertEqual(t, fired, start.Add(time.Minute))
+	default:
+		t.Fatal("timer did not fire")
+	}
+	assertEqual(t, timer.Stop(), false)
+
+	assertEqual(t, timer.Reset(time.Minute), false)
+	assertEqual(t, timer.Stop(), true)
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestSchedulerMockClock(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(start)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		BlockingExecution: true,
+		Clock:             clock,
+	})
+
+	fired := make(chan time.Time)
+	job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		fired <- clock.Now()
+		return true, nil
+	})
+	trigger, err := quartz.NewCronTrigger("0 0 12 * * ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJob(ctx, job, trigger); err != nil {
+		t.Fatal(err)
+	}
+
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	next := start.Add(12 * time.Hour)
+	clock.Advance(12 * time.Hour)
+	for i := 0; i < 7; i++ {
+		select {
+		case at := <-fired:
+			assertEqual(t, at, next)
+		case <-ctx.Done():
+			t.Fatal("job did not fire", next)
+		}
+
+		next = next.Add(24 * time.Hour)
+		clock.Advance(24 * time.Hour)
+	}
+}
@@ -0,0 +1,102 @@
+package quartz
+
+import "time"
+
+// DefaultClockCheckInterval is the ClockCheckInterval of the StdScheduler
+// returned by NewStdScheduler.
+const DefaultClockCheckInterval = 30 * time.Second
+
+// defaultClockJumpThreshold is the ClockJumpThreshold used when no
+// threshold is configured.
+const defaultClockJumpThreshold = 5 * time.Second
+
+// clockWatchdog detects the jumps of the wall clock, which the timers of
+// the execution loop, running on the monotonic clock, do not account
+// for. The jump is the difference between the wall clock time and the
+// monotonic time elapsed between two checks, so that the time the loop
+// spends executing the jobs is not mistaken for a jump. The jumps are
+// not detected unless the Clock implements the monotonicClock interface.
+type clockWatchdog struct {
+	clock    monotonicClock
+	now      func() time.Time
+	interval time.Duration
+	timer    Timer
+	wall     time.Time
+	mono     time.Duration
+}
+
+// newClockWatchdog returns a new clockWatchdog ticking at the interval,
+// which is disabled unless the interval is positive.
+func newClockWatchdog(clock Clock, interval time.Duration) *clockWatchdog {
+	w := &clockWatchdog{now: clock.Now, interval: interval}
+	if mono, ok := clock.(monotonicClock); ok && interval > 0 {
+		w.clock = mono
+		w.timer = clock.NewTimer(interval)
+		w.wall, w.mono = clock.Now(), mono.monotonic()
+	}
+
+	return w
+}
+
+// C returns the channel of the watchdog ticks, nil if it is disabled.
+func (w *clockWatchdog) C() <-chan time.Time {
+	if w.timer == nil {
+		return nil
+	}
+
+	return w.timer.C()
+}
+
+// tick returns the jump of the clock since the previous check, and arms
+// the watchdog timer again.
+func (w *clockWatchdog) tick() time.Duration {
+	w.timer.Reset(w.interval)
+	return w.check()
+}
+
+// check returns the jump of the clock since the previous check, zero if
+// the watchdog is disabled.
+func (w *clockWatchdog) check() time.Duration {
+	if w.timer == nil {
+		return 0
+	}
+
+	// the monotonic reading of the real clock is stripped, so that
+	// the times are compared on the wall clock
+	wall, mono := w.now(), w.clock.monotonic()
+	jump := wall.Round(0).Sub(w.wall.Round(0)) - (mono - w.mono)
+	w.wall, w.mono = wall, mono
+
+	return jump
+}
+
+// stop stops the watchdog timer.
+func (w *clockWatchdog) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// clockJumped handles the jump of the clock, if it is beyond the
+// ClockJumpThreshold, and reports whether the clock has jumped. The
+// fires missed during a forward jump are handled as misfires. On a
+// backward jump, the fire times keep following the previous ones, so
+// that the fires are not repeated.
+func (sched *StdScheduler) clockJumped(jump time.Duration) bool {
+	threshold := sched.opts.ClockJumpThreshold
+	if jump <= threshold && jump >= -threshold {
+		return false
+	}
+
+	sched.opts.Logger.Info("Detected a jump of the clock", "jump", jump)
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if jump > 0 {
+		sched.jumpedUntil = sched.nowNano()
+	} else {
+		sched.jumpedUntil = 0
+	}
+
+	return true
+}
@@ -0,0 +1,91 @@
+package quartz_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerClockJump(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:              clock,
+		Logger:             quartz.NewNoopLogger(),
+		MisfirePolicy:      quartz.MisfireRescheduleNext,
+		ClockCheckInterval: 30 * time.Second,
+	})
+	listener := &recordingListener{}
+	sched.AddListener(listener)
+
+	var runs int32
+	key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	waitForNextRun := func(expected time.Time) {
+		t.Helper()
+		for {
+			job, err := sched.GetScheduledJob(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.NextRunTime == expected.UnixNano() {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("unexpected next run time %s", job.NextRun())
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}
+
+	// the fire is executed on time
+	time.Sleep(20 * time.Millisecond)
+	errs := make(chan error, 1)
+	go func() { errs <- sched.WaitForJob(ctx, key) }()
+	time.Sleep(10 * time.Millisecond)
+	clock.Advance(time.Minute)
+	assertEqual(t, <-errs, nil)
+	waitForNextRun(now.Add(2 * time.Minute))
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+
+	// the fires missed during a suspend of two hours are handled by
+	// the MisfirePolicy, instead of being executed in a burst
+	clock.Jump(2 * time.Hour)
+	clock.Advance(time.Minute)
+	waitForNextRun(now.Add(2*time.Hour + 3*time.Minute))
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+	listener.mtx.Lock()
+	assertEqual(t, len(listener.skipped), 1)
+	listener.mtx.Unlock()
+
+	// the fires are not repeated once the clock is set back, and the
+	// timer is armed again on the wall clock
+	clock.Jump(-30 * time.Minute)
+	clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+
+	go func() { errs <- sched.WaitForJob(ctx, key) }()
+	time.Sleep(10 * time.Millisecond)
+	clock.Advance(30 * time.Minute)
+	assertEqual(t, <-errs, nil)
+	waitForNextRun(now.Add(2*time.Hour + 4*time.Minute))
+	assertEqual(t, atomic.LoadInt32(&runs), int32(2))
+}
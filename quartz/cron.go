@@ -26,31 +26,78 @@ import (
 // "0 10,44 14 ? 3 WED"     Fire at 2:10pm and at 2:44pm every Wednesday in the month of March.
 // "0 15 10 ? * MON-FRI"    Fire at 10:15am every Monday, Tuesday, Wednesday, Thursday and Friday
 // "0 15 10 15 * ?"         Fire at 10:15am on the 15th day of every month
+// "0 15 10 L * ?"          Fire at 10:15am on the last day of every month
+// "0 15 10 LW * ?"         Fire at 10:15am on the last weekday of every month
+// "0 15 10 15W * ?"        Fire at 10:15am on the weekday nearest to the 15th day of every month
+// "0 15 10 ? * 6L"         Fire at 10:15am on the last Friday of every month
+// "0 15 10 ? * TUE#2"      Fire at 10:15am on the second Tuesday of every month
+// "15 10 * * MON-FRI"      Fire at 10:15am every Monday, Tuesday, Wednesday, Thursday and Friday
+// "@daily"                 Fire at midnight every day
+// "@every 1h30m"           Fire every hour and a half
+//
+// Standard 5-field expressions (minute hour day-of-month month day-of-week) fire
+// at the first second of the matching minutes. Their day-of-week values follow
+// the Unix 0-7 numbering, both 0 and 7 being Sunday, e.g. 0-6 or 1-7 for every
+// day and 5-7 for Friday to Sunday, while the 6 and 7-field expressions follow
+// the Quartz 1-7 (SUN-SAT) numbering. The names of the months and the days are
+// accepted in their full and 3-letter forms, in any case, e.g. MON, mon or
+// Monday. The ? placeholder, which is only allowed in the day fields, matches
+// any day as * does.
+//
+// The expression is evaluated in the location of the trigger. Wall clock times
+// skipped by a forward daylight saving transition fire at the moment of the
+// transition, e.g. a 02:30 job fires at 03:00 on the spring-forward day.
+// Wall clock times repeated by a backward transition fire once, at their first
+// occurrence.
 type CronTrigger struct {
 	expression  string
 	fields      []*cronField
 	lastDefined int
 	location    *time.Location
+	every       time.Duration
+	limits      triggerLimits
 }
 
-// Verify CronTrigger satisfies the Trigger interface.
-var _ Trigger = (*CronTrigger)(nil)
+// Verify CronTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*CronTrigger)(nil)
+
+// Verify CronTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*CronTrigger)(nil)
 
 // NewCronTrigger returns a new CronTrigger using the UTC location.
-func NewCronTrigger(expr string) (*CronTrigger, error) {
-	return NewCronTriggerWithLoc(expr, time.UTC)
+func NewCronTrigger(expr string, opts ...TriggerOption) (*CronTrigger, error) {
+	return NewCronTriggerWithLoc(expr, time.UTC, opts...)
 }
 
 // NewCronTriggerWithLoc returns a new CronTrigger with the given time.Location.
-func NewCronTriggerWithLoc(expr string, location *time.Location) (*CronTrigger, error) {
-	fields, err := validateCronExpression(expr)
+// A nil location defaults to UTC. The options can limit the CronTrigger by an
+// end time or a repeat count.
+func NewCronTriggerWithLoc(expr string, location *time.Location, opts ...TriggerOption) (*CronTrigger, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	if strings.HasPrefix(expr, everyPrefix) {
+		every, err := parseEvery(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &CronTrigger{
+			expression: everyPrefix + every.String(),
+			location:   location,
+			every:      every,
+			limits:     newTriggerLimits(opts),
+		}, nil
+	}
+
+	expr, fields, err := parseCronExpression(expr)
 	if err != nil {
 		return nil, err
 	}
 
 	lastDefined := -1
 	for i, field := range fields {
-		if len(field.values) > 0 {
+		if len(field.values) > 0 || field.days != nil {
 			lastDefined = i
 		}
 	}
@@ -65,19 +112,52 @@ func NewCronTriggerWithLoc(expr string, location *time.Location) (*CronTrigger,
 		fields:      fields,
 		lastDefined: lastDefined,
 		location:    location,
+		limits:      newTriggerLimits(opts),
 	}, nil
 }
 
 // NextFireTime returns the next time at which the CronTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the CronTrigger can never fire again.
 func (ct *CronTrigger) NextFireTime(prev int64) (int64, error) {
+	return ct.limits.nextFireTime(ct.next, prev)
+}
+
+// NextN returns the next n fire times of the CronTrigger following from.
+// If the CronTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (ct *CronTrigger) NextN(from int64, n int) ([]int64, error) {
+	return ct.limits.nextN(ct.next, from, n)
+}
+
+// Location returns the location of the CronTrigger.
+func (ct *CronTrigger) Location() *time.Location {
+	return ct.location
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (ct *CronTrigger) RemainingRepeats() (int, bool) {
+	return ct.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the CronTrigger, and false if it has none.
+func (ct *CronTrigger) EndTime() (time.Time, bool) {
+	return ct.limits.end()
+}
+
+func (ct *CronTrigger) next(prev int64) (int64, error) {
+	if ct.every > 0 {
+		return prev + ct.every.Nanoseconds(), nil
+	}
+
 	parser := newCronExpressionParser(ct.lastDefined)
 	prevTime := time.Unix(prev/int64(time.Second), 0).In(ct.location)
 	return parser.nextTime(prevTime, ct.fields)
 }
 
-// Description returns the description of the trigger.
+// Description returns the description of the trigger, using the
+// normalized form of the expression.
 func (ct *CronTrigger) Description() string {
-	return fmt.Sprintf("CronTrigger %s", ct.expression)
+	return fmt.Sprintf("CronTrigger %s %s", ct.expression, ct.location)
 }
 
 // cronExpressionParser parses cron expressions.
@@ -102,6 +182,13 @@ func newCronExpressionParser(lastDefined int) *cronExpressionParser {
 // cronField represents a parsed cron expression as an array.
 type cronField struct {
 	values []int
+
+	// days resolves the days of the month matching a day field
+	// which uses the L, W or # special characters.
+	days func(year int, month time.Month) []int
+
+	// phrase is the English rendering of the special day field.
+	phrase string
 }
 
 // isEmpty checks if the cronField values array is empty.
@@ -129,6 +216,21 @@ var (
 	months = []string{"0", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
 	days   = []string{"0", "SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
 
+	// the full names of the months and the days, along with their
+	// abbreviations
+	fullNames = func() map[string]string {
+		names := make(map[string]string, 19)
+		for month := time.January; month <= time.December; month++ {
+			name := strings.ToUpper(month.String())
+			names[name] = name[:3]
+		}
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			name := strings.ToUpper(day.String())
+			names[name] = name[:3]
+		}
+		return names
+	}()
+
 	// the pre-defined cron expressions
 	special = map[string]string{
 		"@yearly":  "0 0 0 1 1 *",
@@ -139,69 +241,193 @@ var (
 	}
 )
 
-// <second> <minute> <hour> <day-of-month> <month> <day-of-week> <year>
-// <year> field is optional
-
-// the ? wildcard is only used in the day of month and day of week fields
-func validateCronExpression(expression string) ([]*cronField, error) {
-	var tokens []string
+// everyPrefix is the prefix of the fixed interval expressions.
+const everyPrefix = "@every "
 
+// normalizeCronExpression expands the pre-defined cron expressions and
+// prepends the seconds field to the standard 5-field expressions, whose
+// day-of-week field is translated from the Unix numbering to the names.
+func normalizeCronExpression(expression string) (string, error) {
 	if value, ok := special[expression]; ok {
-		tokens = strings.Split(value, " ")
-	} else {
-		tokens = strings.Split(expression, " ")
-	}
-	length := len(tokens)
-	if length < 6 || length > 7 {
-		return nil, cronError("Invalid expression length")
+		return value, nil
 	}
-	if length == 6 {
-		tokens = append(tokens, "*")
+
+	tokens := strings.Fields(expression)
+	if len(tokens) == 5 {
+		if !namedDayOfWeek(tokens[4]) {
+			dayOfWeek, err := unixDayOfWeek(tokens[4])
+			if err != nil {
+				return "", err
+			}
+			tokens[4] = dayOfWeek
+		}
+		tokens = append([]string{"0"}, tokens...)
 	}
-	if (tokens[3] != "?" && tokens[3] != "*") && (tokens[5] != "?" && tokens[5] != "*") {
-		return nil, cronError("Day field was set twice")
+
+	return strings.Join(tokens, " "), nil
+}
+
+// namedDayOfWeek reports whether the day-of-week field is valid and refers
+// to the days by their names only, the same in all the numberings.
+func namedDayOfWeek(field string) bool {
+	if strings.ContainsAny(field, "0123456789") {
+		return false
 	}
+	_, err := parseDayOfWeekField(field)
+	return err == nil
+}
 
-	return buildCronField(tokens)
+// CronParseError is returned for an invalid cron expression. It details
+// the position of the error, so that the offending token can be reported
+// back to the author of the expression.
+type CronParseError struct {
+	// Field is the zero-based index of the invalid field in the
+	// expression, or -1 if the error is not specific to a field.
+	Field int
+	// FieldName is the name of the invalid field, e.g. "hours".
+	FieldName string
+	// Token is the offending part of the field.
+	Token string
+	// Allowed describes the values allowed in the field.
+	Allowed string
+	// Cause describes the error.
+	Cause string
 }
 
-func buildCronField(tokens []string) ([]*cronField, error) {
-	var err error
-	fields := make([]*cronField, 7)
-	fields[0], err = parseField(tokens[0], 0, 59)
-	if err != nil {
-		return nil, err
+// Error returns the string representation of the CronParseError.
+func (e *CronParseError) Error() string {
+	if e.Field < 0 {
+		return cronError(e.Cause).Error()
 	}
 
-	fields[1], err = parseField(tokens[1], 0, 59)
-	if err != nil {
-		return nil, err
+	return fmt.Sprintf("invalid cron expression: %s at %s field %d: %q, allowed: %s",
+		e.Cause, e.FieldName, e.Field, e.Token, e.Allowed)
+}
+
+// fieldError returns a CronParseError for the token, to be completed
+// with the position of the field.
+func fieldError(token, cause string) *CronParseError {
+	return &CronParseError{Token: token, Cause: cause}
+}
+
+// cronFieldSpec describes a field of the cron expression.
+type cronFieldSpec struct {
+	name    string
+	allowed string
+	parse   func(string) (*cronField, error)
+}
+
+// cronFieldSpecs lists the fields of the 7-field cron expression.
+var cronFieldSpecs = []cronFieldSpec{
+	{"seconds", "0-59 , - * /", func(f string) (*cronField, error) { return parseField(f, 0, 59) }},
+	{"minutes", "0-59 , - * /", func(f string) (*cronField, error) { return parseField(f, 0, 59) }},
+	{"hours", "0-23 , - * /", func(f string) (*cronField, error) { return parseField(f, 0, 23) }},
+	{"day of month", "1-31 , - * ? / L W", parseDayOfMonthField},
+	{"month", "1-12 or JAN-DEC , - * /", func(f string) (*cronField, error) { return parseField(f, 1, 12, months) }},
+	{"day of week", "1-7 or SUN-SAT , - * ? / L #", parseDayOfWeekField},
+	{"year", "1970-3940 , - * /", func(f string) (*cronField, error) { return parseField(f, 1970, 1970*2) }},
+}
+
+// ValidateCronExpression validates the cron expression, as accepted by
+// NewCronTrigger. The returned error is a *CronParseError.
+func ValidateCronExpression(expr string) error {
+	if strings.HasPrefix(expr, everyPrefix) {
+		_, err := parseEvery(expr)
+		return err
 	}
 
-	fields[2], err = parseField(tokens[2], 0, 23)
-	if err != nil {
-		return nil, err
+	_, _, err := parseCronExpression(expr)
+	return err
+}
+
+// parseEvery parses the duration of a fixed interval expression.
+func parseEvery(expr string) (time.Duration, error) {
+	token := strings.TrimSpace(strings.TrimPrefix(expr, everyPrefix))
+	every, err := time.ParseDuration(token)
+	if err != nil || every <= 0 {
+		return 0, &CronParseError{
+			Field:     1,
+			FieldName: "duration",
+			Token:     token,
+			Allowed:   "a positive duration",
+			Cause:     "invalid @every duration",
+		}
 	}
 
-	fields[3], err = parseField(tokens[3], 1, 31)
+	return every, nil
+}
+
+// parseCronExpression normalizes and parses the cron expression. Error
+// positions refer to the fields of the expression as given.
+func parseCronExpression(expr string) (string, []*cronField, error) {
+	normalized, err := normalizeCronExpression(expr)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
-
-	fields[4], err = parseField(tokens[4], 1, 12, months)
+	fields, err := validateCronExpression(normalized)
 	if err != nil {
-		return nil, err
+		if parseErr, ok := err.(*CronParseError); ok && parseErr.Field > 0 &&
+			normalized != expr && len(strings.Fields(expr)) == 5 {
+			parseErr.Field--
+		}
+		return "", nil, err
 	}
 
-	fields[5], err = parseField(tokens[5], 1, 7, days)
-	if err != nil {
-		return nil, err
+	return normalized, fields, nil
+}
+
+// <second> <minute> <hour> <day-of-month> <month> <day-of-week> <year>
+// <year> field is optional
+
+// the ? wildcard is only used in the day of month and day of week fields
+func validateCronExpression(expression string) ([]*cronField, error) {
+	tokens := strings.Split(expression, " ")
+	length := len(tokens)
+	if length < 6 || length > 7 {
+		return nil, &CronParseError{Field: -1, Cause: "invalid expression length"}
+	}
+	if length == 6 {
+		tokens = append(tokens, "*")
+	}
+	for i, token := range tokens {
+		if token == "?" && i != 3 && i != 5 {
+			return nil, &CronParseError{
+				Field:     i,
+				FieldName: cronFieldSpecs[i].name,
+				Token:     token,
+				Allowed:   cronFieldSpecs[i].allowed,
+				Cause:     "? is only allowed in the day fields",
+			}
+		}
+	}
+	if (tokens[3] != "?" && tokens[3] != "*") && (tokens[5] != "?" && tokens[5] != "*") {
+		return nil, &CronParseError{
+			Field:     5,
+			FieldName: cronFieldSpecs[5].name,
+			Token:     tokens[5],
+			Allowed:   "? if the day of month is set",
+			Cause:     "day field was set twice",
+		}
 	}
-	fields[5].incr(-1)
 
-	fields[6], err = parseField(tokens[6], 1970, 1970*2)
-	if err != nil {
-		return nil, err
+	return buildCronField(tokens)
+}
+
+func buildCronField(tokens []string) ([]*cronField, error) {
+	fields := make([]*cronField, len(cronFieldSpecs))
+	for i, spec := range cronFieldSpecs {
+		field, err := spec.parse(tokens[i])
+		if err != nil {
+			parseErr, ok := err.(*CronParseError)
+			if !ok {
+				parseErr = fieldError(tokens[i], err.Error())
+			}
+			parseErr.Field = i
+			parseErr.FieldName = spec.name
+			parseErr.Allowed = spec.allowed
+			return nil, parseErr
+		}
+		fields[i] = field
 	}
 
 	return fields, nil
@@ -215,21 +441,17 @@ func parseField(field string, min int, max int, translate ...[]string) (*cronFie
 
 	// any value
 	if field == "*" || field == "?" {
-		return &cronField{[]int{}}, nil
-	}
-
-	// single value
-	i, err := strconv.Atoi(field)
-	if err == nil {
-		if inScope(i, min, max) {
-			return &cronField{[]int{i}}, nil
-		}
-		return nil, cronError("Single min/max validation error")
+		return &cronField{values: []int{}}, nil
 	}
 
 	// list values
 	if strings.Contains(field, ",") {
-		return parseListField(field, dict)
+		return parseListField(field, min, max, dict)
+	}
+
+	// step values
+	if strings.Contains(field, "/") {
+		return parseStepField(field, min, max, dict)
 	}
 
 	// range values
@@ -237,50 +459,144 @@ func parseField(field string, min int, max int, translate ...[]string) (*cronFie
 		return parseRangeField(field, min, max, dict)
 	}
 
-	// step values
-	if strings.Contains(field, "/") {
-		return parseStepField(field, min, max, dict)
+	// single value
+	i, err := parseValue(field, min, max, dict)
+	if err != nil {
+		return nil, err
 	}
 
-	// literal single value
-	if dict != nil {
-		i := intVal(dict, field)
-		if i >= 0 {
-			if inScope(i, min, max) {
-				return &cronField{[]int{i}}, nil
+	return &cronField{values: []int{i}}, nil
+}
+
+// parseValue parses a single numeric or named value of a field.
+func parseValue(token string, min int, max int, translate []string) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		i = intVal(translate, token)
+		if i < 0 {
+			if translate == nil {
+				return 0, fieldError(token, "invalid value")
 			}
-			return nil, cronError("Cron literal min/max validation error")
+			return 0, fieldError(token, "unknown name")
+		}
+	}
+	if !inScope(i, min, max) {
+		return 0, fieldError(token, "value out of range")
+	}
+
+	return i, nil
+}
+
+// parseDayOfMonthField parses the day-of-month field, including the
+// L (last day), LW (last weekday) and W (nearest weekday) values.
+func parseDayOfMonthField(field string) (*cronField, error) {
+	if !strings.ContainsAny(field, "LW") {
+		return parseField(field, 1, 31)
+	}
+	if strings.ContainsAny(field, ",-/") {
+		return nil, fieldError(field, "L and W cannot be used with lists, ranges or steps")
+	}
+
+	switch {
+	case field == "L":
+		return &cronField{days: lastDayOfMonth, phrase: "on the last day of the month"}, nil
+	case field == "LW":
+		return &cronField{days: lastWeekdayOfMonth, phrase: "on the last weekday of the month"}, nil
+	case strings.HasSuffix(field, "W"):
+		day, err := parseValue(strings.TrimSuffix(field, "W"), 1, 31, nil)
+		if err != nil {
+			return nil, err
 		}
+		return &cronField{
+			days:   nearestWeekday(day),
+			phrase: fmt.Sprintf("on the weekday nearest to day %d of the month", day),
+		}, nil
 	}
 
-	return nil, cronError("Cron parse error")
+	return nil, fieldError(field, "invalid value")
 }
 
-func parseListField(field string, translate []string) (*cronField, error) {
+// parseDayOfWeekField parses the day-of-week field, including the
+// L (last given weekday of the month) and # (nth given weekday of the
+// month) values. The values of the returned field are zero-based.
+func parseDayOfWeekField(field string) (*cronField, error) {
+	if !strings.ContainsAny(field, "L#") {
+		f, err := parseField(field, 1, 7, days)
+		if err != nil {
+			return nil, err
+		}
+		f.incr(-1)
+		return f, nil
+	}
+	if strings.ContainsAny(field, ",-/") {
+		return nil, fieldError(field, "L and # cannot be used with lists, ranges or steps")
+	}
+
+	switch {
+	case field == "L":
+		return &cronField{values: []int{int(time.Saturday)}}, nil
+	case strings.HasSuffix(field, "L"):
+		weekday, err := parseValue(strings.TrimSuffix(field, "L"), 1, 7, days)
+		if err != nil {
+			return nil, err
+		}
+		return &cronField{
+			days:   lastWeekday(time.Weekday(weekday - 1)),
+			phrase: fmt.Sprintf("on the last %s of the month", time.Weekday(weekday-1)),
+		}, nil
+	case strings.Contains(field, "#"):
+		t := strings.Split(field, "#")
+		if len(t) != 2 {
+			return nil, fieldError(field, "invalid # value")
+		}
+		weekday, err := parseValue(t[0], 1, 7, days)
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseValue(t[1], 1, 5, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &cronField{
+			days:   nthWeekday(time.Weekday(weekday-1), n),
+			phrase: fmt.Sprintf("on the %s %s of the month", ordinals[n-1], time.Weekday(weekday-1)),
+		}, nil
+	}
+
+	return nil, fieldError(field, "invalid value")
+}
+
+func parseListField(field string, min int, max int, translate []string) (*cronField, error) {
 	t := strings.Split(field, ",")
-	si, err := sliceAtoi(t)
-	if err != nil {
-		si, err = indexes(t, translate)
+	si := make([]int, 0, len(t))
+	for _, token := range t {
+		i, err := parseValue(token, min, max, translate)
 		if err != nil {
 			return nil, err
 		}
+		si = append(si, i)
 	}
 
 	sort.Ints(si)
-	return &cronField{si}, nil
+	return &cronField{values: si}, nil
 }
 
 func parseRangeField(field string, min int, max int, translate []string) (*cronField, error) {
-	var _range []int
 	t := strings.Split(field, "-")
 	if len(t) != 2 {
-		return nil, cronError("Parse cron range error")
+		return nil, fieldError(field, "invalid range")
 	}
 
-	from := normalize(t[0], translate)
-	to := normalize(t[1], translate)
-	if !inScope(from, min, max) || !inScope(to, min, max) {
-		return nil, cronError("Cron range min/max validation error")
+	from, err := parseValue(t[0], min, max, translate)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseValue(t[1], min, max, translate)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		return nil, fieldError(field, "reversed range")
 	}
 
 	_range, err := fillRange(from, to)
@@ -288,24 +604,26 @@ func parseRangeField(field string, min int, max int, translate []string) (*cronF
 		return nil, err
 	}
 
-	return &cronField{_range}, nil
+	return &cronField{values: _range}, nil
 }
 
 func parseStepField(field string, min int, max int, translate []string) (*cronField, error) {
-	var _step []int
 	t := strings.Split(field, "/")
 	if len(t) != 2 {
-		return nil, cronError("Parse cron step error")
+		return nil, fieldError(field, "invalid step")
 	}
 
 	if t[0] == "*" {
 		t[0] = strconv.Itoa(min)
 	}
 
-	from := normalize(t[0], translate)
-	step := atoi(t[1])
-	if !inScope(from, min, max) {
-		return nil, cronError("Cron step min/max validation error")
+	from, err := parseValue(t[0], min, max, translate)
+	if err != nil {
+		return nil, err
+	}
+	step, err := strconv.Atoi(t[1])
+	if err != nil || step <= 0 {
+		return nil, fieldError(t[1], "invalid step")
 	}
 
 	_step, err := fillStep(from, step, max)
@@ -313,12 +631,69 @@ func parseStepField(field string, min int, max int, translate []string) (*cronFi
 		return nil, err
 	}
 
-	return &cronField{_step}, nil
+	return &cronField{values: _step}, nil
 }
 
 func (parser *cronExpressionParser) nextTime(prev time.Time, fields []*cronField) (nextTime int64, err error) {
-	// Build CronStateMachine and run once
-	csm := makeCSMFromFields(prev, fields)
-	nextDateTime := csm.NextTriggerTime(prev.Location())
-	return nextDateTime.UnixNano(), nil
+	loc := prev.Location()
+	for {
+		// Build CronStateMachine and run once
+		csm := makeCSMFromFields(prev, fields)
+		wall, ok := csm.NextTriggerTime(time.UTC)
+		if !ok {
+			return 0, ErrTriggerExpired
+		}
+		nextDateTime := resolveWallTime(wall, loc)
+		if nextDateTime.After(prev) {
+			return nextDateTime.UnixNano(), nil
+		}
+
+		// the first occurrence of a repeated wall clock time has already
+		// passed, continue searching from it
+		prev = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(),
+			wall.Minute(), wall.Second(), 0, loc)
+	}
+}
+
+// resolveWallTime returns the earliest instant at which the clock in the
+// location shows the wall time, given as a UTC time. If the wall time is
+// skipped by a daylight saving transition, the moment of the transition
+// is returned.
+func resolveWallTime(wall time.Time, loc *time.Location) time.Time {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+	if before < after {
+		before, after = after, before
+	}
+
+	// the candidates in chronological order
+	from := wall.Add(-time.Duration(before) * time.Second).In(loc)
+	to := wall.Add(-time.Duration(after) * time.Second).In(loc)
+	for _, candidate := range []time.Time{from, to} {
+		if sameWallTime(candidate, wall) {
+			return candidate
+		}
+	}
+
+	// binary search for the first second after the transition
+	_, offset := from.Zone()
+	lo, hi := from.Unix(), to.Unix()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, midOffset := time.Unix(mid, 0).In(loc).Zone(); midOffset == offset {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return time.Unix(lo, 0).In(loc)
+}
+
+// sameWallTime checks if the times show the same wall clock time.
+func sameWallTime(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd &&
+		a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second()
 }
@@ -0,0 +1,364 @@
+package quartz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPhraseValues is the maximum number of the listed values rendered
+// by DescribeHuman, longer lists fall back to the raw expression.
+const maxPhraseValues = 6
+
+var ordinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+// DescribeHuman returns an English summary of the CronTrigger schedule,
+// e.g. "at 09:30 on weekdays". The normalized expression is returned for
+// the schedules which cannot be phrased.
+func (ct *CronTrigger) DescribeHuman() string {
+	if ct.every > 0 {
+		return "every " + humanDuration(ct.every)
+	}
+
+	description, ok := describeCronFields(ct.fields)
+	if !ok {
+		return ct.expression
+	}
+	if ct.location != time.UTC {
+		description += fmt.Sprintf(" (%s)", ct.location)
+	}
+
+	return description + ct.limits.describe()
+}
+
+// DescribeHuman returns an English summary of the SimpleTrigger schedule,
+// e.g. "every 5 minutes".
+func (st *SimpleTrigger) DescribeHuman() string {
+	description := "every " + humanDuration(st.Interval)
+	if st.InitialDelay > 0 {
+		description += ", first after " + humanDuration(st.InitialDelay)
+	}
+
+	return description + st.limits.describe()
+}
+
+// describe renders the limits as a suffix of a summary.
+func (l *triggerLimits) describe() string {
+	var sb strings.Builder
+	switch {
+	case l.repeatCount == 1:
+		sb.WriteString(", once")
+	case l.repeatCount > 1:
+		fmt.Fprintf(&sb, ", %d times", l.repeatCount)
+	}
+	if l.endTime != 0 {
+		fmt.Fprintf(&sb, ", until %s", time.Unix(0, l.endTime).UTC().Format(time.RFC3339))
+	}
+
+	return sb.String()
+}
+
+// describeCronFields renders the parsed cron fields, returning false if
+// any of them cannot be phrased.
+func describeCronFields(fields []*cronField) (string, bool) {
+	timeOfDay, daily, ok := describeTime(fields[0], fields[1], fields[2])
+	if !ok {
+		return "", false
+	}
+	parts := []string{timeOfDay}
+
+	day, ok := describeDays(fields[3], fields[5])
+	if !ok {
+		return "", false
+	}
+	if day == "" && daily {
+		day = "every day"
+	}
+	if day != "" {
+		parts = append(parts, day)
+	}
+
+	for _, period := range []struct {
+		field    *cronField
+		min, max int
+		unit     string
+		name     func(int) string
+	}{
+		{fields[4], 1, 12, "month", monthName},
+		{fields[6], 1970, 1970 * 2, "year", yearName},
+	} {
+		phrase, ok := describeValues(period.field, period.min, period.max, period.unit, period.name)
+		if !ok {
+			return "", false
+		}
+		if phrase != "" && !strings.HasPrefix(phrase, "every ") {
+			phrase = "in " + phrase
+		}
+		if phrase != "" {
+			parts = append(parts, phrase)
+		}
+	}
+
+	return strings.Join(parts, " "), true
+}
+
+// describeTime renders the time fields, reporting whether the result is
+// a set of times of the day.
+func describeTime(second, minute, hour *cronField) (string, bool, bool) {
+	seconds, sAll := valuesIn(second, 0, 59)
+	minutes, mAll := valuesIn(minute, 0, 59)
+	hours, hAll := valuesIn(hour, 0, 23)
+
+	if !sAll && len(seconds) == 1 && !mAll && len(minutes) == 1 {
+		switch {
+		case !hAll && len(hours) <= maxPhraseValues && isList(hours):
+			times := make([]string, len(hours))
+			for i, h := range hours {
+				times[i] = clockTime(h, minutes[0], seconds[0])
+			}
+			return "at " + joinList(times), true, true
+		case hAll && seconds[0] == 0:
+			return fmt.Sprintf("at minute %d of every hour", minutes[0]), false, true
+		}
+	}
+
+	var parts []string
+	switch {
+	case sAll:
+		parts = append(parts, "every second")
+	case len(seconds) != 1 || seconds[0] != 0:
+		phrase, ok := describeValues(second, 0, 59, "second", nil)
+		if !ok {
+			return "", false, false
+		}
+		parts = append(parts, phrase)
+	}
+
+	switch {
+	case mAll:
+		if len(parts) == 0 {
+			parts = append(parts, "every minute")
+		}
+	default:
+		phrase, ok := describeValues(minute, 0, 59, "minute", nil)
+		if !ok {
+			return "", false, false
+		}
+		parts = append(parts, phrase)
+	}
+
+	if !hAll {
+		if from, to, ok := rangeOf(hours); ok {
+			parts = append(parts, fmt.Sprintf("between %02d:00 and %02d:59", from, to))
+		} else {
+			phrase, ok := describeValues(hour, 0, 23, "hour", nil)
+			if !ok {
+				return "", false, false
+			}
+			parts = append(parts, phrase)
+		}
+	}
+
+	return strings.Join(parts, " "), false, true
+}
+
+// describeDays renders the day-of-month and day-of-week fields.
+func describeDays(dayOfMonth, dayOfWeek *cronField) (string, bool) {
+	if dayOfMonth.phrase != "" {
+		return dayOfMonth.phrase, true
+	}
+	if dayOfWeek.phrase != "" {
+		return dayOfWeek.phrase, true
+	}
+
+	if weekdays, all := valuesIn(dayOfWeek, 0, 6); !all {
+		switch {
+		case equalInts(weekdays, []int{1, 2, 3, 4, 5}):
+			return "on weekdays", true
+		case equalInts(weekdays, []int{0, 6}):
+			return "on weekends", true
+		}
+		phrase, ok := describeValues(dayOfWeek, 0, 6, "", weekdayName)
+		return "on " + phrase, ok
+	}
+
+	if days, all := valuesIn(dayOfMonth, 1, 31); !all {
+		if len(days) == 1 {
+			return fmt.Sprintf("on day %d of the month", days[0]), true
+		}
+		phrase, ok := describeValues(dayOfMonth, 1, 31, "day", nil)
+		return "on " + phrase + " of the month", ok
+	}
+
+	return "", true
+}
+
+// describeValues renders the values of a field as a step, a range or a
+// list of the unit. Numeric values are labelled with the unit, unless
+// the name function is given. It returns an empty string for a field
+// matching all of its values.
+func describeValues(field *cronField, min, max int, unit string,
+	name func(int) string) (string, bool) {
+	values, all := valuesIn(field, min, max)
+	if all {
+		return "", true
+	}
+
+	labelled := name == nil
+	prefix := func(plural bool) string {
+		switch {
+		case !labelled:
+			return ""
+		case plural:
+			return unit + "s "
+		default:
+			return unit + " "
+		}
+	}
+	if name == nil {
+		name = strconv.Itoa
+	}
+
+	if len(values) == 1 {
+		return prefix(false) + name(values[0]), true
+	}
+	if step, ok := stepOf(values, max); ok && step > 1 && unit != "" {
+		phrase := fmt.Sprintf("every %s", plural(step, unit))
+		if values[0] != min {
+			phrase += fmt.Sprintf(" starting at %s%s", prefix(false), name(values[0]))
+		}
+		return phrase, true
+	}
+	if from, to, ok := rangeOf(values); ok {
+		return fmt.Sprintf("%s%s through %s", prefix(true), name(from), name(to)), true
+	}
+	if len(values) > maxPhraseValues {
+		return "", false
+	}
+
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = name(v)
+	}
+
+	return prefix(true) + joinList(names), true
+}
+
+// valuesIn returns the values of the field, and true if the field
+// matches all of the values in the [min, max] range.
+func valuesIn(field *cronField, min, max int) ([]int, bool) {
+	if len(field.values) == 0 {
+		return nil, true
+	}
+	if from, to, ok := rangeOf(field.values); ok && from == min && to == max {
+		return field.values, true
+	}
+
+	return field.values, false
+}
+
+// rangeOf returns the bounds of the values if they are contiguous.
+func rangeOf(values []int) (int, int, bool) {
+	if len(values) < 2 {
+		return 0, 0, false
+	}
+	step, ok := stepOf(values, values[len(values)-1])
+	if !ok || step != 1 {
+		return 0, 0, false
+	}
+
+	return values[0], values[len(values)-1], true
+}
+
+// stepOf returns the step of the values if they form an arithmetic
+// progression, which continues up to the max value.
+func stepOf(values []int, max int) (int, bool) {
+	if len(values) < 2 {
+		return 0, false
+	}
+	step := values[1] - values[0]
+	for i := 2; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0, false
+		}
+	}
+	if values[len(values)-1]+step <= max {
+		return 0, false
+	}
+
+	return step, true
+}
+
+// isList checks if the values are not a step or a range.
+func isList(values []int) bool {
+	_, _, isRange := rangeOf(values)
+	return !isRange
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func joinList(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func clockTime(hour, minute, second int) string {
+	if second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)
+	}
+
+	return fmt.Sprintf("%02d:%02d", hour, minute)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return unit
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func humanDuration(d time.Duration) string {
+	for _, unit := range []struct {
+		duration time.Duration
+		name     string
+	}{
+		{24 * time.Hour, "day"},
+		{time.Hour, "hour"},
+		{time.Minute, "minute"},
+		{time.Second, "second"},
+	} {
+		if d >= unit.duration && d%unit.duration == 0 {
+			return plural(int(d/unit.duration), unit.name)
+		}
+	}
+
+	return d.String()
+}
+
+func monthName(month int) string {
+	return time.Month(month).String()
+}
+
+func yearName(year int) string {
+	return strconv.Itoa(year)
+}
+
+func weekdayName(weekday int) string {
+	return time.Weekday(weekday).String()
+}
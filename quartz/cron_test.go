@@ -1,8 +1,10 @@
 package quartz_test
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -218,6 +220,158 @@ func TestCronExpressionWithLoc2(t *testing.T) {
 	assertEqual(t, result, "Sun Apr 7 14:00:00 2024")
 }
 
+func TestCronExpressionDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		prev       time.Time
+		expected   []string
+	}{
+		{
+			name:       "spring-forward daily",
+			expression: "0 30 2 * * ?",
+			prev:       time.Date(2024, 3, 9, 12, 0, 0, 0, loc),
+			expected: []string{
+				"2024-03-10 03:00:00 -0400 EDT",
+				"2024-03-11 02:30:00 -0400 EDT",
+			},
+		},
+		{
+			name:       "spring-forward hourly",
+			expression: "0 30 * * * ?",
+			prev:       time.Date(2024, 3, 10, 1, 0, 0, 0, loc),
+			expected: []string{
+				"2024-03-10 01:30:00 -0500 EST",
+				"2024-03-10 03:00:00 -0400 EDT",
+				"2024-03-10 03:30:00 -0400 EDT",
+			},
+		},
+		{
+			name:       "fall-back daily",
+			expression: "0 30 1 * * ?",
+			prev:       time.Date(2024, 11, 2, 12, 0, 0, 0, loc),
+			expected: []string{
+				"2024-11-03 01:30:00 -0400 EDT",
+				"2024-11-04 01:30:00 -0500 EST",
+			},
+		},
+		{
+			name:       "fall-back hourly",
+			expression: "0 30 * * * ?",
+			prev:       time.Date(2024, 11, 3, 0, 0, 0, 0, loc),
+			expected: []string{
+				"2024-11-03 00:30:00 -0400 EDT",
+				"2024-11-03 01:30:00 -0400 EDT",
+				"2024-11-03 02:30:00 -0500 EST",
+			},
+		},
+		{
+			name:       "fall-back repeated hour",
+			expression: "0 30 1 * * ?",
+			prev:       time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC), // 01:00 EST
+			expected: []string{
+				"2024-11-04 01:30:00 -0500 EST",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cronTrigger, err := quartz.NewCronTriggerWithLoc(tt.expression, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prev := tt.prev.UnixNano()
+			for _, expected := range tt.expected {
+				prev, err = cronTrigger.NextFireTime(prev)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertEqual(t, time.Unix(0, prev).In(loc).String(), expected)
+			}
+		})
+	}
+}
+
+func TestCronDescription(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cronTrigger, err := quartz.NewCronTriggerWithLoc("0 0 10 * * ?", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, cronTrigger.Description(), "CronTrigger 0 0 10 * * ? America/New_York")
+
+	cronTrigger, err = quartz.NewCronTriggerWithLoc("0 0 10 * * ?", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, cronTrigger.Description(), "CronTrigger 0 0 10 * * ? UTC")
+}
+
+func TestCronDescribeHuman(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"0 30 9 * * MON-FRI", "at 09:30 on weekdays"},
+		{"0 0 8 ? * SAT,SUN", "at 08:00 on weekends"},
+		{"0 0 9 ? * MON,WED,FRI", "at 09:00 on Monday, Wednesday and Friday"},
+		{"0 */5 * * * ?", "every 5 minutes"},
+		{"0 0/15 9-17 * * ?", "every 15 minutes between 09:00 and 17:59"},
+		{"0 15 * * * ?", "at minute 15 of every hour"},
+		{"0 10 14,18 * * ?", "at 14:10 and 18:10 every day"},
+		{"30 0 12 * * ?", "at 12:00:30 every day"},
+		{"* * * * * ?", "every second"},
+		{"0 0 12 1 * ?", "at 12:00 on day 1 of the month"},
+		{"0 0 9 1,15 * ?", "at 09:00 on days 1 and 15 of the month"},
+		{"0 0 0 1 1/3 ?", "at 00:00 on day 1 of the month every 3 months"},
+		{"0 0 0 1 1 ? 2030", "at 00:00 on day 1 of the month in January in 2030"},
+		{"0 0 0 * MAR-MAY ?", "at 00:00 every day in March through May"},
+		{"0 0 0 L * ?", "at 00:00 on the last day of the month"},
+		{"0 0 0 LW * ?", "at 00:00 on the last weekday of the month"},
+		{"0 0 9 15W * ?", "at 09:00 on the weekday nearest to day 15 of the month"},
+		{"0 15 10 ? * 6#3", "at 10:15 on the third Friday of the month"},
+		{"0 0 9 ? * 2L", "at 09:00 on the last Monday of the month"},
+		{"30 9 * * MON-FRI", "at 09:30 on weekdays"},
+		{"@daily", "at 00:00 every day"},
+		{"@every 90m", "every 90 minutes"},
+		{"1,2,3,5,7,37,44,51 * * * * ?", "1,2,3,5,7,37,44,51 * * * * ?"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.expression, func(t *testing.T) {
+			cronTrigger, err := quartz.NewCronTrigger(test.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, cronTrigger.DescribeHuman(), test.expected)
+		})
+	}
+}
+
+func TestCronDescribeHumanLimits(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cronTrigger, err := quartz.NewCronTriggerWithLoc("0 0 9 * * ?", loc, quartz.WithRepeatCount(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, cronTrigger.DescribeHuman(), "at 09:00 every day (America/New_York), 3 times")
+}
+
 func TestCronDaysOfWeek(t *testing.T) {
 	daysOfWeek := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
 	expected := []string{
@@ -314,6 +468,216 @@ func TestCronHourly(t *testing.T) {
 	assertEqual(t, result, "Mon May 27 10:00:00 2019")
 }
 
+func TestCronSpecialCharacters(t *testing.T) {
+	tests := []struct {
+		expression string
+		prev       time.Time
+		expected   []string
+	}{
+		{"0 0 0 L * ?", time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC), []string{
+			"2023-12-31", "2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30",
+		}},
+		{"0 0 0 L 2 ?", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2023-02-28", "2024-02-29", "2025-02-28",
+		}},
+		{"0 30 10 L * ?", time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC), []string{
+			"2024-02-29 10:30", "2024-03-31 10:30",
+		}},
+		{"0 0 0 LW * ?", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-31", "2024-02-29", "2024-03-29", "2024-04-30", "2024-05-31",
+			"2024-06-28", "2024-07-31", "2024-08-30", "2024-09-30",
+		}},
+		{"0 0 0 15W * ?", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-05-15", "2024-06-14", "2024-07-15", "2024-08-15", "2024-09-16",
+		}},
+		{"0 0 0 1W * ?", time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), []string{
+			"2024-06-03", "2024-07-01", "2024-08-01",
+		}},
+		{"0 0 0 31W * ?", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-31", "2024-03-29", "2024-05-31", "2024-07-31",
+		}},
+		{"0 0 0 ? * 6L", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-26", "2024-02-23", "2024-03-29", "2024-04-26",
+		}},
+		{"0 0 0 ? * THUL", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-25", "2024-02-29", "2024-03-28",
+		}},
+		{"0 0 0 ? * TUE#2", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-09", "2024-02-13", "2024-03-12",
+		}},
+		{"0 0 0 ? * 6#5", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-03-29", "2024-05-31", "2024-08-30", "2024-11-29",
+		}},
+		{"0 0 0 ? * L", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-01-06", "2024-01-13", "2024-01-20",
+		}},
+		{"0 0 0 31 * ?", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC), []string{
+			"2023-03-31", "2023-05-31", "2023-07-31",
+		}},
+		{"0 0 0 29 2 ?", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), []string{
+			"2024-02-29", "2028-02-29",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			cronTrigger, err := quartz.NewCronTrigger(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prev := tt.prev.UnixNano()
+			for _, expected := range tt.expected {
+				prev, err = cronTrigger.NextFireTime(prev)
+				if err != nil {
+					t.Fatal(err)
+				}
+				layout := "2006-01-02"
+				if len(expected) > len(layout) {
+					layout = "2006-01-02 15:04"
+				}
+				assertEqual(t, time.Unix(0, prev).UTC().Format(layout), expected)
+			}
+		})
+	}
+}
+
+func TestCronStandardExpression(t *testing.T) {
+	prev := time.Date(2024, 1, 6, 10, 7, 30, 0, time.UTC) // Saturday
+	tests := []struct {
+		expression  string
+		description string
+		expected    []string
+	}{
+		{"0 9 * * MON-FRI", "CronTrigger 0 0 9 * * MON-FRI UTC", []string{
+			"2024-01-08 09:00:00", "2024-01-09 09:00:00",
+		}},
+		{"0 9 * * 1-5", "CronTrigger 0 0 9 * * MON,TUE,WED,THU,FRI UTC", []string{
+			"2024-01-08 09:00:00", "2024-01-09 09:00:00",
+		}},
+		{"*/15 * * * *", "CronTrigger 0 */15 * * * * UTC", []string{
+			"2024-01-06 10:15:00", "2024-01-06 10:30:00",
+		}},
+		{"30  8 1 * *", "CronTrigger 0 30 8 1 * * UTC", []string{
+			"2024-02-01 08:30:00", "2024-03-01 08:30:00",
+		}},
+		{"@daily", "CronTrigger 0 0 0 * * * UTC", []string{
+			"2024-01-07 00:00:00", "2024-01-08 00:00:00",
+		}},
+		{"@every 90m", "CronTrigger @every 1h30m0s UTC", []string{
+			"2024-01-06 11:37:30", "2024-01-06 13:07:30",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			cronTrigger, err := quartz.NewCronTrigger(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, cronTrigger.Description(), tt.description)
+
+			next := prev.UnixNano()
+			for _, expected := range tt.expected {
+				next, err = cronTrigger.NextFireTime(next)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertEqual(t, time.Unix(0, next).UTC().Format("2006-01-02 15:04:05"), expected)
+			}
+		})
+	}
+}
+
+func TestCronStandardDayOfWeek(t *testing.T) {
+	cronTrigger, err := quartz.NewCronTrigger("* * * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Unix numbering counts the days from Sunday as 0
+	from := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC) // Saturday
+	fireTimes, err := cronTrigger.NextN(from.UnixNano(), 5*24*60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fires := make(map[time.Weekday]int)
+	for _, fireTime := range fireTimes {
+		fires[time.Unix(0, fireTime).UTC().Weekday()]++
+	}
+	assertEqual(t, fires, map[time.Weekday]int{
+		time.Monday:    24 * 60,
+		time.Tuesday:   24 * 60,
+		time.Wednesday: 24 * 60,
+		time.Thursday:  24 * 60,
+		time.Friday:    24 * 60,
+	})
+}
+
+func TestCronNextN(t *testing.T) {
+	from := time.Date(2023, 6, 1, 15, 0, 0, 0, time.UTC).UnixNano()
+
+	cronTrigger, err := quartz.NewCronTrigger("0 0 12 * * ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times, err := cronTrigger.NextN(from, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(times), 3)
+	for i, next := range times {
+		expected := time.Date(2023, 6, 2+i, 12, 0, 0, 0, time.UTC)
+		assertEqual(t, time.Unix(0, next).UTC(), expected)
+	}
+
+	// the trigger state is not changed
+	next, err := cronTrigger.NextFireTime(from)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, times[0])
+}
+
+func TestCronExpired(t *testing.T) {
+	from := time.Date(2023, 6, 1, 15, 0, 0, 0, time.UTC).UnixNano()
+
+	cronTrigger, err := quartz.NewCronTrigger("0 0 0 1 1 ? 2024,2025")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times, err := cronTrigger.NextN(from, 5)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+	assertEqual(t, len(times), 2)
+	assertEqual(t, time.Unix(0, times[1]).UTC(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, expression := range []string{
+		"0 0 0 1 1 ? 2020",
+		"0 0 0 29 2 ? 2023",
+		"0 0 0 30 2 ?",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			cronTrigger, err := quartz.NewCronTrigger(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = cronTrigger.NextFireTime(from)
+			assertEqual(t, err, quartz.ErrTriggerExpired)
+		})
+	}
+}
+
+func TestCronEndTime(t *testing.T) {
+	from := time.Date(2023, 6, 1, 15, 0, 0, 0, time.UTC).UnixNano()
+	end := time.Date(2023, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	cronTrigger, err := quartz.NewCronTrigger("0 0 12 * * ?", quartz.WithEndTime(end))
+	if err != nil {
+		t.Fatal(err)
+	}
+	times, err := cronTrigger.NextN(from, 5)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+	assertEqual(t, len(times), 2)
+	assertEqual(t, time.Unix(0, times[1]).UTC(), end)
+}
+
 var readDateLayout = "Mon Jan 2 15:04:05 2006"
 
 func iterate(prev int64, cronTrigger *quartz.CronTrigger, iterations int) (string, error) {
@@ -329,9 +693,264 @@ func iterate(prev int64, cronTrigger *quartz.CronTrigger, iterations int) (strin
 	return time.Unix(prev/int64(time.Second), 0).UTC().Format(readDateLayout), nil
 }
 
+func TestValidateCronExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		field      int
+		fieldName  string
+		token      string
+		cause      string
+	}{
+		{"60 * * * * ?", 0, "seconds", "60", "value out of range"},
+		{"*/0 * * * * ?", 0, "seconds", "0", "invalid step"},
+		{"30-10 * * * * ?", 0, "seconds", "30-10", "reversed range"},
+		{"X * * * * ?", 0, "seconds", "X", "invalid value"},
+		{"0 5,60 * * * ?", 1, "minutes", "60", "value out of range"},
+		{"0 */X * * * ?", 1, "minutes", "X", "invalid step"},
+		{"0 50-10 * * * ?", 1, "minutes", "50-10", "reversed range"},
+		{"0 5,X * * * ?", 1, "minutes", "X", "invalid value"},
+		{"0 0 24 * * ?", 2, "hours", "24", "value out of range"},
+		{"0 0 0/-1 * * ?", 2, "hours", "-1", "invalid step"},
+		{"0 0 18-9 * * ?", 2, "hours", "18-9", "reversed range"},
+		{"0 0 NOON * * ?", 2, "hours", "NOON", "invalid value"},
+		{"0 0 0 32 * ?", 3, "day of month", "32", "value out of range"},
+		{"0 0 0 1/0 * ?", 3, "day of month", "0", "invalid step"},
+		{"0 0 0 20-10 * ?", 3, "day of month", "20-10", "reversed range"},
+		{"0 0 0 32W * ?", 3, "day of month", "32", "value out of range"},
+		{"0 0 0 XW * ?", 3, "day of month", "X", "invalid value"},
+		{"0 0 0 * 13 ?", 4, "month", "13", "value out of range"},
+		{"0 0 0 * 1/X ?", 4, "month", "X", "invalid step"},
+		{"0 0 0 * DEC-JAN ?", 4, "month", "DEC-JAN", "reversed range"},
+		{"0 0 0 * JAN,FOO ?", 4, "month", "FOO", "unknown name"},
+		{"0 0 0 ? * 8", 5, "day of week", "8", "value out of range"},
+		{"0 0 0 ? * 1/0", 5, "day of week", "0", "invalid step"},
+		{"0 0 0 ? * FRI-MON", 5, "day of week", "FRI-MON", "reversed range"},
+		{"0 0 0 ? * FUN", 5, "day of week", "FUN", "unknown name"},
+		{"0 0 0 ? * TUE#6", 5, "day of week", "6", "value out of range"},
+		{"0 0 0 ? * FUNL", 5, "day of week", "FUN", "unknown name"},
+		{"0 0 0 1 * MON", 5, "day of week", "MON", "day field was set twice"},
+		{"0 0 0 * * ? 1969", 6, "year", "1969", "value out of range"},
+		{"0 0 0 * * ? 2020/0", 6, "year", "0", "invalid step"},
+		{"0 0 0 * * ? 2030-2020", 6, "year", "2030-2020", "reversed range"},
+		{"0 0 0 * * ? X", 6, "year", "X", "invalid value"},
+		{"0 24 * * *", 1, "hours", "24", "value out of range"},
+		{"0 0 * * FUN", 4, "day of week", "FUN", "unknown name"},
+		{"@every 5x", 1, "duration", "5x", "invalid @every duration"},
+		{"* * * *", -1, "", "", "invalid expression length"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.expression, func(t *testing.T) {
+			err := quartz.ValidateCronExpression(test.expression)
+			var parseErr *quartz.CronParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected CronParseError, got %v", err)
+			}
+			assertEqual(t, parseErr.Field, test.field)
+			assertEqual(t, parseErr.FieldName, test.fieldName)
+			assertEqual(t, parseErr.Token, test.token)
+			assertEqual(t, parseErr.Cause, test.cause)
+
+			_, err = quartz.NewCronTrigger(test.expression)
+			assertEqual(t, err.Error(), parseErr.Error())
+		})
+	}
+}
+
+func TestValidateCronExpressionValid(t *testing.T) {
+	for _, expression := range []string{"0 0/5 9-17 ? * MON-FRI", "0 9 * * 1-5", "@hourly", "@every 1m"} {
+		assertEqual(t, quartz.ValidateCronExpression(expression), nil)
+	}
+}
+
+func TestCronNameAliases(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	nextN := func(t *testing.T, expr string) []int64 {
+		t.Helper()
+		trigger, err := quartz.NewCronTrigger(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		times, err := trigger.NextN(from, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return times
+	}
+	aliases := func(name string) []string {
+		title := strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		return []string{
+			strings.ToUpper(name[:3]), strings.ToLower(name[:3]), title[:3],
+			strings.ToUpper(name), strings.ToLower(name), title,
+		}
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		number := strconv.Itoa(int(day) + 1)
+		expected := nextN(t, "0 0 12 ? * "+number)
+		for _, alias := range aliases(day.String()) {
+			t.Run(alias, func(t *testing.T) {
+				assertEqual(t, nextN(t, "0 0 12 ? * "+alias), expected)
+				assertEqual(t, nextN(t, "0 12 * * "+alias), expected)
+				assertEqual(t, nextN(t, "0 0 12 * * "+alias), expected)
+				assertEqual(t, nextN(t, "0 0 12 ? * "+alias+"#2"), nextN(t, "0 0 12 ? * "+number+"#2"))
+				assertEqual(t, nextN(t, "0 0 12 ? * "+alias+"L"), nextN(t, "0 0 12 ? * "+number+"L"))
+			})
+		}
+	}
+	for month := time.January; month <= time.December; month++ {
+		expected := nextN(t, fmt.Sprintf("0 0 12 1 %d ?", month))
+		for _, alias := range aliases(month.String()) {
+			t.Run(alias, func(t *testing.T) {
+				assertEqual(t, nextN(t, "0 0 12 1 "+alias+" ?"), expected)
+			})
+		}
+	}
+
+	// the names are also accepted in the lists and the ranges
+	assertEqual(t, nextN(t, "0 0 12 ? * monday-Friday"), nextN(t, "0 0 12 ? * 2-6"))
+	assertEqual(t, nextN(t, "0 0 12 ? * sun,Wednesday"), nextN(t, "0 0 12 ? * 1,4"))
+	assertEqual(t, nextN(t, "0 0 12 1 january-MAR ?"), nextN(t, "0 0 12 1 1-3 ?"))
+	assertEqual(t, nextN(t, "0 0 12 1 feb,July ?"), nextN(t, "0 0 12 1 2,7 ?"))
+
+	for _, expr := range []string{
+		"0 0 12 ? * 0",
+		"0 0 12 ? * 8",
+		"0 0 12 ? * SUNDAYS",
+		"0 0 12 ? * JAN",
+		"0 0 12 1 MON ?",
+		"0 0 12 1 Janu ?",
+		"? 0 12 * * *",
+		"0 ? 12 * * *",
+		"0 0 12 1 ? *",
+		"0 0 12 * * * ?",
+		"0 0 12 15 * MON",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			var parseErr *quartz.CronParseError
+			if err := quartz.ValidateCronExpression(expr); !errors.As(err, &parseErr) {
+				t.Fatal("expected CronParseError, got", err)
+			}
+		})
+	}
+}
+
+func TestCronUnixDayOfWeek(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) // Saturday
+	weekdays := func(t *testing.T, expr string) []time.Weekday {
+		t.Helper()
+		trigger, err := quartz.NewCronTrigger(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fireTimes, err := trigger.NextN(from.UnixNano(), 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var days []time.Weekday
+		for _, fireTime := range fireTimes {
+			if next := time.Unix(0, fireTime).UTC(); next.Before(from.AddDate(0, 0, 7)) {
+				days = append(days, next.Weekday())
+			}
+		}
+		return days
+	}
+
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	all := []time.Weekday{time.Saturday, time.Sunday, time.Monday, time.Tuesday,
+		time.Wednesday, time.Thursday, time.Friday}
+	for _, tt := range []struct {
+		dayOfWeek string
+		expected  []time.Weekday
+	}{
+		{"0", []time.Weekday{time.Sunday}},
+		{"7", []time.Weekday{time.Sunday}},
+		{"SUN", []time.Weekday{time.Sunday}},
+		{"sunday", []time.Weekday{time.Sunday}},
+		{"6", []time.Weekday{time.Saturday}},
+		{"0-6", all},
+		{"1-7", all},
+		{"*", all},
+		{"1-5", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"5-7", []time.Weekday{time.Saturday, time.Sunday, time.Friday}},
+		{"6-7", weekend},
+		{"0,6", weekend},
+		{"sat-sun", weekend},
+		{"*/2", []time.Weekday{time.Saturday, time.Sunday, time.Tuesday, time.Thursday}},
+	} {
+		t.Run(tt.dayOfWeek, func(t *testing.T) {
+			assertEqual(t, weekdays(t, "0 12 * * "+tt.dayOfWeek), tt.expected)
+		})
+	}
+
+	// the day of the L and # forms follows the same numbering
+	for expr, quartzExpr := range map[string]string{
+		"0 12 ? * 5L":  "0 0 12 ? * 6L",
+		"0 12 ? * 0#1": "0 0 12 ? * 1#1",
+		"0 12 ? * 7#2": "0 0 12 ? * SUN#2",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			trigger, err := quartz.NewCronTrigger(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			quartzTrigger, err := quartz.NewCronTrigger(quartzExpr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, _ := quartzTrigger.NextN(from.UnixNano(), 3)
+			fireTimes, _ := trigger.NextN(from.UnixNano(), 3)
+			assertEqual(t, fireTimes, expected)
+		})
+	}
+
+	// the 6-field expressions keep the Quartz numbering
+	assertEqual(t, weekdays(t, "0 0 12 ? * 7"), []time.Weekday{time.Saturday})
+	assertEqual(t, weekdays(t, "0 0 12 ? * 1"), []time.Weekday{time.Sunday})
+
+	for _, expr := range []string{"0 12 * * 8", "0 12 * * 6-1", "0 12 * * 8L", "0 12 * * FUNDAY"} {
+		t.Run(expr, func(t *testing.T) {
+			var parseErr *quartz.CronParseError
+			err := quartz.ValidateCronExpression(expr)
+			if !errors.As(err, &parseErr) {
+				t.Fatal("expected CronParseError, got", err)
+			}
+			assertEqual(t, parseErr.Field, 4)
+		})
+	}
+}
+
+func TestCronParseErrorMessage(t *testing.T) {
+	err := quartz.ValidateCronExpression("0 0 25 * * ?")
+	assertEqual(t, err.Error(),
+		`invalid cron expression: value out of range at hours field 2: "25", allowed: 0-23 , - * /`)
+
+	err = quartz.ValidateCronExpression("* * * *")
+	assertEqual(t, err.Error(), "invalid cron expression: invalid expression length")
+}
+
 func TestCronExpressionError(t *testing.T) {
 	tests := []string{
 		"*/X * * * * *",
+		"0 0 0 1-L * ?",
+		"0 0 0 L,15 * ?",
+		"0 0 0 L/2 * ?",
+		"0 0 0 1,15W * ?",
+		"0 0 0 32W * ?",
+		"0 0 0 XW * ?",
+		"0 0 0 ? * TUE#2,THU#2",
+		"0 0 0 ? * 2-6#2",
+		"0 0 0 ? * TUE#6",
+		"0 0 0 ? * TUE#",
+		"0 0 0 ? * 8L",
+		"0 0 0 ? * 1-5L",
+		"0 0 0 L * 6L",
+		"* * * *",
+		"@every",
+		"@every 0s",
+		"@every -1m",
+		"@every 5x",
 	}
 	for _, test := range tests {
 		t.Run(test, func(t *testing.T) {
@@ -0,0 +1,331 @@
+package quartz
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CrontabError is returned for an invalid or unschedulable crontab entry.
+type CrontabError struct {
+	// Line is the one-based line number of the entry.
+	Line int
+	// Err is the cause of the error, e.g. a *CronParseError.
+	Err error
+}
+
+// Error returns the string representation of the CrontabError.
+func (e *CrontabError) Error() string {
+	return fmt.Sprintf("crontab line %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the cause of the CrontabError.
+func (e *CrontabError) Unwrap() error {
+	return e.Err
+}
+
+// crontabTimezone is the environment variable setting the location of
+// the subsequent crontab entries.
+const crontabTimezone = "CRON_TZ"
+
+// the pre-defined crontab schedules, @reboot excluded
+var crontabMacros = map[string]string{
+	"@yearly":   "@yearly",
+	"@annually": "@yearly",
+	"@monthly":  "@monthly",
+	"@weekly":   "@weekly",
+	"@daily":    "@daily",
+	"@midnight": "@daily",
+	"@hourly":   "@hourly",
+}
+
+var crontabEnvLine = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+
+// crontabEntry is a parsed crontab entry.
+type crontabEntry struct {
+	line    int
+	job     *ShellJob
+	trigger Trigger
+}
+
+// LoadCrontab parses the crontab read from r and schedules a ShellJob per
+// entry, returning the keys of the scheduled jobs.
+//
+// The classic user crontab syntax is supported: comments, blank lines,
+// environment variable assignments, the 5-field expressions with the 0-7
+// (SUN-SAT) day-of-week numbering, and the @reboot, @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly macros. As in cron, an
+// entry restricting both the day of month and the day of week fires on the
+// days matching either of them.
+//
+// The environment variables are passed to the ShellJobs of the subsequent
+// entries. The entries are evaluated in the local time zone, unless set by
+// a CRON_TZ variable. The @reboot entries fire once, immediately, so that
+// the crontab should be loaded into a started scheduler.
+//
+// Each ShellJob is assigned a UniqueJobKey, so that the entries running
+// the same command are scheduled independently. The crontab is parsed
+// before any of the jobs is scheduled, and the jobs scheduled before a
+// scheduling error are deleted, so that an error schedules nothing. The
+// errors of the entries are reported as a *CrontabError.
+func LoadCrontab(ctx context.Context, sched Scheduler, r io.Reader) ([]int, error) {
+	entries, err := parseCrontab(r)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		if err := sched.ScheduleJob(ctx, entry.job, entry.trigger); err != nil {
+			for _, key := range keys {
+				_ = sched.DeleteJob(key)
+			}
+			return nil, &CrontabError{Line: entry.line, Err: err}
+		}
+		keys = append(keys, entry.job.Key())
+	}
+
+	return keys, nil
+}
+
+// parseCrontab parses the entries of the crontab.
+func parseCrontab(r io.Reader) ([]crontabEntry, error) {
+	var entries []crontabEntry
+	var env []string
+	location := time.Local
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if match := crontabEnvLine.FindStringSubmatch(text); match != nil {
+			name, value := match[1], unquote(strings.TrimSpace(match[2]))
+			if name != crontabTimezone {
+				env = append(env, name+"="+value)
+				continue
+			}
+			loc, err := time.LoadLocation(value)
+			if err != nil {
+				return nil, &CrontabError{Line: line, Err: err}
+			}
+			location = loc
+			continue
+		}
+
+		trigger, cmd, err := parseCrontabEntry(text, location)
+		if err != nil {
+			return nil, &CrontabError{Line: line, Err: err}
+		}
+		job := NewShellJob(cmd).WithKey(UniqueJobKey())
+		job.Env = append([]string(nil), env...)
+		entries = append(entries, crontabEntry{
+			line:    line,
+			job:     job,
+			trigger: trigger,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseCrontabEntry parses the schedule and the command of a crontab entry.
+func parseCrontabEntry(text string, location *time.Location) (Trigger, string, error) {
+	if strings.HasPrefix(text, "@") {
+		fields, cmd := splitFields(text, 1)
+		if cmd == "" {
+			return nil, "", fmt.Errorf("missing command")
+		}
+		if fields[0] == "@reboot" {
+			return NewRunOnceTrigger(0), cmd, nil
+		}
+		expr, ok := crontabMacros[fields[0]]
+		if !ok {
+			return nil, "", fmt.Errorf("unknown macro %q", fields[0])
+		}
+		trigger, err := NewCronTriggerWithLoc(expr, location)
+		return trigger, cmd, err
+	}
+
+	fields, cmd := splitFields(text, 5)
+	if len(fields) < 5 {
+		return nil, "", &CronParseError{Field: -1, Cause: "invalid expression length"}
+	}
+	if cmd == "" {
+		return nil, "", fmt.Errorf("missing command")
+	}
+	dayOfWeek, err := unixDayOfWeek(fields[4])
+	if err != nil {
+		return nil, "", err
+	}
+	minute, hour, dayOfMonth, month := fields[0], fields[1], fields[2], fields[3]
+
+	// either of the restricted day fields matches
+	if dayOfMonth != "*" && dayOfWeek != "*" {
+		byMonthDay, err := NewCronTriggerWithLoc(strings.Join([]string{minute, hour, dayOfMonth, month, "*"}, " "),
+			location)
+		if err != nil {
+			return nil, "", err
+		}
+		byWeekday, err := NewCronTriggerWithLoc(strings.Join([]string{minute, hour, "*", month, dayOfWeek}, " "),
+			location)
+		if err != nil {
+			return nil, "", err
+		}
+		return NewUnionTrigger(byMonthDay, byWeekday), cmd, nil
+	}
+
+	trigger, err := NewCronTriggerWithLoc(strings.Join([]string{minute, hour, dayOfMonth, month, dayOfWeek}, " "),
+		location)
+	return trigger, cmd, err
+}
+
+// splitFields splits the first n whitespace separated fields of the text,
+// returning the rest of the text as is.
+func splitFields(text string, n int) ([]string, string) {
+	fields := make([]string, 0, n)
+	for len(fields) < n {
+		text = strings.TrimLeft(text, " \t")
+		if text == "" {
+			break
+		}
+		end := strings.IndexAny(text, " \t")
+		if end < 0 {
+			end = len(text)
+		}
+		fields = append(fields, text[:end])
+		text = text[end:]
+	}
+
+	return fields, strings.TrimSpace(text)
+}
+
+// unquote removes the matching single or double quotes around the value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// unixDayOfWeek translates the day-of-week field of the crontab entries and
+// of the 5-field expressions, numbered 0-7 with both 0 and 7 for Sunday, to
+// the list of the day names. The day of the L and # forms is translated to
+// its name.
+func unixDayOfWeek(field string) (string, error) {
+	if field == "*" || field == "?" || field == "L" {
+		return field, nil
+	}
+
+	if i := strings.IndexAny(field, "L#"); i >= 0 {
+		if strings.ContainsAny(field, ",-/") {
+			// rejected by the day-of-week parser
+			return field, nil
+		}
+		weekday, err := parseCrontabWeekday(field[:i])
+		if err != nil {
+			return "", dayOfWeekError(fieldError(field[:i], err.Error()))
+		}
+		return days[weekday%7+1] + field[i:], nil
+	}
+
+	weekdays := make(map[int]bool)
+	for _, item := range strings.Split(field, ",") {
+		if err := expandCrontabWeekdays(item, weekdays); err != nil {
+			return "", dayOfWeekError(err)
+		}
+	}
+
+	values := make([]int, 0, len(weekdays))
+	for weekday := range weekdays {
+		values = append(values, weekday)
+	}
+	sort.Ints(values)
+	names := make([]string, len(values))
+	for i, weekday := range values {
+		names[i] = days[weekday+1]
+	}
+
+	return strings.Join(names, ","), nil
+}
+
+// dayOfWeekError completes the error of the Unix day-of-week field.
+func dayOfWeekError(err *CronParseError) *CronParseError {
+	err.Field = 4
+	err.FieldName = cronFieldSpecs[5].name
+	err.Allowed = "0-7 or SUN-SAT , - * /"
+	return err
+}
+
+// expandCrontabWeekdays adds the zero-based weekdays of the list item to
+// the set.
+func expandCrontabWeekdays(item string, weekdays map[int]bool) *CronParseError {
+	base, step := item, 1
+	if i := strings.Index(item, "/"); i >= 0 {
+		var err error
+		base = item[:i]
+		if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+			return fieldError(item, "invalid step")
+		}
+	}
+
+	first, last := 0, 7
+	if base != "*" {
+		bounds := strings.Split(base, "-")
+		if len(bounds) > 2 {
+			return fieldError(item, "invalid range")
+		}
+		var err error
+		if first, err = parseCrontabWeekday(bounds[0]); err != nil {
+			return fieldError(bounds[0], err.Error())
+		}
+		switch {
+		case len(bounds) == 2:
+			if last, err = parseCrontabWeekday(bounds[1]); err != nil {
+				return fieldError(bounds[1], err.Error())
+			}
+		case step == 1:
+			last = first
+		}
+		if last == 0 && first > 0 {
+			// a range ending on Sunday, e.g. SAT-SUN, ends the week
+			last = 7
+		}
+		if first > last {
+			return fieldError(item, "reversed range")
+		}
+	}
+
+	for weekday := first; weekday <= last; weekday += step {
+		weekdays[weekday%7] = true
+	}
+
+	return nil
+}
+
+// parseCrontabWeekday parses a 0-7 numbered or named day of the week.
+func parseCrontabWeekday(token string) (int, error) {
+	if weekday, err := strconv.Atoi(token); err == nil {
+		if !inScope(weekday, 0, 7) {
+			return 0, fmt.Errorf("value out of range")
+		}
+		return weekday, nil
+	}
+	if weekday := intVal(days, token); weekday > 0 {
+		return weekday - 1, nil
+	}
+
+	return 0, fmt.Errorf("unknown name")
+}
@@ -0,0 +1,214 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+const crontab = `# backups
+SHELL=/bin/sh
+MAILTO = "ops@example.com"
+CRON_TZ=UTC
+
+30 2 * * *	/usr/local/bin/backup --full
+*/15 9-17 * * 1-5 echo "working hours"
+0 0 13 * 5 echo friday the 13th
+0 12 * * 7 echo sunday
+@midnight echo midnight
+@reboot echo started
+`
+
+func TestLoadCrontab(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 3, 10, 5, 0, 0, time.UTC) // Monday
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock: quartz.NewMockClock(now),
+	})
+
+	keys, err := quartz.LoadCrontab(ctx, sched, strings.NewReader(crontab))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(keys), 6)
+
+	expected := []struct {
+		cmd  string
+		next time.Time
+	}{
+		{"/usr/local/bin/backup --full", time.Date(2024, 6, 4, 2, 30, 0, 0, time.UTC)},
+		{`echo "working hours"`, time.Date(2024, 6, 3, 10, 15, 0, 0, time.UTC)},
+		{"echo friday the 13th", time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)},
+		{"echo sunday", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"echo midnight", time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)},
+		{"echo started", now},
+	}
+	for i, key := range keys {
+		scheduled, err := sched.GetScheduledJob(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		job := scheduled.Job.(*quartz.ShellJob)
+		assertEqual(t, job.Cmd, expected[i].cmd)
+		assertEqual(t, strings.Join(job.Env, " "), "SHELL=/bin/sh MAILTO=ops@example.com")
+		assertEqual(t, time.Unix(0, scheduled.NextRunTime).UTC(), expected[i].next)
+	}
+}
+
+func TestLoadCrontabEitherDayField(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC) // Saturday
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock: quartz.NewMockClock(now),
+	})
+
+	keys, err := quartz.LoadCrontab(ctx, sched, strings.NewReader("CRON_TZ=UTC\n0 0 13 * 5 echo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := quartz.NewUnionTrigger(
+		mustCronTrigger(t, "0 0 0 13 * ?"),
+		mustCronTrigger(t, "0 0 0 ? * FRI"),
+	)
+	prev := now.UnixNano()
+	scheduled, err := sched.GetScheduledJob(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := trigger.NextFireTime(prev)
+	assertEqual(t, err, nil)
+	assertEqual(t, scheduled.NextRunTime, next)
+	assertEqual(t, time.Unix(0, next).UTC(), time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, err, nil)
+	assertEqual(t, time.Unix(0, next).UTC(), time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+}
+
+func TestLoadCrontabDayOfWeekAliases(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 3, 10, 5, 0, 0, time.UTC) // Monday
+	for _, tt := range []struct {
+		dayOfWeek string
+		next      time.Time
+	}{
+		{"0", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"7", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"sun", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"SUN", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"Sunday", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"sunday", time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"6-7", time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)},
+		{"sat-sun", time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)},
+		{"mon-fri", time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)},
+		{"Tuesday-Friday", time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC)},
+		{"wed,Thursday", time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(tt.dayOfWeek, func(t *testing.T) {
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				Clock:  quartz.NewMockClock(now),
+				Logger: quartz.NewNoopLogger(),
+			})
+			keys, err := quartz.LoadCrontab(ctx, sched,
+				strings.NewReader("CRON_TZ=UTC\n0 12 * * "+tt.dayOfWeek+" echo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			scheduled, err := sched.GetScheduledJob(keys[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, time.Unix(0, scheduled.NextRunTime).UTC(), tt.next)
+		})
+	}
+}
+
+func mustCronTrigger(t *testing.T, expr string) *quartz.CronTrigger {
+	t.Helper()
+	trigger, err := quartz.NewCronTrigger(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trigger
+}
+
+func TestLoadCrontabError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		crontab string
+		line    int
+		message string
+	}{
+		{"# comment\n\n0 25 * * * echo", 3,
+			`crontab line 3: invalid cron expression: value out of range at hours field 1: "25", allowed: 0-23 , - * /`},
+		{"0 0 * * 8 echo", 1,
+			`crontab line 1: invalid cron expression: value out of range at day of week field 4: "8", ` +
+				"allowed: 0-7 or SUN-SAT , - * /"},
+		{"0 0 * * 5-1 echo", 1,
+			`crontab line 1: invalid cron expression: reversed range at day of week field 4: "5-1", ` +
+				"allowed: 0-7 or SUN-SAT , - * /"},
+		{"* * * * * echo\n0 0 * *", 2, "crontab line 2: invalid cron expression: invalid expression length"},
+		{"0 0 * * *", 1, "crontab line 1: missing command"},
+		{"@fortnightly echo", 1, `crontab line 1: unknown macro "@fortnightly"`},
+	}
+	for _, tt := range tests {
+		sched := quartz.NewStdScheduler()
+		keys, err := quartz.LoadCrontab(ctx, sched, strings.NewReader(tt.crontab))
+		assertEqual(t, len(keys), 0)
+		var crontabErr *quartz.CrontabError
+		if !errors.As(err, &crontabErr) {
+			t.Fatal("unexpected error", err)
+		}
+		assertEqual(t, crontabErr.Line, tt.line)
+		assertEqual(t, err.Error(), tt.message)
+		// nothing is scheduled on a parse error
+		assertEqual(t, len(sched.GetJobKeys()), 0)
+	}
+
+	// the entries running the same command are scheduled independently
+	sched := quartz.NewStdScheduler()
+	keys, err := quartz.LoadCrontab(ctx, sched, strings.NewReader("0 9 * * * ls\n0 21 * * * ls"))
+	assertEqual(t, err, nil)
+	assertEqual(t, len(keys), 2)
+	assertNotEqual(t, keys[0], keys[1])
+	assertEqual(t, len(sched.GetJobKeys()), 2)
+
+	// the jobs scheduled before a scheduling error are deleted
+	failing := &failingScheduler{Scheduler: quartz.NewStdScheduler(), limit: 1}
+	keys, err = quartz.LoadCrontab(ctx, failing, strings.NewReader("@daily ls\n@hourly pwd"))
+	assertEqual(t, len(keys), 0)
+	var crontabErr *quartz.CrontabError
+	if !errors.As(err, &crontabErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, crontabErr.Line, 2)
+	assertEqual(t, errors.Is(err, errScheduleLimit), true)
+	assertEqual(t, len(failing.GetJobKeys()), 0)
+}
+
+var errScheduleLimit = errors.New("schedule limit")
+
+// failingScheduler fails to schedule the jobs beyond its limit.
+type failingScheduler struct {
+	quartz.Scheduler
+	limit int
+}
+
+func (s *failingScheduler) ScheduleJob(ctx context.Context, job quartz.Job, trigger quartz.Trigger,
+	opts ...quartz.ScheduleOption) error {
+	if len(s.GetJobKeys()) >= s.limit {
+		return errScheduleLimit
+	}
+	return s.Scheduler.ScheduleJob(ctx, job, trigger, opts...)
+}
@@ -10,7 +10,9 @@ func makeCSMFromFields(prev time.Time, fields []*cronField) *CSM.CronStateMachin
 	year := CSM.NewCommonNode(prev.Year(), 0, 999999, fields[6].values)
 	month := CSM.NewCommonNode(int(prev.Month()), 1, 12, fields[4].values)
 	var day *CSM.DayNode
-	if len(fields[5].values) != 0 {
+	if days := specialDays(fields); days != nil {
+		day = CSM.NewSpecialDayNode(prev.Day(), 1, 31, days, month, year)
+	} else if len(fields[5].values) != 0 {
 		day = CSM.NewWeekdayNode(prev.Day(), 1, 31, fields[5].values, month, year)
 	} else {
 		day = CSM.NewMonthdayNode(prev.Day(), 1, 31, fields[3].values, month, year)
@@ -22,3 +24,13 @@ func makeCSMFromFields(prev time.Time, fields []*cronField) *CSM.CronStateMachin
 	csm := CSM.NewCronStateMachine(second, minute, hour, day, month, year)
 	return csm
 }
+
+// specialDays returns the resolver of the day field which uses the
+// L, W or # special characters, if any.
+func specialDays(fields []*cronField) CSM.DaysFunc {
+	if fields[3].days != nil {
+		return fields[3].days
+	}
+
+	return fields[5].days
+}
@@ -0,0 +1,277 @@
+package quartz
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultEventBufferSize is the capacity of the event channel, used when
+// no size is configured.
+const defaultEventBufferSize = 64
+
+// EventType is the type of a SchedulerEvent.
+type EventType int
+
+const (
+	// EventScheduled is published when a Job is scheduled.
+	EventScheduled EventType = iota
+
+	// EventFired is published before a Job is executed.
+	EventFired
+
+	// EventCompleted is published when an execution of a Job
+	// returns no error.
+	EventCompleted
+
+	// EventFailed is published when an execution of a Job returns
+	// an error, including when it panics or times out.
+	EventFailed
+
+	// EventSkipped is published when a fire of a Job is not
+	// executed, e.g. because it is outdated, its previous execution
+	// is still running, or its lock is held by another instance.
+	EventSkipped
+
+	// EventDeleted is published when a Job is removed from the
+	// scheduler.
+	EventDeleted
+
+	// EventUnscheduled is published when a Job leaves the scheduler
+	// because its Trigger returned an error.
+	EventUnscheduled
+
+	// EventStopped is the last event published once the scheduler
+	// is stopped, before the event channel is closed.
+	EventStopped
+
+	// EventKeyCollision is published when a Job is scheduled with
+	// the key of a scheduled Job with a different description.
+	EventKeyCollision
+)
+
+// String returns the name of the EventType.
+func (t EventType) String() string {
+	switch t {
+	case EventScheduled:
+		return "scheduled"
+	case EventFired:
+		return "fired"
+	case EventCompleted:
+		return "completed"
+	case EventFailed:
+		return "failed"
+	case EventSkipped:
+		return "skipped"
+	case EventDeleted:
+		return "deleted"
+	case EventUnscheduled:
+		return "unscheduled"
+	case EventStopped:
+		return "stopped"
+	case EventKeyCollision:
+		return "key collision"
+	default:
+		return "unknown"
+	}
+}
+
+// SchedulerEvent is an event of the StdScheduler, received from the
+// channel returned by Events.
+type SchedulerEvent struct {
+	// Type is the type of the event.
+	Type EventType
+
+	// Key is the key of the Job, zero for EventStopped.
+	Key JobKey
+
+	// Time is the time the event occurred at.
+	Time time.Time
+
+	// ScheduledTime is the time the fire was scheduled at, for the
+	// events of the fires, or the next run time of the Job for
+	// EventScheduled.
+	ScheduledTime time.Time
+
+	// Duration is the duration of the execution, for EventCompleted
+	// and EventFailed.
+	Duration time.Duration
+
+	// Err is the error of the execution for EventFailed, or the
+	// error of the Trigger for EventUnscheduled.
+	Err error
+}
+
+// eventStream publishes the scheduler events to a bounded channel,
+// dropping the oldest event when the channel is full.
+type eventStream struct {
+	NoopListener
+	mtx     sync.Mutex
+	ch      chan SchedulerEvent
+	clock   Clock
+	dropped *uint64
+	closing bool
+	closed  bool
+}
+
+// Verify eventStream satisfies the SchedulerListener interface.
+var _ SchedulerListener = (*eventStream)(nil)
+
+// publish sends the event without blocking, dropping the oldest events
+// of the channel to make room for it.
+func (e *eventStream) publish(event SchedulerEvent) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.closed {
+		return
+	}
+	event.Time = e.clock.Now()
+	for {
+		select {
+		case e.ch <- event:
+			return
+		default:
+		}
+		select {
+		case <-e.ch:
+			atomic.AddUint64(e.dropped, 1)
+		default:
+		}
+	}
+}
+
+// close publishes the EventStopped event and closes the channel.
+func (e *eventStream) close() {
+	e.publish(SchedulerEvent{Type: EventStopped})
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.closed = true
+	close(e.ch)
+}
+
+// fired publishes the event of the fire of the Job.
+func (e *eventStream) fired(eventType EventType, job ScheduledJob) {
+	e.publish(SchedulerEvent{
+		Type:          eventType,
+		Key:           job.Key,
+		ScheduledTime: time.Unix(0, job.NextRunTime),
+	})
+}
+
+// JobScheduled publishes an EventScheduled.
+func (e *eventStream) JobScheduled(job ScheduledJob) {
+	e.fired(EventScheduled, job)
+}
+
+// JobDeleted publishes an EventDeleted.
+func (e *eventStream) JobDeleted(key JobKey) {
+	e.publish(SchedulerEvent{Type: EventDeleted, Key: key})
+}
+
+// BeforeJobExecution publishes an EventFired.
+func (e *eventStream) BeforeJobExecution(job ScheduledJob) {
+	e.fired(EventFired, job)
+}
+
+// AfterJobExecution publishes an EventCompleted, or an EventFailed if
+// the execution returned an error.
+func (e *eventStream) AfterJobExecution(job ScheduledJob, duration time.Duration, err error) {
+	eventType := EventCompleted
+	if err != nil {
+		eventType = EventFailed
+	}
+	e.publish(SchedulerEvent{
+		Type:          eventType,
+		Key:           job.Key,
+		ScheduledTime: time.Unix(0, job.NextRunTime),
+		Duration:      duration,
+		Err:           err,
+	})
+}
+
+// JobSkippedOutdated publishes an EventSkipped.
+func (e *eventStream) JobSkippedOutdated(job ScheduledJob, _ time.Duration) {
+	e.fired(EventSkipped, job)
+}
+
+// JobSkippedConcurrent publishes an EventSkipped.
+func (e *eventStream) JobSkippedConcurrent(job ScheduledJob) {
+	e.fired(EventSkipped, job)
+}
+
+// JobDropped publishes an EventSkipped.
+func (e *eventStream) JobDropped(job ScheduledJob) {
+	e.fired(EventSkipped, job)
+}
+
+// JobSkippedLocked publishes an EventSkipped.
+func (e *eventStream) JobSkippedLocked(job ScheduledJob) {
+	e.fired(EventSkipped, job)
+}
+
+// JobUnscheduled publishes an EventUnscheduled.
+func (e *eventStream) JobUnscheduled(job ScheduledJob, err error) {
+	e.publish(SchedulerEvent{Type: EventUnscheduled, Key: job.Key, Err: err})
+}
+
+// JobKeyCollision publishes an EventKeyCollision.
+func (e *eventStream) JobKeyCollision(job ScheduledJob, _ Job) {
+	e.fired(EventKeyCollision, job)
+}
+
+// Events returns the channel receiving the events of the StdScheduler,
+// an alternative to the SchedulerListener callbacks. The channel holds
+// up to StdSchedulerOptions.EventBufferSize events: the scheduler never
+// blocks on it, and once it is full the oldest event is dropped to make
+// room for the new one, counted by DroppedEvents.
+//
+// The events are published from the call to Events. Once the
+// StdScheduler is stopped and the executions of its jobs have returned,
+// an EventStopped is published and the channel is closed; Events then
+// returns a new channel. The channel of a StdScheduler restarted before
+// its jobs returned remains open until the next stop.
+func (sched *StdScheduler) Events() <-chan SchedulerEvent {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if sched.events == nil {
+		sched.events = &eventStream{
+			ch:      make(chan SchedulerEvent, sched.opts.EventBufferSize),
+			clock:   sched.opts.Clock,
+			dropped: &sched.droppedEvents,
+		}
+	}
+
+	return sched.events.ch
+}
+
+// DroppedEvents returns the number of the events dropped because the
+// channel returned by Events was full.
+func (sched *StdScheduler) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&sched.droppedEvents)
+}
+
+// closeEvents closes the event stream once the jobs of the stopped run
+// have returned. The caller must hold the lock.
+func (sched *StdScheduler) closeEvents() {
+	events := sched.events
+	if events == nil || events.closing {
+		return
+	}
+	events.closing = true
+
+	go func() {
+		sched.wg.Wait()
+
+		sched.mtx.Lock()
+		if sched.events == events {
+			sched.events = nil
+		}
+		sched.mtx.Unlock()
+
+		events.close()
+	}()
+}
@@ -0,0 +1,86 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	events := sched.Events()
+	sched.Start(ctx)
+
+	okKey, failedKey := quartz.NewJobKey("ok"), quartz.NewJobKey("failed")
+	if err := sched.ScheduleJobWithKey(ctx, okKey, quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		return true, nil
+	}), quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	errFailed := errors.New("failed")
+	if err := sched.ScheduleJobWithKey(ctx, failedKey, quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		return false, errFailed
+	}), quartz.NewRunOnceTrigger(20*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(map[quartz.JobKey][]quartz.EventType)
+	var failed quartz.SchedulerEvent
+	for event := range events {
+		received[event.Key] = append(received[event.Key], event.Type)
+		assertEqual(t, event.Time.IsZero(), false)
+		switch event.Type {
+		case quartz.EventFailed:
+			failed = event
+		case quartz.EventUnscheduled:
+			if event.Key == failedKey {
+				sched.Stop()
+			}
+		}
+	}
+
+	// the expiry of the trigger may be reported before the execution returns
+	for _, types := range received {
+		sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	}
+	lifecycle := []quartz.EventType{quartz.EventScheduled, quartz.EventFired}
+	assertEqual(t, received[okKey], append(lifecycle, quartz.EventCompleted, quartz.EventUnscheduled))
+	assertEqual(t, received[failedKey], append(lifecycle, quartz.EventFailed, quartz.EventUnscheduled))
+	assertEqual(t, received[quartz.JobKey{}], []quartz.EventType{quartz.EventStopped})
+	assertEqual(t, errors.Is(failed.Err, errFailed), true)
+	assertEqual(t, sched.DroppedEvents(), uint64(0))
+
+	// a new channel is returned once the previous one is closed
+	assertNotEqual(t, sched.Events(), events)
+}
+
+func TestSchedulerEventsDropOldest(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		EventBufferSize: 2,
+		Logger:          quartz.NewNoopLogger(),
+	})
+	events := sched.Events()
+	for i := 0; i < 5; i++ {
+		if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey(string(rune('a'+i))),
+			quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the scheduler is not blocked, the newest events are kept
+	assertEqual(t, sched.DroppedEvents(), uint64(3))
+	assertEqual(t, (<-events).Key, quartz.NewJobKey("d"))
+	assertEqual(t, (<-events).Key, quartz.NewJobKey("e"))
+}
@@ -0,0 +1,72 @@
+package quartz
+
+import (
+	"context"
+	"time"
+)
+
+// ExecutionContext describes a single execution of a Job. It is attached
+// to the context passed to Execute by the StdScheduler.
+type ExecutionContext struct {
+	// Key is the key of the executed Job.
+	Key JobKey
+
+	// TriggerDescription is the description of the Trigger which
+	// fired the Job.
+	TriggerDescription string
+
+	// ScheduledTime is the time the fire was scheduled at.
+	ScheduledTime time.Time
+
+	// FireTime is the time the execution actually started at.
+	FireTime time.Time
+
+	// RunCount is the number of the started executions of the Job,
+	// including the current one.
+	RunCount int64
+
+	// Misfire is set when the fire is executed although its
+	// scheduled time is outdated.
+	Misfire bool
+
+	// CatchUp is set when the fire is a catch-up run of a fire missed
+	// while the scheduler was not running or the Job was paused, see
+	// CatchUpPolicy.
+	CatchUp bool
+
+	// Data is the data attached to the Job using WithData or
+	// SetJobData, nil if none. It must not be modified.
+	Data map[string]any
+
+	job    *ScheduledJob
+	notify func(func(SchedulerListener))
+}
+
+type executionContextKey struct{}
+
+// withExecutionContext returns a copy of the context carrying the
+// ExecutionContext.
+func withExecutionContext(ctx context.Context, execCtx *ExecutionContext) context.Context {
+	return context.WithValue(ctx, executionContextKey{}, execCtx)
+}
+
+// ExecutionContextFrom returns the ExecutionContext of the current
+// execution, if the context was passed to the Job by the StdScheduler.
+func ExecutionContextFrom(ctx context.Context) (*ExecutionContext, bool) {
+	execCtx, ok := ctx.Value(executionContextKey{}).(*ExecutionContext)
+	return execCtx, ok
+}
+
+// notifyListeners invokes the callback for each of the listeners of the
+// StdScheduler executing the Job, with the snapshot of the executed Job.
+// It does nothing if the context was not passed by a StdScheduler.
+func notifyListeners(ctx context.Context, callback func(SchedulerListener, ScheduledJob)) {
+	execCtx, ok := ExecutionContextFrom(ctx)
+	if !ok || execCtx.notify == nil {
+		return
+	}
+
+	execCtx.notify(func(l SchedulerListener) {
+		callback(l, *execCtx.job)
+	})
+}
@@ -0,0 +1,68 @@
+package quartz
+
+import (
+	"math"
+	"sync"
+)
+
+// ExternalTrigger implements the quartz.WakeableTrigger interface; fires
+// on demand, whenever its Fire method is called, e.g. on the arrival of
+// an upstream event. The fires are executed by the scheduler as those of
+// the other triggers, subject to the worker limits, listeners and paused
+// groups.
+//
+// Until the ExternalTrigger is fired, its next fire time is the maximum
+// int64 value. An ExternalTrigger drives a single scheduled Job.
+type ExternalTrigger struct {
+	mtx     sync.Mutex
+	pending int
+	wakeup  func()
+}
+
+// Verify ExternalTrigger satisfies the WakeableTrigger interface.
+var _ WakeableTrigger = (*ExternalTrigger)(nil)
+
+// NewExternalTrigger returns a new ExternalTrigger.
+func NewExternalTrigger() *ExternalTrigger {
+	return &ExternalTrigger{}
+}
+
+// Fire requests an execution of the Job as soon as possible. The fires
+// requested while the Job is executing are executed once it returns.
+func (et *ExternalTrigger) Fire() {
+	et.mtx.Lock()
+	et.pending++
+	wakeup := et.wakeup
+	et.mtx.Unlock()
+
+	if wakeup != nil {
+		wakeup()
+	}
+}
+
+// NextFireTime returns the next time at which the ExternalTrigger is scheduled to fire.
+// A pending fire is due at once, otherwise the maximum int64 value is returned.
+func (et *ExternalTrigger) NextFireTime(prev int64) (int64, error) {
+	et.mtx.Lock()
+	defer et.mtx.Unlock()
+
+	if et.pending > 0 {
+		et.pending--
+		return prev, nil
+	}
+
+	return math.MaxInt64, nil
+}
+
+// SetWakeup registers the function called by Fire to wake the scheduler.
+func (et *ExternalTrigger) SetWakeup(wakeup func()) {
+	et.mtx.Lock()
+	defer et.mtx.Unlock()
+
+	et.wakeup = wakeup
+}
+
+// Description returns the description of the trigger.
+func (et *ExternalTrigger) Description() string {
+	return "ExternalTrigger"
+}
@@ -0,0 +1,92 @@
+package quartz_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestExternalTrigger(t *testing.T) {
+	trigger := quartz.NewExternalTrigger()
+	assertEqual(t, trigger.Description(), "ExternalTrigger")
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, int64(math.MaxInt64))
+
+	var wakeups int
+	trigger.SetWakeup(func() { wakeups++ })
+	trigger.Fire()
+	trigger.Fire()
+	assertEqual(t, wakeups, 2)
+
+	for i := 0; i < 2; i++ {
+		next, err = trigger.NextFireTime(fromEpoch)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, fromEpoch)
+	}
+	next, _ = trigger.NextFireTime(fromEpoch)
+	assertEqual(t, next, int64(math.MaxInt64))
+}
+
+func TestSchedulerExternalTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  quartz.NewMockClock(time.Unix(0, fromEpoch)),
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	runs := make(chan struct{}, 8)
+	trigger := quartz.NewExternalTrigger()
+	key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		runs <- struct{}{}
+		return nil
+	}, trigger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduled, err := sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.NextRunTime, int64(math.MaxInt64))
+
+	expectRuns := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			select {
+			case <-runs:
+			case <-ctx.Done():
+				t.Fatalf("expected %d runs, got %d", n, i)
+			}
+		}
+		select {
+		case <-runs:
+			t.Fatal("unexpected run")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	trigger.Fire()
+	expectRuns(1)
+
+	for i := 0; i < 3; i++ {
+		trigger.Fire()
+	}
+	expectRuns(3)
+
+	// the fires of a deleted job are ignored
+	if err := sched.DeleteJob(key); err != nil {
+		t.Fatal(err)
+	}
+	trigger.Fire()
+	expectRuns(0)
+}
@@ -0,0 +1,502 @@
+package quartz
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// The operations of the FileJobQueue journal records.
+const (
+	fileOpPush   = "push"
+	fileOpPop    = "pop"
+	fileOpAck    = "ack"
+	fileOpRemove = "remove"
+	fileOpClear  = "clear"
+)
+
+// fileQueueCompactRecords is the number of the journal records below which
+// the journal of a FileJobQueue is not compacted.
+const fileQueueCompactRecords = 1024
+
+// fileRecord is a record of the journal of a FileJobQueue.
+type fileRecord struct {
+	Op          string          `json:"op"`
+	Name        string          `json:"name,omitempty"`
+	Group       string          `json:"group,omitempty"`
+	NextRunTime int64           `json:"next_run_time,omitempty"`
+	Priority    int             `json:"priority,omitempty"`
+	StartTime   int64           `json:"start_time,omitempty"`
+	ExpireTime  int64           `json:"expire_time,omitempty"`
+	Data        map[string]any  `json:"data,omitempty"`
+	Job         json.RawMessage `json:"job,omitempty"`
+	Trigger     json.RawMessage `json:"trigger,omitempty"`
+}
+
+// key returns the JobKey of the record.
+func (r *fileRecord) key() JobKey {
+	return JobKey{Name: r.Name, Group: r.Group}
+}
+
+// newFileRecord returns a new record of the operation on the job.
+func newFileRecord(op string, job *QueuedJob) *fileRecord {
+	return &fileRecord{
+		Op:          op,
+		Name:        job.Key.Name,
+		Group:       job.Key.Group,
+		NextRunTime: job.NextRunTime,
+		Priority:    job.Priority,
+	}
+}
+
+// newPushRecord returns a new push record of the job, holding the encoded
+// Job and Trigger.
+func newPushRecord(job *QueuedJob) (*fileRecord, error) {
+	jobData, err := MarshalJob(job.Job)
+	if err != nil {
+		return nil, err
+	}
+	triggerData, err := MarshalTrigger(job.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	record := newFileRecord(fileOpPush, job)
+	record.Job = jobData
+	record.Trigger = triggerData
+	record.Data = job.Data
+	record.StartTime = job.StartTime
+	record.ExpireTime = job.ExpireTime
+
+	return record, nil
+}
+
+// queuedJob decodes the job of the push record.
+func (r *fileRecord) queuedJob() (*QueuedJob, error) {
+	job, err := UnmarshalJob(r.Job)
+	if err != nil {
+		return nil, err
+	}
+	trigger, err := UnmarshalTrigger(r.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueuedJob{
+		Key:         r.key(),
+		Job:         job,
+		Trigger:     trigger,
+		NextRunTime: r.NextRunTime,
+		Priority:    r.Priority,
+		Data:        r.Data,
+		StartTime:   r.StartTime,
+		ExpireTime:  r.ExpireTime,
+	}, nil
+}
+
+// poppedKey identifies a popped job of a FileJobQueue, which can be popped
+// again before the previous fire is acknowledged.
+type poppedKey struct {
+	key         JobKey
+	nextRunTime int64
+}
+
+// FileJobQueue implements the PersistentJobQueue interface, journaling the
+// queued jobs to a local file. The jobs and their triggers are encoded using
+// MarshalJob and MarshalTrigger, so their types have to be registered. Each
+// operation is appended to the journal and synced to the disk before it takes
+// effect, and the journal is compacted once most of its records are obsolete.
+//
+// When the FileJobQueue is opened, the popped jobs which were not acknowledged
+// are requeued, unless a job with the same key and an earlier next run time is
+// queued. A record torn by an interrupted write at the end of the journal is
+// discarded. The FileJobQueue is safe for concurrent use, but the file must
+// not be shared by multiple processes.
+type FileJobQueue struct {
+	mtx     sync.Mutex
+	path    string
+	file    *os.File
+	size    int64 // the size of the journal
+	records int   // the number of the journal records
+	queue   *priorityQueue
+	popped  map[poppedKey]*QueuedJob
+}
+
+// Verify FileJobQueue satisfies the PersistentJobQueue interface.
+var _ PersistentJobQueue = (*FileJobQueue)(nil)
+
+// NewFileJobQueue opens the FileJobQueue journaled to the file at the path,
+// creating the file if it does not exist. The journal is compacted when
+// it is opened.
+func NewFileJobQueue(path string) (*FileJobQueue, error) {
+	q := &FileJobQueue{
+		path:   path,
+		queue:  newPriorityQueue(),
+		popped: make(map[poppedKey]*QueuedJob),
+	}
+	if err := q.replay(); err != nil {
+		return nil, err
+	}
+	if err := q.requeuePopped(); err != nil {
+		return nil, err
+	}
+	if err := q.compact(); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Push adds the job to the FileJobQueue.
+func (q *FileJobQueue) Push(job *QueuedJob) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.queue.contains(job.Key) {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyExists, job.Key)
+	}
+	record, err := newPushRecord(job)
+	if err != nil {
+		return err
+	}
+	if err := q.append(record); err != nil {
+		return err
+	}
+
+	return q.queue.Push(job)
+}
+
+// Pop removes and returns the first job of the FileJobQueue. The job is
+// kept in the journal until it is acknowledged.
+func (q *FileJobQueue) Pop() (*QueuedJob, error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	head, err := q.queue.Head()
+	if err != nil {
+		return nil, err
+	}
+	if err := q.append(newFileRecord(fileOpPop, head)); err != nil {
+		return nil, err
+	}
+
+	job, err := q.queue.Pop()
+	if err != nil {
+		return nil, err
+	}
+	q.popped[poppedKey{job.Key, job.NextRunTime}] = job
+
+	return job, nil
+}
+
+// Head returns the first job of the FileJobQueue without removing it.
+func (q *FileJobQueue) Head() (*QueuedJob, error) {
+	return q.queue.Head()
+}
+
+// Remove removes and returns the job with the specified key.
+func (q *FileJobQueue) Remove(key JobKey) (*QueuedJob, error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if !q.queue.contains(key) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, key)
+	}
+	record := &fileRecord{Op: fileOpRemove, Name: key.Name, Group: key.Group}
+	if err := q.append(record); err != nil {
+		return nil, err
+	}
+
+	return q.queue.Remove(key)
+}
+
+// Len returns the number of the queued jobs, excluding the popped jobs.
+func (q *FileJobQueue) Len() int {
+	return q.queue.Len()
+}
+
+// Clear removes all of the queued jobs. The popped jobs are kept until
+// they are acknowledged.
+func (q *FileJobQueue) Clear() error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if err := q.append(&fileRecord{Op: fileOpClear}); err != nil {
+		return err
+	}
+
+	return q.queue.Clear()
+}
+
+// Ack acknowledges the completion of the fire of the popped job, removing
+// it from the journal.
+func (q *FileJobQueue) Ack(job *QueuedJob) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	key := poppedKey{job.Key, job.NextRunTime}
+	if _, ok := q.popped[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, job.Key)
+	}
+	if err := q.append(newFileRecord(fileOpAck, job)); err != nil {
+		return err
+	}
+	delete(q.popped, key)
+
+	return nil
+}
+
+// Jobs returns the queued jobs, ordered by their next run time.
+func (q *FileJobQueue) Jobs() ([]*QueuedJob, error) {
+	return q.queue.jobs(), nil
+}
+
+// Compact rewrites the journal, so that it holds a record per queued job
+// and two records per popped job.
+func (q *FileJobQueue) Compact() error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.file == nil {
+		return fs.ErrClosed
+	}
+
+	return q.compact()
+}
+
+// Close closes the journal of the FileJobQueue. The operations modifying
+// a closed FileJobQueue fail.
+func (q *FileJobQueue) Close() error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.file == nil {
+		return fs.ErrClosed
+	}
+	err := q.file.Close()
+	q.file = nil
+
+	return err
+}
+
+// replay applies the records of the journal.
+func (q *FileJobQueue) replay() error {
+	file, err := os.Open(q.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// a trailing record without the line break was torn
+			// by an interrupted write, and is discarded
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record fileRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("corrupt job queue journal %s, line %d: %w", q.path, line, err)
+		}
+		if err := q.apply(&record); err != nil {
+			return fmt.Errorf("corrupt job queue journal %s, line %d: %w", q.path, line, err)
+		}
+	}
+}
+
+// apply applies the journal record to the in-memory state.
+func (q *FileJobQueue) apply(record *fileRecord) error {
+	switch record.Op {
+	case fileOpPush:
+		job, err := record.queuedJob()
+		if err != nil {
+			return err
+		}
+		return q.queue.Push(job)
+	case fileOpPop:
+		job, err := q.queue.Remove(record.key())
+		if err != nil {
+			return err
+		}
+		q.popped[poppedKey{job.Key, job.NextRunTime}] = job
+	case fileOpAck:
+		delete(q.popped, poppedKey{record.key(), record.NextRunTime})
+	case fileOpRemove:
+		_, err := q.queue.Remove(record.key())
+		return err
+	case fileOpClear:
+		return q.queue.Clear()
+	default:
+		return fmt.Errorf("unknown operation %q", record.Op)
+	}
+
+	return nil
+}
+
+// requeuePopped requeues the popped jobs which were not acknowledged, the
+// earliest fire of each key taking precedence.
+func (q *FileJobQueue) requeuePopped() error {
+	for _, job := range q.poppedJobs() {
+		if queued, err := q.queue.Remove(job.Key); err == nil && queued.NextRunTime <= job.NextRunTime {
+			job = queued
+		}
+		if err := q.queue.Push(job); err != nil {
+			return err
+		}
+	}
+	q.popped = make(map[poppedKey]*QueuedJob)
+
+	return nil
+}
+
+// poppedJobs returns the popped jobs, ordered by their next run time.
+func (q *FileJobQueue) poppedJobs() []*QueuedJob {
+	jobs := make([]*QueuedJob, 0, len(q.popped))
+	for _, job := range q.popped {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].NextRunTime < jobs[j].NextRunTime
+	})
+
+	return jobs
+}
+
+// append appends the record to the journal and syncs it to the disk. A
+// partially written record is truncated. The journal is compacted once
+// the obsolete records prevail, retrying on the following appends if
+// the compaction fails.
+func (q *FileJobQueue) append(record *fileRecord) error {
+	if q.file == nil {
+		return fs.ErrClosed
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err = q.file.Write(data); err == nil {
+		err = q.file.Sync()
+	}
+	if err != nil {
+		_ = q.file.Truncate(q.size)
+		return err
+	}
+	q.size += int64(len(data))
+	q.records++
+
+	live := q.queue.Len() + 2*len(q.popped)
+	if q.records >= fileQueueCompactRecords && q.records > 2*live {
+		_ = q.compact()
+	}
+
+	return nil
+}
+
+// compact replaces the journal with a snapshot of the current state.
+func (q *FileJobQueue) compact() error {
+	tmp := q.path + ".tmp"
+	records, err := q.writeSnapshot(tmp)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if q.file != nil {
+		q.file.Close()
+		q.file = nil
+	}
+	err = os.Rename(tmp, q.path)
+	if err != nil {
+		_ = os.Remove(tmp)
+	} else {
+		syncDir(filepath.Dir(q.path))
+		q.records = records
+	}
+
+	// reopen the journal, even if the rename failed
+	file, openErr := os.OpenFile(q.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if openErr != nil {
+		return openErr
+	}
+	info, statErr := file.Stat()
+	if statErr != nil {
+		file.Close()
+		return statErr
+	}
+	q.file = file
+	q.size = info.Size()
+
+	return err
+}
+
+// writeSnapshot writes the records of the current state to the file at
+// the path, returning the number of the records.
+func (q *FileJobQueue) writeSnapshot(path string) (int, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	// the popped jobs go first, as a job with the same key can be queued
+	var records []*fileRecord
+	for _, job := range q.poppedJobs() {
+		record, err := newPushRecord(job)
+		if err != nil {
+			return 0, err
+		}
+		records = append(records, record, newFileRecord(fileOpPop, job))
+	}
+	for _, job := range q.queue.jobs() {
+		record, err := newPushRecord(job)
+		if err != nil {
+			return 0, err
+		}
+		records = append(records, record)
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return 0, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+
+	return len(records), file.Close()
+}
+
+// syncDir syncs the directory, so that a rename within it is durable. The
+// errors are ignored, as not all of the platforms support it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
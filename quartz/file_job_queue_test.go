@@ -0,0 +1,230 @@
+package quartz_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/reugn/go-quartz/quartz/queuetest"
+)
+
+func openFileJobQueue(t *testing.T, path string) *quartz.FileJobQueue {
+	t.Helper()
+	queue, err := quartz.NewFileJobQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { queue.Close() })
+	return queue
+}
+
+func fileQueuedJob(name string, nextRunTime int64) *quartz.QueuedJob {
+	return &quartz.QueuedJob{
+		Key:         quartz.NewJobKey(name),
+		Job:         quartz.NewShellJob("echo " + name),
+		Trigger:     quartz.NewSimpleTrigger(time.Minute),
+		NextRunTime: nextRunTime,
+	}
+}
+
+func TestFileJobQueue(t *testing.T) {
+	dir := t.TempDir()
+	var n int
+	queuetest.TestJobQueue(t, func() quartz.JobQueue {
+		n++
+		return openFileJobQueue(t, filepath.Join(dir, fmt.Sprintf("queue-%d.jsonl", n)))
+	})
+}
+
+func TestFileJobQueueRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	queue := openFileJobQueue(t, path)
+	for _, job := range []*quartz.QueuedJob{
+		fileQueuedJob("a", 10),
+		fileQueuedJob("b", 20),
+		fileQueuedJob("c", 30),
+	} {
+		if err := queue.Push(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the fire of a is interrupted after the job is rescheduled
+	popped, err := queue.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, popped.Key, quartz.NewJobKey("a"))
+	if err := queue.Push(fileQueuedJob("a", 40)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the fire of b is completed and acknowledged
+	popped, err = queue.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Ack(popped); err != nil {
+		t.Fatal(err)
+	}
+	assertNotEqual(t, queue.Ack(popped), nil)
+	assertEqual(t, queue.Close(), nil)
+	assertNotEqual(t, queue.Push(fileQueuedJob("d", 50)), nil)
+
+	// a torn record is discarded
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"op":"push","name":"d"`)
+	file.Close()
+
+	queue = openFileJobQueue(t, path)
+	jobs, err := queue.Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(jobs), 2)
+	assertEqual(t, jobs[0].Key, quartz.NewJobKey("a"))
+	assertEqual(t, jobs[0].NextRunTime, int64(10))
+	assertEqual(t, jobs[0].Job.Description(), "ShellJob: echo a")
+	assertEqual(t, jobs[1].Key, quartz.NewJobKey("c"))
+	assertEqual(t, jobs[1].Trigger.Description(), quartz.NewSimpleTrigger(time.Minute).Description())
+}
+
+func TestFileJobQueueBounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	queue := openFileJobQueue(t, path)
+	job := fileQueuedJob("a", 10)
+	job.StartTime, job.ExpireTime = 5, 50
+	if err := queue.Push(job); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, queue.Close(), nil)
+
+	// the bounds of the job are reloaded along with it
+	queue = openFileJobQueue(t, path)
+	popped, err := queue.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, popped.StartTime, int64(5))
+	assertEqual(t, popped.ExpireTime, int64(50))
+}
+
+func TestFileJobQueueCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	queue := openFileJobQueue(t, path)
+	if err := queue.Push(fileQueuedJob("static", 1<<40)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := queue.Push(fileQueuedJob("cycle", int64(i))); err != nil {
+			t.Fatal(err)
+		}
+		popped, err := queue.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := queue.Ack(popped); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines >= 1024 {
+		t.Fatalf("the journal was not compacted: %d records", lines)
+	}
+
+	if err := queue.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, bytes.Count(data, []byte("\n")), 1)
+	assertEqual(t, queue.Len(), 1)
+}
+
+func TestFileJobQueueScheduler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(start)
+	queue := openFileJobQueue(t, path)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+		Queue:             queue,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+
+	job := quartz.NewShellJob("true")
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Minute)
+	for {
+		scheduled, err := sched.GetScheduledJob(job.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scheduled.LastCompletedTime != 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := sched.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	queue.Close()
+
+	// the completed fire is acknowledged, the restarted scheduler
+	// reloads the job and skips the fires missed while it was down
+	queue = openFileJobQueue(t, path)
+	clock = quartz.NewMockClock(start.Add(10*time.Minute + 30*time.Second))
+	listener := &recordingListener{}
+	sched = quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		MisfirePolicy:     quartz.MisfireRescheduleNext,
+		OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+		Queue:             queue,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	sched.AddListener(listener)
+	assertEqual(t, len(sched.GetScheduledJobs()), 0)
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	for {
+		listener.mtx.Lock()
+		skipped := listener.skipped
+		listener.mtx.Unlock()
+		if len(skipped) > 0 {
+			assertEqual(t, skipped, []quartz.JobKey{quartz.NewJobKey(fmt.Sprint(job.Key()))})
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	scheduled, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.Job.Description(), job.Description())
+	assertEqual(t, scheduled.NextRunTime, start.Add(11*time.Minute+30*time.Second).UnixNano())
+}
@@ -0,0 +1,62 @@
+package quartz
+
+import (
+	"context"
+	"time"
+)
+
+// fire is a single execution of a scheduled Job, handed over from the
+// execution loop to the executing goroutine along with its dedicated
+// context.
+type fire struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	item   *item
+	job    *ScheduledJob
+
+	// misfire is set when the outdated fire is executed.
+	misfire bool
+
+	// catchUp is set when the fire catches up a missed one.
+	catchUp bool
+
+	// deadline, when set, is the next fire time of the Job, which
+	// bounds the execution.
+	deadline int64
+
+	// ack is set when the fire was popped from a PersistentJobQueue.
+	ack *queueAck
+
+	// pool and worker identify the worker executing the fire, if any.
+	pool   string
+	worker int
+
+	// started is the time the execution of the fire started.
+	started time.Time
+}
+
+// newFire returns a fire of the item, using the ScheduledJob snapshot
+// taken by the caller.
+func newFire(it *item, job *ScheduledJob) *fire {
+	return &fire{item: it, job: job}
+}
+
+// start derives the context of the fire from the jobs context, making
+// the ScheduledJob available to the Job via ScheduledJobFromContext.
+func (f *fire) start(jobCtx context.Context) {
+	ctx := context.WithValue(jobCtx, scheduledJobKey{}, *f.job)
+	f.ctx, f.cancel = context.WithCancel(ctx)
+}
+
+type scheduledJobKey struct{}
+
+// ScheduledJobFromContext returns the ScheduledJob being executed, if
+// the context was passed to the Job by the StdScheduler.
+func ScheduledJobFromContext(ctx context.Context) (*ScheduledJob, bool) {
+	job, ok := ctx.Value(scheduledJobKey{}).(ScheduledJob)
+	if !ok {
+		return nil, false
+	}
+
+	return &job, true
+}
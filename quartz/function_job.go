@@ -3,6 +3,7 @@ package quartz
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // Function represents an argument-less function which returns a generic type R and a possible error.
@@ -15,6 +16,8 @@ type FunctionJob[R any] struct {
 	Result    *R
 	Error     error
 	JobStatus JobStatus
+
+	explicitKey
 }
 
 // NewFunctionJob returns a new FunctionJob without an explicit description.
@@ -44,14 +47,21 @@ func (f *FunctionJob[R]) Description() string {
 	return f.desc
 }
 
-// Key returns the unique FunctionJob key.
+// Key returns the unique FunctionJob key, derived from its description and
+// function unless assigned using WithKey.
 func (f *FunctionJob[R]) Key() int {
-	return HashCode(fmt.Sprintf("%s:%p", f.desc, f.function))
+	return f.keyOr(func() int { return HashKey("FunctionJob", f.desc, fmt.Sprintf("%p", f.function)) })
+}
+
+// WithKey assigns the key of the FunctionJob. It returns the FunctionJob.
+func (f *FunctionJob[R]) WithKey(key int) *FunctionJob[R] {
+	f.assign(key)
+	return f
 }
 
 // Execute is called by a Scheduler when the Trigger associated with this job fires.
 // It invokes the held function, setting the results in Result and Error members.
-func (f *FunctionJob[R]) Execute(ctx context.Context) {
+func (f *FunctionJob[R]) Execute(ctx context.Context) error {
 	result, err := (*f.function)(ctx)
 	if err != nil {
 		f.JobStatus = FAILURE
@@ -62,4 +72,126 @@ func (f *FunctionJob[R]) Execute(ctx context.Context) {
 		f.Error = nil
 		f.Result = &result
 	}
+
+	return err
+}
+
+// FunctionResult holds the outcome of a run of a FunctionResultJob.
+type FunctionResult[R any] struct {
+	Value R
+	Err   error
+}
+
+// FunctionResultJob represents a Job that invokes the passed Function, keeping the
+// result of the most recent completed run, implements the quartz.Job interface. The
+// result is safe to read concurrently with the executions of the Job.
+type FunctionResultJob[R any] struct {
+	function *Function[R]
+	desc     string
+	results  chan<- FunctionResult[R]
+
+	mtx       sync.RWMutex
+	result    FunctionResult[R]
+	done      chan struct{}
+	completed bool
+
+	explicitKey
+}
+
+// NewFunctionJobWithResult returns a new FunctionResultJob.
+func NewFunctionJobWithResult[R any](function Function[R]) *FunctionResultJob[R] {
+	return &FunctionResultJob[R]{
+		function: &function,
+		desc:     fmt.Sprintf("FunctionResultJob:%p", &function),
+		done:     make(chan struct{}),
+	}
+}
+
+// NewFunctionJobWithResultChan returns a new FunctionResultJob delivering the
+// result of each run on the results channel. The run blocks until the result
+// is received, or drops it once the execution context is done.
+func NewFunctionJobWithResultChan[R any](function Function[R],
+	results chan<- FunctionResult[R]) *FunctionResultJob[R] {
+	job := NewFunctionJobWithResult(function)
+	job.results = results
+	return job
+}
+
+// Description returns the description of the FunctionResultJob.
+func (f *FunctionResultJob[R]) Description() string {
+	return f.desc
+}
+
+// Key returns the unique FunctionResultJob key, derived from its description
+// and function unless assigned using WithKey.
+func (f *FunctionResultJob[R]) Key() int {
+	return f.keyOr(func() int { return HashKey("FunctionResultJob", f.desc, fmt.Sprintf("%p", f.function)) })
+}
+
+// WithKey assigns the key of the FunctionResultJob. It returns the
+// FunctionResultJob.
+func (f *FunctionResultJob[R]) WithKey(key int) *FunctionResultJob[R] {
+	f.assign(key)
+	return f
+}
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+// It invokes the held function, keeping its result.
+func (f *FunctionResultJob[R]) Execute(ctx context.Context) error {
+	value, err := (*f.function)(ctx)
+	result := FunctionResult[R]{Value: value, Err: err}
+
+	f.mtx.Lock()
+	f.result = result
+	if !f.completed {
+		f.completed = true
+		close(f.done)
+	}
+	f.mtx.Unlock()
+
+	if f.results != nil {
+		select {
+		case f.results <- result:
+		case <-ctx.Done():
+		}
+	}
+
+	return err
+}
+
+// Result returns the result of the most recent completed run of the
+// FunctionResultJob, the zero value if no run has completed yet.
+func (f *FunctionResultJob[R]) Result() (R, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return f.result.Value, f.result.Err
+}
+
+// Done returns a channel which is closed once the first run of the
+// FunctionResultJob has completed.
+func (f *FunctionResultJob[R]) Done() <-chan struct{} {
+	return f.done
+}
+
+// funcJob represents a Job with an explicit key that invokes a function
+// returning an error, used by the StdScheduler to schedule closures.
+type funcJob struct {
+	function func(context.Context) error
+	key      int
+}
+
+// Description returns the description of the funcJob.
+func (f *funcJob) Description() string {
+	return fmt.Sprintf("FuncJob:%d", f.key)
+}
+
+// Key returns the key the funcJob was created with.
+func (f *funcJob) Key() int {
+	return f.key
+}
+
+// Execute invokes the held function, returning its error.
+func (f *funcJob) Execute(ctx context.Context) error {
+	return f.function(ctx)
 }
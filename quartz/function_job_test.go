@@ -3,6 +3,7 @@ package quartz_test
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -80,3 +81,81 @@ func TestFunctionJobRespectsContext(t *testing.T) {
 		t.Fatal("errored jobs should not return values")
 	}
 }
+
+func TestFunctionResultJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var n int32
+	job := quartz.NewFunctionJobWithResult(func(_ context.Context) (int32, error) {
+		return atomic.AddInt32(&n, 1), nil
+	})
+	value, err := job.Result()
+	assertEqual(t, value, int32(0))
+	assertEqual(t, err, nil)
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	<-job.Done()
+	// the result is read concurrently with the executions
+	var last int32
+	for i := 0; i < 100; i++ {
+		value, err := job.Result()
+		assertEqual(t, err, nil)
+		if value < last || value < 1 {
+			t.Fatal("unexpected result", value, last)
+		}
+		last = value
+		time.Sleep(100 * time.Microsecond)
+	}
+
+	errFunction := errors.New("function error")
+	job = quartz.NewFunctionJobWithResult(func(_ context.Context) (int32, error) {
+		return 0, errFunction
+	})
+	assertEqual(t, job.Execute(ctx), errFunction)
+	_, err = job.Result()
+	assertEqual(t, err, errFunction)
+}
+
+func TestFunctionResultJobChan(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := make(chan quartz.FunctionResult[int])
+	var n int
+	job := quartz.NewFunctionJobWithResultChan(func(_ context.Context) (int, error) {
+		n++
+		return n, nil
+	}, results)
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Millisecond),
+		quartz.WithConcurrencyPolicy(quartz.ConcurrencyQueue)); err != nil {
+		t.Fatal(err)
+	}
+
+	// every run is delivered
+	for i := 1; i <= 5; i++ {
+		result := <-results
+		assertEqual(t, result, quartz.FunctionResult[int]{Value: i})
+	}
+
+	// the result is dropped once the context is done
+	canceled, cancelRun := context.WithCancel(context.Background())
+	cancelRun()
+	assertEqual(t, quartz.NewFunctionJobWithResultChan(func(_ context.Context) (int, error) {
+		return 1, nil
+	}, results).Execute(canceled), nil)
+}
@@ -0,0 +1,133 @@
+package quartz
+
+import "time"
+
+// ExecutionOutcome is the outcome of a fire of a Job.
+type ExecutionOutcome int
+
+const (
+	// OutcomeOK is the outcome of an execution which returned no error.
+	OutcomeOK ExecutionOutcome = iota
+
+	// OutcomeError is the outcome of an execution which returned an
+	// error.
+	OutcomeError
+
+	// OutcomePanic is the outcome of an execution which panicked.
+	OutcomePanic
+
+	// OutcomeSkipped is the outcome of a fire which was not executed,
+	// e.g. because it was outdated.
+	OutcomeSkipped
+
+	// OutcomeTimedOut is the outcome of an execution which exceeded its
+	// timeout.
+	OutcomeTimedOut
+)
+
+// String returns the name of the ExecutionOutcome.
+func (o ExecutionOutcome) String() string {
+	switch o {
+	case OutcomeOK:
+		return "ok"
+	case OutcomeError:
+		return "error"
+	case OutcomePanic:
+		return "panic"
+	case OutcomeSkipped:
+		return "skipped"
+	case OutcomeTimedOut:
+		return "timed-out"
+	default:
+		return "unknown"
+	}
+}
+
+// ExecutionRecord is an entry of the execution history of a Job.
+type ExecutionRecord struct {
+	// ScheduledTime is the time the fire was scheduled at.
+	ScheduledTime time.Time
+
+	// StartTime is the time the execution started, zero if the fire
+	// was skipped.
+	StartTime time.Time
+
+	// Duration is the duration of the execution.
+	Duration time.Duration
+
+	// Outcome is the outcome of the fire.
+	Outcome ExecutionOutcome
+
+	// Error is the error returned by the execution, or the reason the
+	// fire was skipped.
+	Error string
+}
+
+// executionHistory is a ring buffer of the last ExecutionRecords of a Job.
+type executionHistory struct {
+	records []ExecutionRecord
+	next    int
+	full    bool
+}
+
+// newExecutionHistory returns a new executionHistory holding up to size
+// records.
+func newExecutionHistory(size int) *executionHistory {
+	return &executionHistory{records: make([]ExecutionRecord, size)}
+}
+
+// add appends the record, replacing the oldest one once the history is
+// full.
+func (h *executionHistory) add(record ExecutionRecord) {
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns a copy of the records, the oldest first.
+func (h *executionHistory) snapshot() []ExecutionRecord {
+	if !h.full {
+		return append([]ExecutionRecord(nil), h.records[:h.next]...)
+	}
+
+	records := make([]ExecutionRecord, 0, len(h.records))
+	records = append(records, h.records[h.next:]...)
+	return append(records, h.records[:h.next]...)
+}
+
+// GetJobHistory returns the execution history of the Job with the key,
+// the oldest record first. It returns nil if the Job is not found, or
+// HistorySize is not set.
+func (sched *StdScheduler) GetJobHistory(key int) []ExecutionRecord {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if item := sched.findItem(intJobKey(key)); item != nil && item.history != nil {
+		return item.history.snapshot()
+	}
+
+	return nil
+}
+
+// recordExecution adds the record to the execution history of the item,
+// if it is kept.
+func (sched *StdScheduler) recordExecution(it *item, record ExecutionRecord) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if it.history != nil {
+		it.history.add(record)
+	}
+}
+
+// recordSkipped adds the record of a fire of the ScheduledJob skipped for
+// the reason to the execution history of the item.
+func (sched *StdScheduler) recordSkipped(it *item, job *ScheduledJob, reason string) {
+	sched.recordExecution(it, ExecutionRecord{
+		ScheduledTime: time.Unix(0, job.NextRunTime),
+		Outcome:       OutcomeSkipped,
+		Error:         reason,
+	})
+}
@@ -0,0 +1,118 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerJobHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		HistorySize: 3,
+		Logger:      quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var runs int32
+	job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+		switch atomic.AddInt32(&runs, 1) {
+		case 1:
+			return true, nil
+		case 2:
+			return false, errors.New("failed")
+		case 3:
+			panic("boom")
+		default:
+			<-ctx.Done()
+			return false, ctx.Err()
+		}
+	})
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithTimeout(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(sched.GetJobHistory(job.Key())), 0)
+	assertEqual(t, sched.GetJobHistory(-1), []quartz.ExecutionRecord(nil))
+
+	waitHistory := func(n int) []quartz.ExecutionRecord {
+		for {
+			history := sched.GetJobHistory(job.Key())
+			if len(history) == n {
+				return history
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for i := 1; i <= 4; i++ {
+		if err := sched.TriggerJob(ctx, job.Key()); err != nil {
+			t.Fatal(err)
+		}
+		for atomic.LoadInt32(&runs) < int32(i) {
+			time.Sleep(time.Millisecond)
+		}
+		if i < 4 {
+			waitHistory(i)
+		}
+	}
+
+	// the oldest record is replaced once the history is full
+	time.Sleep(50 * time.Millisecond)
+	history := waitHistory(3)
+	assertEqual(t, history[0].Outcome, quartz.OutcomeError)
+	assertEqual(t, history[0].Error, "failed")
+	assertEqual(t, history[1].Outcome, quartz.OutcomePanic)
+	assertEqual(t, history[1].Error, "job panicked: boom")
+	assertEqual(t, history[2].Outcome, quartz.OutcomeTimedOut)
+	assertEqual(t, history[2].Duration >= 10*time.Millisecond, true)
+	assertEqual(t, history[2].StartTime.IsZero(), false)
+
+	// the history survives the reschedule of the job
+	if err := sched.RescheduleJob(ctx, job.Key(), quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&runs, 0)
+	if err := sched.TriggerJob(ctx, job.Key()); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		history = sched.GetJobHistory(job.Key())
+		if history[2].Outcome == quartz.OutcomeOK {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assertEqual(t, history[1].Outcome, quartz.OutcomeTimedOut)
+
+	// the skipped fires are recorded
+	release := make(chan struct{})
+	blocking := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		<-release
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, blocking, quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithConcurrencyPolicy(quartz.ConcurrencySkip)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sched.TriggerJob(ctx, blocking.Key()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for len(sched.GetJobHistory(blocking.Key())) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	history = sched.GetJobHistory(blocking.Key())
+	assertEqual(t, history[0].Outcome, quartz.OutcomeSkipped)
+	assertEqual(t, history[0].Error, "concurrent execution")
+	assertEqual(t, history[0].StartTime.IsZero(), true)
+	assertEqual(t, quartz.OutcomeTimedOut.String(), "timed-out")
+}
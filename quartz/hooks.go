@@ -0,0 +1,106 @@
+package quartz
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	// OnStartGroup is the group of the keys of the OnStart jobs.
+	OnStartGroup = "quartz.on_start"
+
+	// OnStopGroup is the group of the keys of the OnStop jobs.
+	OnStopGroup = "quartz.on_stop"
+)
+
+// defaultOnStopTimeout bounds the OnStop jobs when the StdScheduler is
+// stopped, used when no timeout is configured.
+const defaultOnStopTimeout = 10 * time.Second
+
+// hookFire returns the fire of the OnStart or OnStop Job. The item of
+// the fire is not indexed, so that the Job is not listed as scheduled.
+func (sched *StdScheduler) hookFire(job Job, group string) *fire {
+	key := NewJobKeyWithGroup(strconv.Itoa(job.Key()), group)
+	it := sched.newItem(key, job, nil, scheduleOptions{})
+	it.priority = sched.nowNano()
+
+	return newFire(it, &ScheduledJob{
+		Job:                job,
+		Key:                key,
+		TriggerDescription: group,
+		NextRunTime:        it.priority,
+		Timeout:            it.opts.timeout,
+		MisfirePolicy:      it.opts.misfire,
+		CatchUpPolicy:      it.opts.catchUp,
+		RemainingRuns:      1,
+	})
+}
+
+// startHooks executes the OnStart jobs of the run in order, in the jobs
+// context. They are not dispatched by the execution loop, so that they
+// are executed even if the run is stopped at once. The caller must hold
+// the lock.
+func (sched *StdScheduler) startHooks(jobCtx context.Context) {
+	started := make(chan struct{})
+	sched.started = started
+	if len(sched.opts.OnStart) == 0 {
+		close(started)
+		return
+	}
+
+	sched.wg.Add(1)
+	go func() {
+		defer sched.wg.Done()
+		defer close(started)
+		for _, job := range sched.opts.OnStart {
+			f := sched.hookFire(job, OnStartGroup)
+			f.start(jobCtx)
+			sched.run(f)
+		}
+	}()
+}
+
+// valuesContext carries the values of a context, while its deadline and
+// cancellation are the ones of another context.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+// valuesOf returns a context carrying the values of the values context,
+// bounded by the ctx. The ctx is returned as is if there are no values.
+func valuesOf(values, ctx context.Context) context.Context {
+	if values == nil {
+		return ctx
+	}
+	return valuesContext{Context: ctx, values: values}
+}
+
+// Value returns the value of the values context for the key.
+func (c valuesContext) Value(key any) any {
+	return c.values.Value(key)
+}
+
+// stopHooks executes the OnStop jobs of the stopped run in order, once
+// its OnStart jobs have returned, in the context bounding them, which is
+// canceled once they have returned. The caller must hold the lock.
+func (sched *StdScheduler) stopHooks(ctx context.Context, cancel context.CancelFunc) {
+	if len(sched.opts.OnStop) == 0 {
+		cancel()
+		return
+	}
+
+	started := sched.started
+	sched.wg.Add(1)
+	go func() {
+		defer sched.wg.Done()
+		defer cancel()
+		<-started
+		for _, job := range sched.opts.OnStop {
+			f := sched.hookFire(job, OnStopGroup)
+			f.start(ctx)
+			sched.run(f)
+		}
+	}()
+}
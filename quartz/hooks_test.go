@@ -0,0 +1,153 @@
+package quartz_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerHooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	started := make(chan struct{}, 2)
+	onStart := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		started <- struct{}{}
+		return true, nil
+	})
+	var stopped []error
+	onStop := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+		// the context of the OnStop jobs is live
+		time.Sleep(10 * time.Millisecond)
+		stopped = append(stopped, ctx.Err())
+		return true, nil
+	})
+	panicking := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		panic("boom")
+	})
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		WorkerLimit: 1,
+		OnStart:     []quartz.Job{onStart},
+		OnStop:      []quartz.Job{panicking, onStop},
+		Logger:      quartz.NewNoopLogger(),
+	})
+	listener := &recordingListener{}
+	sched.AddListener(listener)
+	events := sched.Events()
+
+	for i := 0; i < 2; i++ {
+		if err := sched.StartErr(ctx); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-started:
+		case <-ctx.Done():
+			t.Fatal("OnStart job was not executed")
+		}
+		assertEqual(t, len(sched.GetJobKeys()), 0)
+		assertEqual(t, len(sched.GetJobKeysWithGroup(quartz.OnStartGroup)), 0)
+
+		sched.Stop()
+		sched.Wait(ctx)
+		assertEqual(t, stopped, make([]error, i+1))
+
+		// the events of the first run are published until the
+		// channel is closed
+		for sched.Events() == events {
+			select {
+			case <-time.After(5 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("the events channel was not closed")
+			}
+		}
+	}
+
+	startKey := quartz.NewJobKeyWithGroup(strconv.Itoa(onStart.Key()), quartz.OnStartGroup)
+	stopKey := quartz.NewJobKeyWithGroup(strconv.Itoa(onStop.Key()), quartz.OnStopGroup)
+	panicKey := quartz.NewJobKeyWithGroup(strconv.Itoa(panicking.Key()), quartz.OnStopGroup)
+	assertEqual(t, listener.before, []quartz.JobKey{startKey, panicKey, stopKey, startKey, panicKey, stopKey})
+
+	// the hooks are published along with the executions of the jobs
+	var completed, failed []quartz.JobKey
+	for event := range events {
+		switch event.Type {
+		case quartz.EventCompleted:
+			completed = append(completed, event.Key)
+		case quartz.EventFailed:
+			failed = append(failed, event.Key)
+		}
+	}
+	assertEqual(t, completed, []quartz.JobKey{startKey, stopKey})
+	assertEqual(t, failed, []quartz.JobKey{panicKey})
+}
+
+func TestSchedulerStartStopHooks(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 1},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var mtx sync.Mutex
+		var executed []string
+		hook := func(name string) quartz.Job {
+			return quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				executed = append(executed, name)
+				return true, nil
+			})
+		}
+		opts.OnStart = []quartz.Job{hook("start")}
+		opts.OnStop = []quartz.Job{hook("stop")}
+		opts.Logger = quartz.NewNoopLogger()
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+
+		// the OnStart jobs are executed before the OnStop jobs, even
+		// if the scheduler is stopped at once
+		for i := 0; i < 100; i++ {
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+			sched.Stop()
+			sched.Wait(ctx)
+
+			mtx.Lock()
+			assertEqual(t, executed, []string{"start", "stop"})
+			executed = nil
+			mtx.Unlock()
+		}
+	}
+}
+
+func TestSchedulerShutdownHooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the OnStop jobs are bounded by the context of Shutdown
+	var stopErr error
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OnStop: []quartz.Job{quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			<-ctx.Done()
+			stopErr = ctx.Err()
+			return false, ctx.Err()
+		})},
+		Logger: quartz.NewNoopLogger(),
+	})
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer shutdownCancel()
+	assertEqual(t, sched.Shutdown(shutdownCtx), context.DeadlineExceeded)
+	assertEqual(t, sched.WaitErr(ctx), nil)
+	assertEqual(t, stopErr, context.DeadlineExceeded)
+}
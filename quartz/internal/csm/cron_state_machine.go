@@ -20,10 +20,14 @@ type CronStateMachine struct {
 	day    *DayNode
 	month  csmNode
 	year   csmNode
+
+	// exhausted is set when the year overflows, i.e. there is no
+	// following instant that fits the expression.
+	exhausted bool
 }
 
 func NewCronStateMachine(second, minute, hour csmNode, day *DayNode, month, year csmNode) *CronStateMachine {
-	return &CronStateMachine{second, minute, hour, day, month, year}
+	return &CronStateMachine{second, minute, hour, day, month, year, false}
 }
 
 func (csm *CronStateMachine) Value() time.Time {
@@ -42,7 +46,13 @@ func (csm *CronStateMachine) ValueWithLocation(loc *time.Location) time.Time {
 	)
 }
 
-func (csm *CronStateMachine) NextTriggerTime(loc *time.Location) time.Time {
+// NextTriggerTime returns the first following instant that fits the
+// expression. It returns false if there is no such instant.
+func (csm *CronStateMachine) NextTriggerTime(loc *time.Location) (time.Time, bool) {
 	csm.findForward()
-	return csm.ValueWithLocation(loc)
+	// Advancing the day can result in a date that does not exist
+	if !csm.exhausted && !csm.isValidDate() {
+		csm.nextDate()
+	}
+	return csm.ValueWithLocation(loc), !csm.exhausted
 }
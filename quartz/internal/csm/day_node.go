@@ -4,19 +4,29 @@ import "time"
 
 var _ csmNode = (*DayNode)(nil)
 
+// DaysFunc returns the sorted days of the month matching an expression.
+type DaysFunc func(year int, month time.Month) []int
+
 type DayNode struct {
 	c             CommonNode
 	weekdayValues []int
+	days          DaysFunc
 	month         *csmNode
 	year          *csmNode
 }
 
 func NewMonthdayNode(value, min, max int, dayOfMonthValues []int, month, year csmNode) *DayNode {
-	return &DayNode{CommonNode{value, min, max, dayOfMonthValues}, make([]int, 0), &month, &year}
+	return &DayNode{CommonNode{value, min, max, dayOfMonthValues}, make([]int, 0), nil, &month, &year}
 }
 
 func NewWeekdayNode(value, min, max int, dayOfWeekValues []int, month, year csmNode) *DayNode {
-	return &DayNode{CommonNode{value, min, max, make([]int, 0)}, dayOfWeekValues, &month, &year}
+	return &DayNode{CommonNode{value, min, max, make([]int, 0)}, dayOfWeekValues, nil, &month, &year}
+}
+
+// NewSpecialDayNode returns a DayNode whose valid values depend on the
+// month and the year, as resolved by the days function.
+func NewSpecialDayNode(value, min, max int, days DaysFunc, month, year csmNode) *DayNode {
+	return &DayNode{CommonNode{value, min, max, make([]int, 0)}, make([]int, 0), days, &month, &year}
 }
 
 func (n *DayNode) Value() int {
@@ -29,12 +39,28 @@ func (n *DayNode) Reset() {
 }
 
 func (n *DayNode) Next() (overflowed bool) {
+	if n.isSpecial() {
+		return n.nextSpecial()
+	}
 	if n.isWeekday() {
 		return n.nextWeekday()
 	}
 	return n.nextDay()
 }
 
+func (n *DayNode) nextSpecial() (overflowed bool) {
+	for _, value := range n.specialDays() {
+		if value > n.c.value {
+			n.c.value = value
+			return false
+		}
+	}
+
+	// The valid days of the next month are resolved on reset
+	n.c.value = n.c.min
+	return true
+}
+
 func (n *DayNode) nextWeekday() (overflowed bool) {
 	weekday := n.getWeekday()
 
@@ -67,6 +93,9 @@ func (n *DayNode) findForward() result {
 
 func (n *DayNode) isValid() bool {
 	withinLimits := n.isValidDay()
+	if n.isSpecial() {
+		withinLimits = withinLimits && contained(n.c.value, n.specialDays())
+	}
 	if n.isWeekday() {
 		withinLimits = withinLimits && n.isValidWeekday()
 	}
@@ -85,6 +114,14 @@ func (n *DayNode) isWeekday() bool {
 	return len(n.weekdayValues) != 0
 }
 
+func (n *DayNode) isSpecial() bool {
+	return n.days != nil
+}
+
+func (n *DayNode) specialDays() []int {
+	return n.days((*n.year).Value(), time.Month((*n.month).Value()))
+}
+
 func (n *DayNode) getWeekday() int {
 	date := time.Date((*n.year).Value(), time.Month((*n.month).Value()), n.c.value, 0, 0, 0, 0, time.UTC)
 	return int(date.Weekday())
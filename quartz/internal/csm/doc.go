@@ -20,4 +20,6 @@
 // NOTE: Some precautions must be taken as the "day" value does not have a constant radix. It depends
 // on the month and the year. January always has 30 days, while February 2024 has 29. This is taken into account
 // by the DayNode struct (day_node.go) and CronStateMachine.next() (fn_next.go).
+// The days matching the L, W and # special characters also depend on the month and the year,
+// and are resolved by the DaysFunc of the DayNode.
 package csm
@@ -33,6 +33,8 @@ func (csm *CronStateMachine) resetFrom(node NodeID) {
 func (csm *CronStateMachine) overflowFrom(node NodeID) {
 	chosenNode := csm.selectNode(node)
 	if chosenNode == nil {
+		// The year overflowed
+		csm.exhausted = true
 		return
 	}
 
@@ -11,6 +11,10 @@ func (csm *CronStateMachine) next() {
 		return
 	}
 
+	csm.nextDate()
+}
+
+func (csm *CronStateMachine) nextDate() {
 	// Dates (dd-mm-yy) can be invalid in the case of leap years!
 	// If an invalid date is detected, re-run the loop
 	for next := true; next; next = !csm.isValidDate() {
@@ -29,9 +33,13 @@ func (csm *CronStateMachine) next() {
 			}
 			continue
 		}
+
+		// No valid date is left
+		csm.exhausted = true
+		return
 	}
 }
 
 func (csm *CronStateMachine) isValidDate() bool {
-	return csm.day.Value() <= csm.day.max()
+	return csm.day.isValid()
 }
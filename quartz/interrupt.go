@@ -0,0 +1,137 @@
+package quartz
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrJobNotInterruptable is returned by InterruptJob when a running Job
+// does not implement the InterruptableJob interface.
+var ErrJobNotInterruptable = errors.New("the Job is not interruptable")
+
+// ErrJobInterrupted is returned by the executions of the built-in jobs
+// which stopped early because they were interrupted.
+var ErrJobInterrupted = errors.New("the Job was interrupted")
+
+// InterruptableJob is implemented by the jobs which can be asked to stop
+// at their next safe point, e.g. after a checkpoint, as a gentler
+// alternative to canceling the context of their executions.
+type InterruptableJob interface {
+	Job
+
+	// Interrupt asks the running executions of the Job to stop, for
+	// the given reason. It is called from another goroutine than
+	// Execute, and must not block until the executions return.
+	Interrupt(reason string)
+}
+
+// InterruptJob interrupts the running executions of the Job with the
+// specified key, without affecting its schedule. It returns
+// ErrJobNotFound if the Job is not being executed, or
+// ErrJobNotInterruptable if the Job does not implement the
+// InterruptableJob interface.
+func (sched *StdScheduler) InterruptJob(key int, reason string) error {
+	sched.mtx.Lock()
+	fires, ok := sched.running[intJobKey(key)]
+	if !ok {
+		sched.mtx.Unlock()
+		return ErrJobNotFound
+	}
+	// the fires of the same item share the Job
+	jobs := make(map[*item]InterruptableJob, len(fires))
+	for f := range fires {
+		job, ok := f.job.Job.(InterruptableJob)
+		if !ok {
+			sched.mtx.Unlock()
+			return fmt.Errorf("%w: %s", ErrJobNotInterruptable, f.job.Job.Description())
+		}
+		jobs[f.item] = job
+	}
+	sched.mtx.Unlock()
+
+	for _, job := range jobs {
+		sched.opts.Logger.Info("Interrupting the Job",
+			"key", intJobKey(key),
+			"description", job.Description(),
+			"reason", reason,
+		)
+		job.Interrupt(reason)
+	}
+
+	return nil
+}
+
+// executions tracks the running executions of a built-in
+// InterruptableJob, so that they can be interrupted.
+type executions struct {
+	mtx     sync.Mutex
+	running map[*execution]struct{}
+}
+
+// execution is a running execution of a built-in InterruptableJob.
+type execution struct {
+	// interrupt, when set, stops the execution.
+	interrupt   func()
+	interrupted bool
+	reason      string
+}
+
+// start tracks a new execution, until the returned function is called.
+func (e *executions) start() (*execution, func()) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.running == nil {
+		e.running = make(map[*execution]struct{})
+	}
+	running := &execution{}
+	e.running[running] = struct{}{}
+
+	return running, func() {
+		e.mtx.Lock()
+		defer e.mtx.Unlock()
+
+		delete(e.running, running)
+	}
+}
+
+// onInterrupt sets the function stopping the execution, and reports
+// whether the execution was already interrupted.
+func (e *executions) onInterrupt(running *execution, interrupt func()) bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	running.interrupt = interrupt
+	return running.interrupted
+}
+
+// interruptAll interrupts the running executions.
+func (e *executions) interruptAll(reason string) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	for running := range e.running {
+		running.interrupted = true
+		running.reason = reason
+		if running.interrupt != nil {
+			running.interrupt()
+		}
+	}
+}
+
+// err returns ErrJobInterrupted along with its reason and the error of
+// the execution, if it was interrupted.
+func (e *executions) err(running *execution, err error) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	switch {
+	case !running.interrupted:
+		return err
+	case err == nil:
+		return fmt.Errorf("%w: %s", ErrJobInterrupted, running.reason)
+	default:
+		return fmt.Errorf("%w: %s: %v", ErrJobInterrupted, running.reason, err)
+	}
+}
@@ -0,0 +1,78 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerInterruptJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	shellJob := quartz.NewShellJob("sleep 5")
+	if err := sched.ScheduleJob(ctx, shellJob, quartz.NewRunOnceTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	funcKey, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		<-release
+		return nil
+	}, quartz.NewRunOnceTrigger(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sched.InterruptJob(shellJob.Key(), "maintenance"), quartz.ErrJobNotFound)
+
+	waitForRunning := func(key int) {
+		t.Helper()
+		for {
+			job, err := sched.GetScheduledJob(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.Running > 0 {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatal("the Job is not running")
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}
+
+	// the running command is terminated
+	errs := make(chan error, 1)
+	go func() { errs <- sched.WaitForJob(ctx, shellJob.Key()) }()
+	if err := sched.TriggerJob(ctx, shellJob.Key()); err != nil {
+		t.Fatal(err)
+	}
+	waitForRunning(shellJob.Key())
+	time.Sleep(50 * time.Millisecond)
+	if err := sched.InterruptJob(shellJob.Key(), "maintenance"); err != nil {
+		t.Fatal(err)
+	}
+	err = <-errs
+	assertEqual(t, errors.Is(err, quartz.ErrJobInterrupted), true)
+
+	// the jobs which do not implement InterruptableJob are reported
+	if err := sched.TriggerJob(ctx, funcKey); err != nil {
+		t.Fatal(err)
+	}
+	waitForRunning(funcKey)
+	err = sched.InterruptJob(funcKey, "maintenance")
+	assertEqual(t, errors.Is(err, quartz.ErrJobNotInterruptable), true)
+	close(release)
+}
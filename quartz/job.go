@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
 	"sync/atomic"
+	"time"
 )
 
 // Job represents an interface to be implemented by structs which represent a 'job'
 // to be performed.
 type Job interface {
 	// Execute is called by a Scheduler when the Trigger associated with this job fires.
-	Execute(context.Context)
+	// The returned error is reported by the Scheduler as the result of the execution.
+	Execute(context.Context) error
 
 	// Description returns the description of the Job.
 	Description() string
@@ -43,38 +49,217 @@ type ShellJob struct {
 	Cmd       string
 	Result    string
 	JobStatus JobStatus
+
+	// Args, when set, holds the program and its arguments, which are
+	// executed directly instead of passing Cmd to sh -c, so that they
+	// are not interpreted by the shell.
+	Args []string
+
+	// Env holds the environment variables, in the form key=value,
+	// added to the environment of the command.
+	Env []string
+
+	// ReplaceEnv makes Env replace the environment of the command,
+	// instead of being added to the environment of the process.
+	ReplaceEnv bool
+
+	// Dir is the working directory of the command. When empty, the
+	// command runs in the working directory of the process.
+	Dir string
+
+	// Timeout, when greater than 0, bounds each run of the command.
+	// The process group of the command is killed once the timeout
+	// expires or the context passed to Execute is done.
+	Timeout time.Duration
+
+	// OutputLimit caps the number of the captured bytes of each of
+	// the output streams, the rest of the output is discarded. When
+	// 0, a limit of 64 KiB is used. A negative limit disables the cap.
+	OutputLimit int
+
+	// Stdout and Stderr hold the captured output of the last run.
+	Stdout string
+	Stderr string
+
+	// ExitCode is the exit code of the last run, or -1 if the command
+	// did not exit, e.g. it failed to start or was killed.
+	ExitCode int
+
+	executions executions
+	explicitKey
 }
 
+// Verify ShellJob satisfies the InterruptableJob interface.
+var _ InterruptableJob = (*ShellJob)(nil)
+
+// defaultOutputLimit is the number of the captured bytes of each of the
+// output streams of a ShellJob, used when no limit is configured.
+const defaultOutputLimit = 64 << 10
+
 // NewShellJob returns a new ShellJob.
 func NewShellJob(cmd string) *ShellJob {
 	return &ShellJob{
 		Cmd:       cmd,
 		Result:    "",
 		JobStatus: NA,
+		ExitCode:  -1,
 	}
 }
 
+// NewShellJobWithArgs returns a new ShellJob executing the program with
+// the arguments, bypassing the shell.
+func NewShellJobWithArgs(name string, args ...string) *ShellJob {
+	job := NewShellJob("")
+	job.Args = append([]string{name}, args...)
+	return job
+}
+
 // Description returns the description of the ShellJob.
 func (sh *ShellJob) Description() string {
+	if len(sh.Args) > 0 {
+		return fmt.Sprintf("ShellJob: %s", strings.Join(sh.Args, " "))
+	}
+
 	return fmt.Sprintf("ShellJob: %s", sh.Cmd)
 }
 
-// Key returns the unique ShellJob key.
+// Key returns the unique ShellJob key, the hash of its command, arguments,
+// environment and working directory unless assigned using WithKey.
 func (sh *ShellJob) Key() int {
-	return HashCode(sh.Description())
+	return sh.keyOr(func() int {
+		parts := []string{"ShellJob", sh.Cmd, sh.Dir, strconv.FormatBool(sh.ReplaceEnv),
+			strconv.Itoa(len(sh.Args))}
+		parts = append(parts, sh.Args...)
+		return HashKey(append(parts, sh.Env...)...)
+	})
+}
+
+// WithKey assigns the key of the ShellJob, so that the jobs running the
+// same command can be scheduled independently. It returns the ShellJob.
+func (sh *ShellJob) WithKey(key int) *ShellJob {
+	sh.assign(key)
+	return sh
 }
 
 // Execute is called by a Scheduler when the Trigger associated with this job fires.
-func (sh *ShellJob) Execute(ctx context.Context) {
-	out, err := exec.CommandContext(ctx, "sh", "-c", sh.Cmd).Output()
+// The output and the exit code of the command are captured, and the Result holds
+// its standard output, or the error if the command failed.
+func (sh *ShellJob) Execute(ctx context.Context) error {
+	if sh.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sh.Timeout)
+		defer cancel()
+	}
+
+	limit := sh.OutputLimit
+	if limit == 0 {
+		limit = defaultOutputLimit
+	}
+	stdout, stderr := &cappedBuffer{limit: limit}, &cappedBuffer{limit: limit}
+	cmd := sh.command(ctx)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	running, done := sh.executions.start()
+	defer done()
+	err := sh.executions.err(running, sh.run(ctx, cmd, running))
+	sh.Stdout, sh.Stderr = stdout.String(), stderr.String()
+	sh.ExitCode = -1
+	if cmd.ProcessState != nil {
+		sh.ExitCode = cmd.ProcessState.ExitCode()
+	}
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = fmt.Errorf("%w: %v", ctxErr, err)
+		}
 		sh.JobStatus = FAILURE
 		sh.Result = err.Error()
-		return
+		return err
 	}
 
 	sh.JobStatus = OK
-	sh.Result = string(out)
+	sh.Result = sh.Stdout
+	return nil
+}
+
+// command returns the command to execute, running in its own process group.
+func (sh *ShellJob) command(ctx context.Context) *exec.Cmd {
+	var cmd *exec.Cmd
+	if len(sh.Args) > 0 {
+		cmd = exec.CommandContext(ctx, sh.Args[0], sh.Args[1:]...)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", sh.Cmd)
+	}
+
+	cmd.Dir = sh.Dir
+	switch {
+	case sh.ReplaceEnv:
+		cmd.Env = append([]string{}, sh.Env...)
+	case len(sh.Env) > 0:
+		cmd.Env = append(os.Environ(), sh.Env...)
+	}
+	setProcessGroup(cmd)
+
+	return cmd
+}
+
+// Interrupt terminates the running commands of the ShellJob, sending
+// SIGTERM to their process groups so that they can exit gracefully. The
+// interrupted executions return ErrJobInterrupted.
+func (sh *ShellJob) Interrupt(reason string) {
+	sh.executions.interruptAll(reason)
+}
+
+// run runs the command of the execution, killing its process group once
+// the context is done, so that the children of the command are terminated
+// as well.
+func (sh *ShellJob) run(ctx context.Context, cmd *exec.Cmd, running *execution) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if sh.executions.onInterrupt(running, func() { terminateProcessGroup(cmd) }) {
+		terminateProcessGroup(cmd)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+	err := cmd.Wait()
+	close(done)
+
+	return err
+}
+
+// cappedBuffer is an io.Writer keeping the first limit bytes written to it,
+// and discarding the rest. A negative limit keeps all of the bytes.
+type cappedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write writes the part of p within the limit to the buffer.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.limit < 0 {
+		return b.buf.Write(p)
+	}
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+// String returns the contents of the buffer.
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
 }
 
 // CurlJob represents a cURL command Job, implements the quartz.Job interface.
@@ -88,8 +273,29 @@ type CurlJob struct {
 	StatusCode    int
 	JobStatus     JobStatus
 	request       *http.Request
+	client        *http.Client
+	responseLimit int
+
+	explicitKey
 }
 
+// CurlJobOptions represents the optional parameters of the CurlJob.
+type CurlJobOptions struct {
+	// HTTPClient is the client sending the requests of the CurlJob,
+	// e.g. to configure the timeouts or the proxy. When nil, the
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// ResponseLimit caps the number of the bytes of the response body
+	// kept in the Response, the rest of the body is discarded. When 0,
+	// a limit of 64 KiB is used. A negative limit disables the cap.
+	ResponseLimit int
+}
+
+// defaultResponseLimit is the number of the response body bytes kept by
+// a CurlJob, used when no limit is configured.
+const defaultResponseLimit = 64 << 10
+
 // NewCurlJob returns a new CurlJob.
 func NewCurlJob(
 	method string,
@@ -97,8 +303,7 @@ func NewCurlJob(
 	body string,
 	headers map[string]string,
 ) (*CurlJob, error) {
-	_body := bytes.NewBuffer([]byte(body))
-	req, err := http.NewRequest(method, url, _body)
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -107,15 +312,72 @@ func NewCurlJob(
 		req.Header.Set(k, v)
 	}
 
+	job, err := NewCurlJobWithOptions(req, CurlJobOptions{})
+	if err != nil {
+		return nil, err
+	}
+	job.URL = url
+	job.Headers = headers
+
+	return job, nil
+}
+
+// NewCurlJobWithOptions returns a new CurlJob sending the request. A copy
+// of the request with a fresh body is sent on every fire, so the body of
+// the request is read upfront unless it can be obtained using GetBody.
+func NewCurlJobWithOptions(request *http.Request, opts CurlJobOptions) (*CurlJob, error) {
+	req := request.Clone(context.Background())
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = int64(len(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	var body string
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		body = string(data)
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	responseLimit := opts.ResponseLimit
+	if responseLimit == 0 {
+		responseLimit = defaultResponseLimit
+	}
+
 	return &CurlJob{
-		RequestMethod: method,
-		URL:           url,
+		RequestMethod: req.Method,
+		URL:           req.URL.String(),
 		Body:          body,
 		Headers:       headers,
 		Response:      "",
 		StatusCode:    -1,
 		JobStatus:     NA,
 		request:       req,
+		client:        client,
+		responseLimit: responseLimit,
 	}, nil
 }
 
@@ -124,33 +386,101 @@ func (cu *CurlJob) Description() string {
 	return fmt.Sprintf("CurlJob: %s %s %s", cu.RequestMethod, cu.URL, cu.Body)
 }
 
-// Key returns the unique CurlJob key.
+// Key returns the unique CurlJob key, the hash of its method, URL, body
+// and headers unless assigned using WithKey.
 func (cu *CurlJob) Key() int {
-	return HashCode(cu.Description())
+	return cu.keyOr(func() int {
+		parts := []string{"CurlJob", cu.RequestMethod, cu.URL, cu.Body}
+		names := make([]string, 0, len(cu.Headers))
+		for name := range cu.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parts = append(parts, name, cu.Headers[name])
+		}
+		return HashKey(parts...)
+	})
+}
+
+// WithKey assigns the key of the CurlJob, so that the jobs sending the
+// same request can be scheduled independently. It returns the CurlJob.
+func (cu *CurlJob) WithKey(key int) *CurlJob {
+	cu.assign(key)
+	return cu
 }
 
 // Execute is called by a Scheduler when the Trigger associated with this job fires.
-func (cu *CurlJob) Execute(ctx context.Context) {
-	client := &http.Client{}
-	cu.request = cu.request.WithContext(ctx)
-	resp, err := client.Do(cu.request)
+// A response status code other than 2xx fails the execution.
+func (cu *CurlJob) Execute(ctx context.Context) error {
+	req, err := cu.newRequest(ctx)
 	if err != nil {
 		cu.JobStatus = FAILURE
 		cu.StatusCode = -1
 		cu.Response = err.Error()
-		return
+		return err
 	}
 
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		cu.JobStatus = OK
-	} else {
+	resp, err := cu.client.Do(req)
+	if err != nil {
 		cu.JobStatus = FAILURE
+		cu.StatusCode = -1
+		cu.Response = err.Error()
+		return err
 	}
 
+	defer resp.Body.Close()
+	body := &cappedBuffer{limit: cu.responseLimit}
+	_, err = io.Copy(body, resp.Body)
 	cu.StatusCode = resp.StatusCode
-	cu.Response = string(body)
+	cu.Response = body.String()
+	if err != nil {
+		cu.JobStatus = FAILURE
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		cu.JobStatus = FAILURE
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	cu.JobStatus = OK
+	return nil
+}
+
+// newRequest returns a copy of the request of the CurlJob, with a fresh body.
+func (cu *CurlJob) newRequest(ctx context.Context) (*http.Request, error) {
+	req := cu.request.Clone(ctx)
+	if cu.request.GetBody != nil {
+		body, err := cu.request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	return req, nil
+}
+
+// wrappedJob represents a Job executed by a function wrapping it.
+type wrappedJob struct {
+	Job
+	execute func(context.Context, Job) error
+}
+
+// Execute calls the wrapping function with the wrapped Job.
+func (j *wrappedJob) Execute(ctx context.Context) error {
+	return j.execute(ctx, j.Job)
+}
+
+// WrapJob returns a Job executed by calling the function with the given Job,
+// passing through its Key and Description. It simplifies writing the wrappers
+// of the StdSchedulerOptions.JobWrappers.
+func WrapJob(job Job, execute func(ctx context.Context, job Job) error) Job {
+	return &wrappedJob{
+		Job:     job,
+		execute: execute,
+	}
 }
 
 type isolatedJob struct {
@@ -160,13 +490,14 @@ type isolatedJob struct {
 }
 
 // Execute is called by a Scheduler when the Trigger associated with this job fires.
-func (j *isolatedJob) Execute(ctx context.Context) {
+// A fire is skipped without an error if the Job is already running.
+func (j *isolatedJob) Execute(ctx context.Context) error {
 	if wasRunning := j.isRunning.Swap(true); wasRunning != nil && wasRunning.(bool) {
-		return
+		return nil
 	}
 	defer j.isRunning.Store(false)
 
-	j.Job.Execute(ctx)
+	return j.Job.Execute(ctx)
 }
 
 // NewIsolatedJob wraps a job object and ensures that only one
@@ -0,0 +1,170 @@
+package quartz
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JobChainListener is implemented by the SchedulerListeners interested in
+// the steps of the JobChain executions.
+type JobChainListener interface {
+	// JobChainStepCompleted is called when a step of a JobChain returns,
+	// with the zero-based index of the step, the Job of the step and its
+	// error, nil if the step succeeded.
+	JobChainStepCompleted(job ScheduledJob, step int, stepJob Job, err error)
+}
+
+// JobChainError is returned by a JobChain whose steps failed.
+type JobChainError struct {
+	// Steps holds the zero-based indexes of the failed steps.
+	Steps []int
+	// Errors holds the errors of the failed steps.
+	Errors []error
+}
+
+// Error returns the string representation of the JobChainError.
+func (e *JobChainError) Error() string {
+	steps := make([]string, len(e.Steps))
+	errs := make([]string, len(e.Errors))
+	for i, step := range e.Steps {
+		steps[i] = strconv.Itoa(step)
+		errs[i] = e.Errors[i].Error()
+	}
+
+	if len(e.Steps) == 1 {
+		return fmt.Sprintf("job chain step %s failed: %s", steps[0], errs[0])
+	}
+
+	return fmt.Sprintf("job chain steps %s failed: %s", strings.Join(steps, ", "),
+		strings.Join(errs, "; "))
+}
+
+// Unwrap returns the error of the first failed step.
+func (e *JobChainError) Unwrap() error {
+	return e.Errors[0]
+}
+
+// JobChain represents a sequence of jobs executed as a single Job, implements
+// the quartz.Job interface. The steps are executed in order within a fire of
+// the JobChain.
+type JobChain struct {
+	// ContinueOnError makes the JobChain execute the steps following a
+	// failed step. Otherwise, the JobChain stops at the first failure.
+	ContinueOnError bool
+
+	jobs       []Job
+	desc       string
+	executions executions
+
+	explicitKey
+}
+
+// Verify JobChain satisfies the Job interface.
+var _ Job = (*JobChain)(nil)
+
+// Verify JobChain satisfies the InterruptableJob interface.
+var _ InterruptableJob = (*JobChain)(nil)
+
+// NewJobChain returns a new JobChain of the given jobs, described by the
+// descriptions of the jobs.
+func NewJobChain(jobs ...Job) *JobChain {
+	descriptions := make([]string, len(jobs))
+	for i, job := range jobs {
+		descriptions[i] = job.Description()
+	}
+
+	return NewJobChainWithDesc(fmt.Sprintf("JobChain: %s", strings.Join(descriptions, " -> ")), jobs...)
+}
+
+// NewJobChainWithDesc returns a new JobChain of the given jobs with an
+// explicit description.
+func NewJobChainWithDesc(desc string, jobs ...Job) *JobChain {
+	return &JobChain{
+		jobs: jobs,
+		desc: desc,
+	}
+}
+
+// Description returns the description of the JobChain.
+func (jc *JobChain) Description() string {
+	return jc.desc
+}
+
+// Key returns the unique JobChain key, the hash of its description and the
+// keys of its jobs unless assigned using WithKey.
+func (jc *JobChain) Key() int {
+	return jc.keyOr(func() int {
+		parts := []string{"JobChain", jc.desc}
+		for _, job := range jc.jobs {
+			parts = append(parts, strconv.Itoa(job.Key()))
+		}
+		return HashKey(parts...)
+	})
+}
+
+// WithKey assigns the key of the JobChain. It returns the JobChain.
+func (jc *JobChain) WithKey(key int) *JobChain {
+	jc.assign(key)
+	return jc
+}
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+// The steps are executed in order, until a step fails, unless ContinueOnError is
+// set, the context is done, or the JobChain is interrupted. The outcome of each
+// step is reported to the JobChainListeners of the StdScheduler executing the
+// JobChain. A panic of a step is reported as its error.
+func (jc *JobChain) Execute(ctx context.Context) error {
+	var chainErr *JobChainError
+	fail := func(step int, err error) {
+		if chainErr == nil {
+			chainErr = &JobChainError{}
+		}
+		chainErr.Steps = append(chainErr.Steps, step)
+		chainErr.Errors = append(chainErr.Errors, err)
+	}
+
+	running, done := jc.executions.start()
+	defer done()
+	for step, job := range jc.jobs {
+		if err := ctx.Err(); err != nil {
+			fail(step, err)
+			break
+		}
+		var interrupt func()
+		if interruptable, ok := job.(InterruptableJob); ok {
+			interrupt = func() { interruptable.Interrupt(running.reason) }
+		}
+		if jc.executions.onInterrupt(running, interrupt) {
+			fail(step, jc.executions.err(running, nil))
+			break
+		}
+
+		err := executeJob(ctx, job)
+		notifyListeners(ctx, func(l SchedulerListener, scheduled ScheduledJob) {
+			if listener, ok := l.(JobChainListener); ok {
+				listener.JobChainStepCompleted(scheduled, step, job, err)
+			}
+		})
+		if err != nil {
+			fail(step, err)
+			if !jc.ContinueOnError {
+				break
+			}
+		}
+	}
+
+	if chainErr != nil {
+		return chainErr
+	}
+
+	return nil
+}
+
+// Interrupt interrupts the current step of the running executions of the
+// JobChain, if the Job of the step implements the InterruptableJob
+// interface, and stops them before their next step.
+func (jc *JobChain) Interrupt(reason string) {
+	jc.executions.interruptAll(reason)
+}
@@ -0,0 +1,184 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type chainStep struct {
+	step int
+	err  error
+}
+
+type chainListener struct {
+	quartz.NoopListener
+	steps chan chainStep
+}
+
+func (l *chainListener) JobChainStepCompleted(_ quartz.ScheduledJob, step int, _ quartz.Job, err error) {
+	l.steps <- chainStep{step, err}
+}
+
+// recordingJob appends its name to the record, failing with the error.
+func recordingJob(name string, record *[]string, err error) quartz.Job {
+	return quartz.NewFunctionJobWithDesc(name, func(_ context.Context) (bool, error) {
+		*record = append(*record, name)
+		return err == nil, err
+	})
+}
+
+func TestJobChain(t *testing.T) {
+	ctx := context.Background()
+
+	var record []string
+	chain := quartz.NewJobChain(
+		recordingJob("extract", &record, nil),
+		recordingJob("transform", &record, nil),
+		recordingJob("load", &record, nil),
+	)
+	assertEqual(t, chain.Description(), "JobChain: extract -> transform -> load")
+	assertEqual(t, chain.Execute(ctx), nil)
+	assertEqual(t, record, []string{"extract", "transform", "load"})
+
+	override := quartz.NewJobChainWithDesc("nightly", chain)
+	assertEqual(t, override.Description(), "nightly")
+
+	// the keys are derived from the description and the jobs
+	assertEqual(t, override.Key(), quartz.NewJobChainWithDesc("nightly", chain).Key())
+	assertNotEqual(t, override.Key(), quartz.NewJobChainWithDesc("nightly", override).Key())
+	assertNotEqual(t, override.Key(), chain.Key())
+}
+
+func TestJobChainFailure(t *testing.T) {
+	ctx := context.Background()
+	errTransform := errors.New("transform failed")
+	errLoad := errors.New("load failed")
+
+	var record []string
+	chain := quartz.NewJobChain(
+		recordingJob("extract", &record, nil),
+		recordingJob("transform", &record, errTransform),
+		recordingJob("load", &record, errLoad),
+	)
+	err := chain.Execute(ctx)
+	assertEqual(t, err.Error(), "job chain step 1 failed: transform failed")
+	assertEqual(t, errors.Is(err, errTransform), true)
+	assertEqual(t, record, []string{"extract", "transform"})
+
+	record = nil
+	chain.ContinueOnError = true
+	err = chain.Execute(ctx)
+	assertEqual(t, err.Error(), "job chain steps 1, 2 failed: transform failed; load failed")
+	var chainErr *quartz.JobChainError
+	if !errors.As(err, &chainErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, chainErr.Steps, []int{1, 2})
+	assertEqual(t, record, []string{"extract", "transform", "load"})
+
+	// the panics fail the step
+	chain = quartz.NewJobChain(quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		panic("boom")
+	}))
+	assertEqual(t, chain.Execute(ctx).Error(), "job chain step 0 failed: job panicked: boom")
+}
+
+func TestJobChainCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var record []string
+	chain := quartz.NewJobChain(
+		quartz.NewFunctionJobWithDesc("cancel", func(_ context.Context) (bool, error) {
+			cancel()
+			return true, nil
+		}),
+		recordingJob("load", &record, nil),
+	)
+	err := chain.Execute(ctx)
+	assertEqual(t, errors.Is(err, context.Canceled), true)
+	assertEqual(t, len(record), 0)
+}
+
+func TestJobChainInterrupted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the running step is interrupted, and the next one is not executed
+	var record []string
+	chain := quartz.NewJobChain(
+		quartz.NewShellJob("sleep 5"),
+		recordingJob("load", &record, nil),
+	)
+	errs := make(chan error, 1)
+	go func() { errs <- chain.Execute(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	chain.Interrupt("maintenance")
+
+	err := <-errs
+	var chainErr *quartz.JobChainError
+	if !errors.As(err, &chainErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, chainErr.Steps, []int{0})
+	assertEqual(t, errors.Is(err, quartz.ErrJobInterrupted), true)
+	assertEqual(t, len(record), 0)
+
+	// the chain is interrupted before its next step when the running
+	// step is not interruptable
+	release := make(chan struct{})
+	chain = quartz.NewJobChain(
+		quartz.NewFunctionJobWithDesc("extract", func(_ context.Context) (bool, error) {
+			<-release
+			return true, nil
+		}),
+		recordingJob("load", &record, nil),
+	)
+	go func() { errs <- chain.Execute(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	chain.Interrupt("maintenance")
+	close(release)
+
+	err = <-errs
+	if !errors.As(err, &chainErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, chainErr.Steps, []int{1})
+	assertEqual(t, errors.Is(err, quartz.ErrJobInterrupted), true)
+	assertEqual(t, len(record), 0)
+}
+
+func TestJobChainListener(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := &chainListener{steps: make(chan chainStep, 3)}
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.AddListener(listener)
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	errTransform := errors.New("transform failed")
+	var record []string
+	chain := quartz.NewJobChain(
+		recordingJob("extract", &record, nil),
+		recordingJob("transform", &record, errTransform),
+		recordingJob("load", &record, nil),
+	)
+	if err := sched.ScheduleJob(ctx, chain, quartz.NewRunOnceTrigger(0)); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, <-listener.steps, chainStep{0, nil})
+	assertEqual(t, <-listener.steps, chainStep{1, errTransform})
+	select {
+	case step := <-listener.steps:
+		t.Fatal("unexpected step", step)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,372 @@
+package quartz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrInvalidJobConfig is returned for the invalid entries of a job
+// configuration.
+var ErrInvalidJobConfig = errors.New("invalid job config")
+
+var (
+	jobFactoriesMtx sync.RWMutex
+	jobFactories    = make(map[string]func(params map[string]any) (Job, error))
+)
+
+// RegisterJobFactory makes a Job type available to the JobLoader under the
+// given type name. The factory creates the Job from the parameters of the
+// configuration entry. The types registered using RegisterJob, including
+// the built-in "shell" and "curl" jobs, are available without a factory,
+// their parameters being the fields of their JSON encoding. A factory takes
+// precedence over a Job type registered under the same name.
+// RegisterJobFactory panics if the type name is already registered.
+func RegisterJobFactory(typeName string, factory func(params map[string]any) (Job, error)) {
+	jobFactoriesMtx.Lock()
+	defer jobFactoriesMtx.Unlock()
+
+	if _, ok := jobFactories[typeName]; ok {
+		panic(fmt.Sprintf("quartz: job factory %q is already registered", typeName))
+	}
+	jobFactories[typeName] = factory
+}
+
+// JobConfig is an entry of a job configuration, declaring a Job along with
+// its Trigger and scheduling options.
+type JobConfig struct {
+	// Name and Group form the JobKey of the Job. An empty Group is
+	// replaced with the DefaultGroup.
+	Name  string `json:"name"`
+	Group string `json:"group,omitempty"`
+
+	// Type is the type name of the Job, registered using
+	// RegisterJobFactory or RegisterJob.
+	Type string `json:"type"`
+
+	// Params are the parameters of the Job.
+	Params map[string]any `json:"params,omitempty"`
+
+	// Trigger is the Trigger of the Job, in the encoding of
+	// MarshalTrigger, e.g. {"type":"cron","expression":"0 0 * * *"}.
+	Trigger json.RawMessage `json:"trigger"`
+
+	// Timeout, e.g. "30s", bounds each execution of the Job.
+	// See WithTimeout.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Concurrency is the ConcurrencyPolicy of the Job: "allow",
+	// "skip" or "queue". Defaults to "allow".
+	Concurrency string `json:"concurrency,omitempty"`
+
+	// StartNow fires the Job immediately once scheduled.
+	// See WithStartNow.
+	StartNow bool `json:"start_now,omitempty"`
+}
+
+// jobConfigFile is the document of a job configuration.
+type jobConfigFile struct {
+	Jobs []JobConfig `json:"jobs"`
+}
+
+// JobConfigError is the error of an entry of a job configuration.
+type JobConfigError struct {
+	// Index is the position of the entry in the configuration,
+	// -1 for the removed entries whose Job could not be deleted.
+	Index int
+
+	// Key is the JobKey of the entry.
+	Key JobKey
+
+	// Err is the error of the entry.
+	Err error
+}
+
+// Error returns the description of the error.
+func (e *JobConfigError) Error() string {
+	return fmt.Sprintf("job config %d (%s): %v", e.Index, e.Key, e.Err)
+}
+
+// Unwrap returns the error of the entry.
+func (e *JobConfigError) Unwrap() error {
+	return e.Err
+}
+
+// LoadResult is the outcome of applying a job configuration.
+type LoadResult struct {
+	// Added are the keys of the newly scheduled jobs.
+	Added []JobKey
+
+	// Rescheduled are the keys of the jobs whose entries changed,
+	// which were replaced.
+	Rescheduled []JobKey
+
+	// Removed are the keys of the jobs whose entries were removed
+	// from the configuration, which were deleted.
+	Removed []JobKey
+
+	// Errors are the errors of the entries which were not applied.
+	Errors []*JobConfigError
+}
+
+// JobLoader schedules the jobs declared in a JSON configuration into a
+// Scheduler, keeping track of the loaded jobs to apply the changes of
+// the configuration when it is reloaded. A YAML configuration can be
+// loaded once converted to JSON. A configuration is an object holding the
+// JobConfig entries in its "jobs" field:
+//
+//	{"jobs": [{
+//	    "name": "backup",
+//	    "type": "shell",
+//	    "params": {"cmd": "tar -czf backup.tgz data"},
+//	    "trigger": {"type": "cron", "expression": "0 0 3 * * *"},
+//	    "timeout": "1h",
+//	    "concurrency": "skip"
+//	}]}
+//
+// The jobs are identified by the JobKeys of their entries when the
+// Scheduler supports it, as the StdScheduler does, and by the keys of the
+// jobs otherwise. The MisfirePolicy is configured for the whole Scheduler.
+type JobLoader struct {
+	mtx    sync.Mutex
+	sched  Scheduler
+	loaded map[JobKey]loadedEntry
+}
+
+// keyedScheduler is implemented by the Schedulers identifying the jobs by
+// their JobKeys, such as the StdScheduler.
+type keyedScheduler interface {
+	ScheduleJobWithKey(ctx context.Context, key JobKey, job Job, trigger Trigger, opts ...ScheduleOption) error
+	DeleteJobWithKey(key JobKey) error
+}
+
+// loadedEntry is an entry scheduled by the JobLoader.
+type loadedEntry struct {
+	spec   string
+	jobKey int
+}
+
+// NewJobLoader returns a new JobLoader scheduling the jobs into the
+// Scheduler.
+func NewJobLoader(sched Scheduler) *JobLoader {
+	return &JobLoader{
+		sched:  sched,
+		loaded: make(map[JobKey]loadedEntry),
+	}
+}
+
+// Load schedules the jobs of the configuration read from r. All of the
+// entries are validated before any is scheduled; the invalid entries,
+// and the entries which failed to be scheduled, e.g. because a Job with
+// the same key exists, are reported in the Errors of the LoadResult,
+// while the valid ones are scheduled. An error is returned if the
+// configuration cannot be decoded.
+func (l *JobLoader) Load(ctx context.Context, r io.Reader) (*LoadResult, error) {
+	return l.apply(ctx, r, false)
+}
+
+// Reload applies the configuration read from r to the jobs previously
+// loaded by the JobLoader: the new entries are scheduled, the jobs whose
+// entries changed are replaced, and the jobs whose entries were removed
+// are deleted. The jobs of the invalid entries are left unchanged.
+func (l *JobLoader) Reload(ctx context.Context, r io.Reader) (*LoadResult, error) {
+	return l.apply(ctx, r, true)
+}
+
+// loadedJob is a validated entry of a job configuration.
+type loadedJob struct {
+	index   int
+	key     JobKey
+	spec    string
+	job     Job
+	trigger Trigger
+	opts    []ScheduleOption
+}
+
+func (l *JobLoader) apply(ctx context.Context, r io.Reader, reload bool) (*LoadResult, error) {
+	var file jobConfigFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobConfig, err)
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	result := &LoadResult{}
+	declared := make(map[JobKey]struct{}, len(file.Jobs))
+	var jobs []*loadedJob
+	for i := range file.Jobs {
+		config := &file.Jobs[i]
+		key := NewJobKeyWithGroup(config.Name, config.Group)
+		if _, ok := declared[key]; ok {
+			result.Errors = append(result.Errors, &JobConfigError{Index: i, Key: key,
+				Err: fmt.Errorf("%w: duplicate key", ErrInvalidJobConfig)})
+			continue
+		}
+		declared[key] = struct{}{}
+
+		job, err := config.load(i, key)
+		if err != nil {
+			result.Errors = append(result.Errors, &JobConfigError{Index: i, Key: key, Err: err})
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	for _, job := range jobs {
+		entry, loaded := l.loaded[job.key]
+		if loaded && entry.spec == job.spec && reload {
+			continue
+		}
+
+		opts := job.opts
+		if loaded && reload {
+			opts = append(opts, WithReplaceExisting())
+		}
+		if err := l.schedule(ctx, job, opts); err != nil {
+			result.Errors = append(result.Errors, &JobConfigError{Index: job.index, Key: job.key, Err: err})
+			continue
+		}
+		if _, keyed := l.sched.(keyedScheduler); !keyed && loaded && reload && entry.jobKey != job.job.Key() {
+			// the Job of the changed entry has a new key, so that
+			// it did not replace the previous one
+			if err := l.delete(job.key, entry); err != nil && !errors.Is(err, ErrJobNotFound) {
+				result.Errors = append(result.Errors, &JobConfigError{Index: job.index, Key: job.key, Err: err})
+			}
+		}
+		l.loaded[job.key] = loadedEntry{spec: job.spec, jobKey: job.job.Key()}
+		if loaded && reload {
+			result.Rescheduled = append(result.Rescheduled, job.key)
+		} else {
+			result.Added = append(result.Added, job.key)
+		}
+	}
+
+	if reload {
+		for key, entry := range l.loaded {
+			if _, ok := declared[key]; ok {
+				continue
+			}
+			err := l.delete(key, entry)
+			switch {
+			case err == nil:
+				result.Removed = append(result.Removed, key)
+			case errors.Is(err, ErrJobNotFound):
+			default:
+				result.Errors = append(result.Errors, &JobConfigError{Index: -1, Key: key, Err: err})
+				continue
+			}
+			delete(l.loaded, key)
+		}
+	}
+
+	return result, nil
+}
+
+// schedule schedules the Job of the entry.
+func (l *JobLoader) schedule(ctx context.Context, job *loadedJob, opts []ScheduleOption) error {
+	if keyed, ok := l.sched.(keyedScheduler); ok {
+		return keyed.ScheduleJobWithKey(ctx, job.key, job.job, job.trigger, opts...)
+	}
+
+	return l.sched.ScheduleJob(ctx, job.job, job.trigger, opts...)
+}
+
+// delete deletes the Job of the loaded entry.
+func (l *JobLoader) delete(key JobKey, entry loadedEntry) error {
+	if keyed, ok := l.sched.(keyedScheduler); ok {
+		return keyed.DeleteJobWithKey(key)
+	}
+
+	return l.sched.DeleteJob(entry.jobKey)
+}
+
+// load validates the entry, creating its Job and Trigger.
+func (c *JobConfig) load(index int, key JobKey) (*loadedJob, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("%w: missing name", ErrInvalidJobConfig)
+	}
+	job, err := c.newJob()
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Trigger) == 0 {
+		return nil, fmt.Errorf("%w: missing trigger", ErrInvalidJobConfig)
+	}
+	trigger, err := UnmarshalTrigger(c.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []ScheduleOption
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJobConfig, err)
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+	switch c.Concurrency {
+	case "", "allow":
+	case "skip":
+		opts = append(opts, WithConcurrencyPolicy(ConcurrencySkip))
+	case "queue":
+		opts = append(opts, WithConcurrencyPolicy(ConcurrencyQueue))
+	default:
+		return nil, fmt.Errorf("%w: unknown concurrency policy %q", ErrInvalidJobConfig, c.Concurrency)
+	}
+	if c.StartNow {
+		opts = append(opts, WithStartNow())
+	}
+
+	// the canonical encoding of the entry detects its changes
+	var trimmed bytes.Buffer
+	if err := json.Compact(&trimmed, c.Trigger); err != nil {
+		return nil, err
+	}
+	canonical := *c
+	canonical.Trigger = trimmed.Bytes()
+	spec, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadedJob{
+		index:   index,
+		key:     key,
+		spec:    string(spec),
+		job:     job,
+		trigger: trigger,
+		opts:    opts,
+	}, nil
+}
+
+// newJob creates the Job of the entry, using the factory of its type, or
+// decoding its parameters as the JSON encoding of a registered Job type.
+func (c *JobConfig) newJob() (Job, error) {
+	jobFactoriesMtx.RLock()
+	factory, ok := jobFactories[c.Type]
+	jobFactoriesMtx.RUnlock()
+	if ok {
+		return factory(c.Params)
+	}
+
+	fields := make(map[string]any, len(c.Params)+1)
+	for name, value := range c.Params {
+		fields[name] = value
+	}
+	fields["type"] = c.Type
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalJob(data)
+}
@@ -0,0 +1,181 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func init() {
+	quartz.RegisterJobFactory("greet", func(params map[string]any) (quartz.Job, error) {
+		name, ok := params["name"].(string)
+		if !ok {
+			return nil, errors.New("missing name")
+		}
+		return quartz.NewFunctionJob(func(_ context.Context) (string, error) {
+			return "hello " + name, nil
+		}), nil
+	})
+}
+
+func TestJobLoader(t *testing.T) {
+	ctx := context.Background()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	loader := quartz.NewJobLoader(sched)
+
+	result, err := loader.Load(ctx, strings.NewReader(`{"jobs": [
+		{"name": "backup", "group": "ops", "type": "shell",
+		 "params": {"cmd": "tar -czf backup.tgz data"},
+		 "trigger": {"type": "cron", "expression": "0 0 3 * * *"},
+		 "timeout": "1h", "concurrency": "skip"},
+		{"name": "greet", "type": "greet", "params": {"name": "ops"},
+		 "trigger": {"type": "simple", "interval": "1m"}},
+		{"name": "greet", "type": "greet", "params": {"name": "dup"},
+		 "trigger": {"type": "simple", "interval": "1m"}},
+		{"name": "invalid", "type": "greet",
+		 "trigger": {"type": "simple", "interval": "1m"}},
+		{"name": "unknown", "type": "unknown",
+		 "trigger": {"type": "simple", "interval": "1m"}},
+		{"name": "timeout", "type": "shell", "params": {"cmd": "ls"},
+		 "trigger": {"type": "simple", "interval": "1m"}, "timeout": "never"},
+		{"name": "untriggered", "type": "shell", "params": {"cmd": "ls"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupKey := quartz.NewJobKeyWithGroup("backup", "ops")
+	greetKey := quartz.NewJobKey("greet")
+	assertEqual(t, result.Added, []quartz.JobKey{backupKey, greetKey})
+	assertEqual(t, len(result.Errors), 5)
+	indexes := make([]int, 0, len(result.Errors))
+	for _, configErr := range result.Errors {
+		indexes = append(indexes, configErr.Index)
+	}
+	assertEqual(t, indexes, []int{2, 3, 4, 5, 6})
+	assertEqual(t, errors.Is(result.Errors[0], quartz.ErrInvalidJobConfig), true)
+	assertEqual(t, errors.Is(result.Errors[2], quartz.ErrUnknownJobType), true)
+	assertEqual(t, errors.Is(result.Errors[3], quartz.ErrInvalidJobConfig), true)
+	assertEqual(t, errors.Is(result.Errors[4], quartz.ErrInvalidJobConfig), true)
+
+	keys := sched.GetJobKeysWithGroup("ops")
+	assertEqual(t, keys, []quartz.JobKey{backupKey})
+	for _, scheduled := range sched.GetScheduledJobs() {
+		if scheduled.Key == backupKey {
+			assertEqual(t, scheduled.Timeout, time.Hour)
+			assertEqual(t, scheduled.Job.Description(), "ShellJob: tar -czf backup.tgz data")
+		}
+	}
+
+	// loading the same entries again fails as the jobs exist
+	result, err = loader.Load(ctx, strings.NewReader(`{"jobs": [
+		{"name": "greet", "type": "greet", "params": {"name": "ops"},
+		 "trigger": {"type": "simple", "interval": "1m"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(result.Added), 0)
+	assertEqual(t, errors.Is(result.Errors[0], quartz.ErrJobAlreadyExists), true)
+
+	_, err = loader.Load(ctx, strings.NewReader(`{"jobs": [{"name": "x", "unknown": true}]}`))
+	assertEqual(t, errors.Is(err, quartz.ErrInvalidJobConfig), true)
+}
+
+func TestJobLoaderReload(t *testing.T) {
+	ctx := context.Background()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	loader := quartz.NewJobLoader(sched)
+
+	entry := func(name, cmd string) string {
+		return fmt.Sprintf(`{"name": %q, "type": "shell", "params": {"cmd": %q},
+			"trigger": {"type": "simple", "interval": "1m"}}`, name, cmd)
+	}
+	config := func(entries ...string) *strings.Reader {
+		return strings.NewReader(`{"jobs": [` + strings.Join(entries, ",") + `]}`)
+	}
+
+	result, err := loader.Load(ctx, config(entry("a", "ls"), entry("b", "ls"), entry("c", "ls")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(result.Added), 3)
+
+	// a job scheduled out of the loader is left unchanged
+	if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey("manual"),
+		quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = loader.Reload(ctx, config(entry("a", "ls"), entry("b", "pwd"), entry("d", "ls"),
+		`{"name": "e", "type": "unknown", "trigger": {"type": "simple", "interval": "1s"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, result.Added, []quartz.JobKey{quartz.NewJobKey("d")})
+	assertEqual(t, result.Rescheduled, []quartz.JobKey{quartz.NewJobKey("b")})
+	assertEqual(t, result.Removed, []quartz.JobKey{quartz.NewJobKey("c")})
+	assertEqual(t, len(result.Errors), 1)
+
+	descriptions := make(map[string]string)
+	for _, scheduled := range sched.GetScheduledJobs() {
+		descriptions[scheduled.Key.Name] = scheduled.Job.Description()
+	}
+	assertEqual(t, descriptions, map[string]string{
+		"a":      "ShellJob: ls",
+		"b":      "ShellJob: pwd",
+		"d":      "ShellJob: ls",
+		"manual": "ShellJob: ls",
+	})
+
+	// reloading the same configuration is a no-op
+	result, err = loader.Reload(ctx, config(entry("a", "ls"), entry("b", "pwd"), entry("d", "ls")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(result.Added)+len(result.Rescheduled)+len(result.Removed), 0)
+}
+
+func TestJobLoaderScheduler(t *testing.T) {
+	ctx := context.Background()
+	// the embedded interface hides the JobKey based methods
+	sched := struct{ quartz.Scheduler }{quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})}
+	loader := quartz.NewJobLoader(sched)
+
+	config := func(cmd string) *strings.Reader {
+		return strings.NewReader(fmt.Sprintf(`{"jobs": [{"name": "a", "type": "shell",
+			"params": {"cmd": %q}, "trigger": {"type": "simple", "interval": "1m"}}]}`, cmd))
+	}
+	result, err := loader.Load(ctx, config("ls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, result.Added, []quartz.JobKey{quartz.NewJobKey("a")})
+	assertEqual(t, sched.GetJobKeys(), []int{quartz.NewShellJob("ls").Key()})
+
+	// the Job of the changed entry replaces the previous one
+	result, err = loader.Reload(ctx, config("pwd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, result.Rescheduled, []quartz.JobKey{quartz.NewJobKey("a")})
+	assertEqual(t, len(result.Errors), 0)
+	assertEqual(t, sched.GetJobKeys(), []int{quartz.NewShellJob("pwd").Key()})
+
+	result, err = loader.Reload(ctx, strings.NewReader(`{"jobs": []}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, result.Removed, []quartz.JobKey{quartz.NewJobKey("a")})
+	assertEqual(t, len(sched.GetJobKeys()), 0)
+}
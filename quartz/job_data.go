@@ -0,0 +1,71 @@
+package quartz
+
+import "context"
+
+// WithData attaches the data to the Job, available to its executions
+// using DataFrom or the ExecutionContext, so that the same Job
+// implementation can be scheduled with different parameters. The map is
+// copied, and the data of the scheduled Job is replaced using
+// SetJobData. The values have to be encodable to JSON when the Job is
+// kept by the FileJobQueue, which decodes the numbers as float64.
+func WithData(data map[string]any) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.data = copyData(data)
+	}
+}
+
+// DataFrom returns the data of the executed Job, attached using WithData
+// or SetJobData, if the context was passed to the Job by a Scheduler.
+// The map is shared by the executions of the Job, and must not be
+// modified.
+func DataFrom(ctx context.Context) (map[string]any, bool) {
+	execCtx, ok := ExecutionContextFrom(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	return execCtx.Data, true
+}
+
+// SetJobData replaces the data of the Job with the specified key, taking
+// effect on its next fire. The running executions keep the data they
+// were started with. The map is copied.
+func (sched *StdScheduler) SetJobData(key int, data map[string]any) error {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	it := sched.findItem(intJobKey(key))
+	if it == nil {
+		return ErrJobNotFound
+	}
+
+	it.opts.data = copyData(data)
+	if _, ok := sched.inflight[it]; ok {
+		// the item is queued again once returned by the
+		// execution loop
+		return nil
+	}
+
+	// the Job is queued again, so that a persistent JobQueue
+	// stores the data
+	if err := sched.dequeue(it); err != nil {
+		return err
+	}
+	sched.push(it)
+	sched.resetHead()
+	return nil
+}
+
+// copyData returns a shallow copy of the data, nil if it is empty.
+func copyData(data map[string]any) map[string]any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]any, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+
+	return copied
+}
@@ -0,0 +1,116 @@
+package quartz_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerJobData(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	// the same function is scheduled with different parameters
+	regions := make(chan any, 2)
+	report := func(ctx context.Context) error {
+		data, ok := quartz.DataFrom(ctx)
+		if !ok {
+			t.Error("no data in the context")
+		}
+		regions <- data["region"]
+		return nil
+	}
+	data := map[string]any{"region": "eu"}
+	euKey, err := sched.ScheduleFunc(ctx, report, quartz.NewRunOnceTrigger(time.Hour), quartz.WithData(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	usKey, err := sched.ScheduleFunc(ctx, report, quartz.NewRunOnceTrigger(time.Hour),
+		quartz.WithData(map[string]any{"region": "us"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the data is copied when the Job is scheduled
+	data["region"] = "ap"
+
+	job, err := sched.GetScheduledJob(euKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.Data, map[string]any{"region": "eu"})
+	for _, key := range []int{euKey, usKey} {
+		if err := sched.TriggerJob(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	received := map[any]bool{<-regions: true, <-regions: true}
+	assertEqual(t, received, map[any]bool{"eu": true, "us": true})
+
+	// the replaced data is passed to the next fire
+	if err := sched.SetJobData(euKey, map[string]any{"region": "ap"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.TriggerJob(ctx, euKey); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, <-regions, any("ap"))
+	assertEqual(t, sched.SetJobData(-1, nil), quartz.ErrJobNotFound)
+}
+
+func TestFileJobQueueJobData(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+		Queue:  openFileJobQueue(t, path),
+	})
+
+	job := quartz.NewShellJob("ls")
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithData(map[string]any{"region": "eu", "shards": 3})); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.SetJobData(job.Key(), map[string]any{"region": "us", "shards": 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the data is decoded from JSON
+	jobs, err := openFileJobQueue(t, path).Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(jobs), 1)
+	assertEqual(t, jobs[0].Data, map[string]any{"region": "us", "shards": float64(4)})
+}
+
+func TestSimulationSchedulerJobData(t *testing.T) {
+	ctx := context.Background()
+	sim := quartz.NewSimulationScheduler(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err := sim.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var region any
+	job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+		data, _ := quartz.DataFrom(ctx)
+		region = data["region"]
+		return true, nil
+	})
+	if err := sim.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Minute),
+		quartz.WithData(map[string]any{"region": "eu"})); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Minute)
+	assertEqual(t, region, any("eu"))
+}
@@ -0,0 +1,201 @@
+package quartz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JobGroupListener is implemented by the SchedulerListeners interested in
+// the members of the JobGroup executions.
+type JobGroupListener interface {
+	// JobGroupMemberCompleted is called when a member of a JobGroup
+	// returns, with the zero-based index of the member, the member Job
+	// and its error, nil if the member succeeded. It is called from the
+	// goroutines of the members, concurrently.
+	JobGroupMemberCompleted(job ScheduledJob, member int, memberJob Job, err error)
+}
+
+// JobGroupError is returned by a JobGroup whose members failed.
+type JobGroupError struct {
+	// Members holds the zero-based indexes of the failed members, in
+	// ascending order.
+	Members []int
+	// Errors holds the errors of the failed members.
+	Errors []error
+}
+
+// Error returns the string representation of the JobGroupError.
+func (e *JobGroupError) Error() string {
+	members := make([]string, len(e.Members))
+	errs := make([]string, len(e.Errors))
+	for i, member := range e.Members {
+		members[i] = strconv.Itoa(member)
+		errs[i] = e.Errors[i].Error()
+	}
+
+	if len(e.Members) == 1 {
+		return fmt.Sprintf("job group member %s failed: %s", members[0], errs[0])
+	}
+
+	return fmt.Sprintf("job group members %s failed: %s", strings.Join(members, ", "),
+		strings.Join(errs, "; "))
+}
+
+// Unwrap returns the error of the first failed member.
+func (e *JobGroupError) Unwrap() error {
+	return e.Errors[0]
+}
+
+// Is reports whether the error of any of the failed members matches the
+// target.
+func (e *JobGroupError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JobGroup represents a set of independent jobs executed in parallel as a
+// single Job, implements the quartz.Job interface. Up to maxConcurrent
+// members run at the same time within a fire of the JobGroup, and the fire
+// fails if any of the members fails.
+type JobGroup struct {
+	// CancelOnError makes the JobGroup stop launching the members once a
+	// member fails, and cancel the context of the running members.
+	// Otherwise, all of the members are executed.
+	CancelOnError bool
+
+	jobs          []Job
+	maxConcurrent int
+	desc          string
+
+	explicitKey
+}
+
+// Verify JobGroup satisfies the Job interface.
+var _ Job = (*JobGroup)(nil)
+
+// NewJobGroup returns a new JobGroup of the given jobs, running up to
+// maxConcurrent of them at the same time, all of them if maxConcurrent is
+// not greater than 0. The JobGroup is described by the descriptions of the
+// jobs.
+func NewJobGroup(maxConcurrent int, jobs ...Job) *JobGroup {
+	descriptions := make([]string, len(jobs))
+	for i, job := range jobs {
+		descriptions[i] = job.Description()
+	}
+
+	return NewJobGroupWithDesc(fmt.Sprintf("JobGroup: %s", strings.Join(descriptions, " | ")),
+		maxConcurrent, jobs...)
+}
+
+// NewJobGroupWithDesc returns a new JobGroup of the given jobs with an
+// explicit description.
+func NewJobGroupWithDesc(desc string, maxConcurrent int, jobs ...Job) *JobGroup {
+	if maxConcurrent <= 0 || maxConcurrent > len(jobs) {
+		maxConcurrent = len(jobs)
+	}
+
+	return &JobGroup{
+		jobs:          jobs,
+		maxConcurrent: maxConcurrent,
+		desc:          desc,
+	}
+}
+
+// Description returns the description of the JobGroup.
+func (jg *JobGroup) Description() string {
+	return jg.desc
+}
+
+// Key returns the unique JobGroup key, the hash of its description unless
+// assigned using WithKey.
+func (jg *JobGroup) Key() int {
+	return jg.keyOr(func() int { return HashKey("JobGroup", jg.desc) })
+}
+
+// WithKey assigns the key of the JobGroup. It returns the JobGroup.
+func (jg *JobGroup) WithKey(key int) *JobGroup {
+	jg.assign(key)
+	return jg
+}
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+// The members are launched in order, up to maxConcurrent at the same time, and the
+// JobGroup returns once all of the launched members have returned. Once the context
+// is done, the remaining members are not launched, and the first of them fails with
+// the error of the context. The outcome of each member is reported to the
+// JobGroupListeners of the StdScheduler executing the JobGroup. A panic of a member
+// is reported as its error, without affecting the other members.
+func (jg *JobGroup) Execute(ctx context.Context) error {
+	memberCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type failure struct {
+		member int
+		err    error
+	}
+	var mtx sync.Mutex
+	var failures []failure
+	fail := func(member int, err error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		failures = append(failures, failure{member, err})
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jg.maxConcurrent)
+	for member, job := range jg.jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-memberCtx.Done():
+		}
+		if err := memberCtx.Err(); err != nil {
+			fail(member, err)
+			break
+		}
+
+		wg.Add(1)
+		go func(member int, job Job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := executeJob(memberCtx, job)
+			notifyListeners(ctx, func(l SchedulerListener, scheduled ScheduledJob) {
+				if listener, ok := l.(JobGroupListener); ok {
+					listener.JobGroupMemberCompleted(scheduled, member, job, err)
+				}
+			})
+			if err != nil {
+				fail(member, err)
+				if jg.CancelOnError {
+					cancel()
+				}
+			}
+		}(member, job)
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].member < failures[j].member
+	})
+	groupErr := &JobGroupError{}
+	for _, f := range failures {
+		groupErr.Members = append(groupErr.Members, f.member)
+		groupErr.Errors = append(groupErr.Errors, f.err)
+	}
+
+	return groupErr
+}
@@ -0,0 +1,120 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type groupListener struct {
+	quartz.NoopListener
+	members chan chainStep
+}
+
+func (l *groupListener) JobGroupMemberCompleted(_ quartz.ScheduledJob, member int, _ quartz.Job, err error) {
+	l.members <- chainStep{member, err}
+}
+
+func TestJobGroup(t *testing.T) {
+	var running, maxRunning, succeeded int32
+	errMember := errors.New("member failed")
+	jobs := make([]quartz.Job, 12)
+	for i := range jobs {
+		i := i
+		jobs[i] = quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prev := atomic.LoadInt32(&maxRunning)
+				if n <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			switch i {
+			case 3:
+				return false, errMember
+			case 7:
+				panic("member panicked")
+			}
+			atomic.AddInt32(&succeeded, 1)
+			return true, nil
+		})
+	}
+
+	// the panic of a member does not abort the other ones
+	err := quartz.NewJobGroup(4, jobs...).Execute(context.Background())
+	var groupErr *quartz.JobGroupError
+	if !errors.As(err, &groupErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, groupErr.Members, []int{3, 7})
+	assertEqual(t, errors.Is(err, errMember), true)
+	assertEqual(t, atomic.LoadInt32(&succeeded), int32(10))
+	assertEqual(t, atomic.LoadInt32(&maxRunning), int32(4))
+}
+
+func TestJobGroupCanceled(t *testing.T) {
+	var record []string
+	errExtract := errors.New("extract failed")
+
+	// the remaining members are not launched once a member fails
+	group := quartz.NewJobGroup(1,
+		recordingJob("extract", &record, errExtract),
+		recordingJob("transform", &record, nil),
+		recordingJob("load", &record, nil),
+	)
+	group.CancelOnError = true
+	err := group.Execute(context.Background())
+	var groupErr *quartz.JobGroupError
+	if !errors.As(err, &groupErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, groupErr.Members, []int{0, 1})
+	assertEqual(t, errors.Is(err, context.Canceled), true)
+	assertEqual(t, record, []string{"extract"})
+
+	// the members are not launched once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	record = nil
+	err = quartz.NewJobGroup(0, recordingJob("load", &record, nil)).Execute(ctx)
+	assertEqual(t, errors.Is(err, context.Canceled), true)
+	assertEqual(t, len(record), 0)
+}
+
+func TestJobGroupListener(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := &groupListener{members: make(chan chainStep, 3)}
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.AddListener(listener)
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	errTransform := errors.New("transform failed")
+	var record []string
+	group := quartz.NewJobGroup(1,
+		recordingJob("extract", &record, nil),
+		recordingJob("transform", &record, errTransform),
+		recordingJob("load", &record, nil),
+	)
+	if err := sched.ScheduleJob(ctx, group, quartz.NewRunOnceTrigger(0)); err != nil {
+		t.Fatal(err)
+	}
+	members := make(map[int]error)
+	for i := 0; i < 3; i++ {
+		member := <-listener.members
+		members[member.step] = member.err
+	}
+	assertEqual(t, members, map[int]error{0: nil, 1: errTransform, 2: nil})
+}
@@ -0,0 +1,145 @@
+package quartz
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrUnknownJobType is returned when decoding a Job of a type which is
+// not registered.
+var ErrUnknownJobType = errors.New("unknown job type")
+
+var jobRegistry = newTypeRegistry[Job]("job", ErrUnknownJobType)
+
+func init() {
+	RegisterJob("shell", func() Job { return &ShellJob{} })
+	RegisterJob("curl", func() Job { return &CurlJob{} })
+}
+
+// RegisterJob makes a Job type available to MarshalJob and UnmarshalJob
+// under the given type name. The factory returns a new zero value of the
+// type, which has to support the JSON encoding. The built-in jobs are
+// registered as "shell" and "curl". RegisterJob panics if the type name
+// or the type is already registered.
+func RegisterJob(typeName string, factory func() Job) {
+	jobRegistry.register(typeName, factory)
+}
+
+// MarshalJob returns the JSON encoding of the registered Job, extended
+// with its type name in the "type" field.
+func MarshalJob(job Job) ([]byte, error) {
+	return jobRegistry.marshal(job)
+}
+
+// UnmarshalJob decodes a Job encoded by MarshalJob, using the registered
+// type of its "type" field.
+func UnmarshalJob(data []byte) (Job, error) {
+	return jobRegistry.unmarshal(data)
+}
+
+// keyJSON returns the JSON representation of the assigned key, nil if no
+// key is assigned.
+func (k *explicitKey) keyJSON() *int {
+	if !k.set {
+		return nil
+	}
+
+	key := k.key
+	return &key
+}
+
+// fromKeyJSON assigns the key of its JSON representation, if any.
+func (k *explicitKey) fromKeyJSON(key *int) {
+	if key != nil {
+		k.assign(*key)
+	}
+}
+
+type shellJobJSON struct {
+	Cmd         string       `json:"cmd,omitempty"`
+	Args        []string     `json:"args,omitempty"`
+	Env         []string     `json:"env,omitempty"`
+	ReplaceEnv  bool         `json:"replace_env,omitempty"`
+	Dir         string       `json:"dir,omitempty"`
+	Timeout     jsonDuration `json:"timeout,omitempty"`
+	OutputLimit int          `json:"output_limit,omitempty"`
+	Key         *int         `json:"key,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The outcome of the
+// last run is not encoded.
+func (sh *ShellJob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(shellJobJSON{
+		Cmd:         sh.Cmd,
+		Args:        sh.Args,
+		Env:         sh.Env,
+		ReplaceEnv:  sh.ReplaceEnv,
+		Dir:         sh.Dir,
+		Timeout:     jsonDuration(sh.Timeout),
+		OutputLimit: sh.OutputLimit,
+		Key:         sh.keyJSON(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (sh *ShellJob) UnmarshalJSON(data []byte) error {
+	var v shellJobJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*sh = *NewShellJob(v.Cmd)
+	sh.Args = v.Args
+	sh.Env = v.Env
+	sh.ReplaceEnv = v.ReplaceEnv
+	sh.Dir = v.Dir
+	sh.Timeout = time.Duration(v.Timeout)
+	sh.OutputLimit = v.OutputLimit
+	sh.fromKeyJSON(v.Key)
+
+	return nil
+}
+
+type curlJobJSON struct {
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	Body          string            `json:"body,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	ResponseLimit int               `json:"response_limit,omitempty"`
+	Key           *int              `json:"key,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The outcome of the
+// last run and the http.Client of the CurlJob are not encoded, a decoded
+// CurlJob uses the http.DefaultClient.
+func (cu *CurlJob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(curlJobJSON{
+		Method:        cu.RequestMethod,
+		URL:           cu.URL,
+		Body:          cu.Body,
+		Headers:       cu.Headers,
+		ResponseLimit: cu.responseLimit,
+		Key:           cu.keyJSON(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (cu *CurlJob) UnmarshalJSON(data []byte) error {
+	var v curlJobJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	job, err := NewCurlJob(v.Method, v.URL, v.Body, v.Headers)
+	if err != nil {
+		return err
+	}
+
+	*cu = *job
+	if v.ResponseLimit != 0 {
+		cu.responseLimit = v.ResponseLimit
+	}
+	cu.fromKeyJSON(v.Key)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package quartz_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func roundTripJob(t *testing.T, job quartz.Job) quartz.Job {
+	t.Helper()
+
+	data, err := quartz.MarshalJob(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := quartz.UnmarshalJob(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, fmt.Sprintf("%T", decoded), fmt.Sprintf("%T", job))
+	assertEqual(t, decoded.Description(), job.Description())
+	assertEqual(t, decoded.Key(), job.Key())
+
+	return decoded
+}
+
+func TestJobJSON(t *testing.T) {
+	shellJob := quartz.NewShellJobWithArgs("tar", "-czf", "backup.tgz", "data")
+	shellJob.Env = []string{"LANG=C"}
+	shellJob.ReplaceEnv = true
+	shellJob.Dir = "/var/backups"
+	shellJob.Timeout = time.Minute
+	shellJob.OutputLimit = -1
+	shellJob.Stdout = "discarded"
+	decodedShell := roundTripJob(t, shellJob).(*quartz.ShellJob)
+	assertEqual(t, decodedShell.Env, shellJob.Env)
+	assertEqual(t, decodedShell.ReplaceEnv, true)
+	assertEqual(t, decodedShell.Dir, shellJob.Dir)
+	assertEqual(t, decodedShell.Timeout, time.Minute)
+	assertEqual(t, decodedShell.OutputLimit, -1)
+	assertEqual(t, decodedShell.Stdout, "")
+	assertEqual(t, decodedShell.ExitCode, -1)
+	roundTripJob(t, quartz.NewShellJob("ls -la").WithKey(42))
+
+	curlJob, err := quartz.NewCurlJob(http.MethodPost, "http://localhost:8080/hook", "{}",
+		map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedCurl := roundTripJob(t, curlJob.WithKey(7)).(*quartz.CurlJob)
+	assertEqual(t, decodedCurl.Headers, curlJob.Headers)
+	assertEqual(t, decodedCurl.StatusCode, -1)
+}
+
+func TestUnmarshalJobUnknown(t *testing.T) {
+	_, err := quartz.UnmarshalJob([]byte(`{"type":"unknown"}`))
+	if !errors.Is(err, quartz.ErrUnknownJobType) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = quartz.MarshalJob(quartz.NewJobChain())
+	if !errors.Is(err, quartz.ErrUnknownJobType) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = quartz.UnmarshalJob([]byte(`{"type":"curl","method":"GET","url":":"}`))
+	assertNotEqual(t, err, nil)
+}
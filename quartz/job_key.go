@@ -0,0 +1,98 @@
+package quartz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultGroup is the group of the jobs scheduled without an explicit
+// group, including the jobs scheduled using the integer Job key.
+const DefaultGroup = "default"
+
+// JobKey represents the identity of a scheduled Job, a name unique
+// within a group.
+type JobKey struct {
+	Name  string
+	Group string
+}
+
+// NewJobKey returns a new JobKey with the given name in the DefaultGroup.
+func NewJobKey(name string) JobKey {
+	return NewJobKeyWithGroup(name, DefaultGroup)
+}
+
+// NewJobKeyWithGroup returns a new JobKey with the given name and group.
+// An empty group is replaced with the DefaultGroup.
+func NewJobKeyWithGroup(name, group string) JobKey {
+	if group == "" {
+		group = DefaultGroup
+	}
+
+	return JobKey{
+		Name:  name,
+		Group: group,
+	}
+}
+
+// String returns the string representation of the JobKey.
+func (k JobKey) String() string {
+	return fmt.Sprintf("%s::%s", k.Group, k.Name)
+}
+
+// intJobKey returns the JobKey corresponding to the integer Job key.
+func intJobKey(key int) JobKey {
+	return NewJobKey(strconv.Itoa(key))
+}
+
+// intKey returns the integer Job key corresponding to the JobKey, if any.
+func (k JobKey) intKey() (int, bool) {
+	if k.Group != DefaultGroup {
+		return 0, false
+	}
+
+	key, err := strconv.Atoi(k.Name)
+	if err != nil || strconv.Itoa(key) != k.Name {
+		return 0, false
+	}
+
+	return key, true
+}
+
+// uniqueKeyBase is the first key returned by UniqueJobKey, the start of
+// the upper half of the non-negative range of int, which HashKey does not
+// produce.
+const uniqueKeyBase = math.MaxInt>>1 + 1
+
+// uniqueKeys is the number of the keys returned by UniqueJobKey.
+var uniqueKeys int64
+
+// UniqueJobKey returns a new Job key, unique within the process, to be
+// assigned to a Job using its WithKey method. The returned keys do not
+// collide with the keys of the built-in jobs derived using HashKey.
+func UniqueJobKey() int {
+	return uniqueKeyBase + int(atomic.AddInt64(&uniqueKeys, 1)-1)
+}
+
+// explicitKey holds the key assigned to a built-in Job using its WithKey
+// method, which overrides the key derived from the Job.
+type explicitKey struct {
+	key int
+	set bool
+}
+
+// keyOr returns the assigned key, or the derived one if no key is assigned.
+func (k *explicitKey) keyOr(derive func() int) int {
+	if k.set {
+		return k.key
+	}
+
+	return derive()
+}
+
+// assign assigns the key.
+func (k *explicitKey) assign(key int) {
+	k.key = key
+	k.set = true
+}
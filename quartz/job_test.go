@@ -3,8 +3,13 @@ package quartz_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -82,3 +87,292 @@ func TestMultipleExecution(t *testing.T) {
 		t.Error("only one job should run")
 	}
 }
+
+func TestJobExecuteError(t *testing.T) {
+	ctx := context.Background()
+
+	if err := quartz.NewShellJob("true").Execute(ctx); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := quartz.NewShellJob("exit 1").Execute(ctx); err == nil {
+		t.Fatal("expected the shell command failure")
+	}
+
+	shellJob := quartz.NewShellJob("echo $GREETING")
+	shellJob.Env = []string{"GREETING=hello"}
+	if err := shellJob.Execute(ctx); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, shellJob.Result, "hello\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "/ok", wantErr: false},
+		{path: "/fail", wantErr: true},
+	} {
+		curlJob, err := quartz.NewCurlJob(http.MethodGet, server.URL+tt.path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := curlJob.Execute(ctx); (err != nil) != tt.wantErr {
+			t.Fatal("unexpected error", tt.path, err)
+		}
+	}
+
+	errFunction := errors.New("function error")
+	funcJob := quartz.NewFunctionJob(func(_ context.Context) (int, error) {
+		return 0, errFunction
+	})
+	if err := funcJob.Execute(ctx); !errors.Is(err, errFunction) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+func TestShellJob(t *testing.T) {
+	ctx := context.Background()
+
+	job := quartz.NewShellJob("echo out; echo oops >&2; exit 3")
+	assertNotEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.JobStatus, quartz.FAILURE)
+	assertEqual(t, job.Stdout, "out\n")
+	assertEqual(t, job.Stderr, "oops\n")
+	assertEqual(t, job.ExitCode, 3)
+
+	// the arguments are not interpreted by a shell
+	job = quartz.NewShellJobWithArgs("echo", "$HOME;", "exit", "1")
+	assertEqual(t, job.Description(), "ShellJob: echo $HOME; exit 1")
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.Result, "$HOME; exit 1\n")
+	assertEqual(t, job.ExitCode, 0)
+
+	dir := t.TempDir()
+	job = quartz.NewShellJob("pwd; echo ${TOKEN}${HOME}")
+	job.Dir = dir
+	job.Env = []string{"TOKEN=secret"}
+	job.ReplaceEnv = true
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, job.Stdout, dir+"\nsecret\n")
+
+	job = quartz.NewShellJob("head -c 100000 /dev/zero")
+	job.OutputLimit = 10
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, len(job.Stdout), 10)
+}
+
+func TestShellJobTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not supported")
+	}
+
+	// the children of the shell holding the output are killed as well
+	job := quartz.NewShellJob("sleep 5 | cat")
+	job.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	err := job.Execute(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatal("the process group was not killed", elapsed)
+	}
+	assertEqual(t, job.ExitCode, -1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	err = quartz.NewShellJob("sleep 5 | cat").Execute(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+type countingTransport struct {
+	requests int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCurlJob(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/moved" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Method + " " + string(body)))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL,
+		io.NopCloser(strings.NewReader(`{"id":1}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	transport := &countingTransport{}
+	job, err := quartz.NewCurlJobWithOptions(req, quartz.CurlJobOptions{
+		HTTPClient:    &http.Client{Transport: transport, Timeout: time.Second},
+		ResponseLimit: 8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.Description(), fmt.Sprintf(`CurlJob: POST %s {"id":1}`, server.URL))
+	assertEqual(t, job.Headers["Content-Type"], "application/json")
+
+	// the body is sent on every fire
+	for i := 0; i < 2; i++ {
+		assertEqual(t, job.Execute(ctx), nil)
+		assertEqual(t, job.JobStatus, quartz.OK)
+		assertEqual(t, job.StatusCode, http.StatusOK)
+		assertEqual(t, job.Response, `POST {"i`)
+	}
+	assertEqual(t, atomic.LoadInt32(&transport.requests), int32(2))
+
+	// the status codes other than 2xx fail the execution
+	for _, tt := range []struct {
+		path       string
+		headers    map[string]string
+		statusCode int
+	}{
+		{"/", nil, http.StatusUnauthorized},
+		{"/moved", map[string]string{"Authorization": "Bearer token"}, http.StatusNotModified},
+	} {
+		job, err = quartz.NewCurlJob(http.MethodGet, server.URL+tt.path, "", tt.headers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNotEqual(t, job.Execute(ctx), nil)
+		assertEqual(t, job.JobStatus, quartz.FAILURE)
+		assertEqual(t, job.StatusCode, tt.statusCode)
+	}
+}
+
+func TestJobKeys(t *testing.T) {
+	// the arguments are not joined into the same command
+	assertNotEqual(t, quartz.NewShellJobWithArgs("echo", "a b").Key(),
+		quartz.NewShellJobWithArgs("echo", "a", "b").Key())
+	withDir := quartz.NewShellJob("ls")
+	withDir.Dir = "/tmp"
+	assertNotEqual(t, withDir.Key(), quartz.NewShellJob("ls").Key())
+	assertEqual(t, quartz.NewShellJob("ls").Key(), quartz.NewShellJob("ls").Key())
+
+	// the requests differing by their headers are distinct
+	newCurlJob := func(headers map[string]string) *quartz.CurlJob {
+		request, err := http.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+		job, err := quartz.NewCurlJobWithOptions(request, quartz.CurlJobOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+	first := newCurlJob(map[string]string{"X-Tenant": "a", "Accept": "text/plain"})
+	second := newCurlJob(map[string]string{"X-Tenant": "b", "Accept": "text/plain"})
+	assertEqual(t, first.Description(), second.Description())
+	assertNotEqual(t, first.Key(), second.Key())
+	assertEqual(t, first.Key(), newCurlJob(map[string]string{"Accept": "text/plain", "X-Tenant": "a"}).Key())
+}
+
+func TestSchedulerKeyCollision(t *testing.T) {
+	ctx := context.Background()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	listener := &recordingListener{}
+	sched.AddListener(listener)
+	events := sched.Events()
+
+	if err := sched.ScheduleJob(ctx, quartz.NewShellJob("ls").WithKey(1), quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// the same Job is not a collision
+	err := sched.ScheduleJob(ctx, quartz.NewShellJob("ls").WithKey(1), quartz.NewSimpleTrigger(time.Hour))
+	assertEqual(t, err, quartz.ErrJobAlreadyExists)
+	assertEqual(t, len(listener.snapshot().collisions), 0)
+
+	err = sched.ScheduleJob(ctx, quartz.NewShellJob("pwd").WithKey(1), quartz.NewSimpleTrigger(time.Hour))
+	assertEqual(t, err, quartz.ErrJobAlreadyExists)
+	err = sched.ScheduleJobs(ctx, []quartz.JobWithTrigger{{
+		Job:     quartz.NewShellJob("date").WithKey(1),
+		Trigger: quartz.NewSimpleTrigger(time.Hour),
+		Options: []quartz.ScheduleOption{quartz.WithReplaceExisting()},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := quartz.NewJobKey("1")
+	assertEqual(t, listener.snapshot().collisions, []quartz.JobKey{key, key})
+
+	var collisions int
+	for len(events) > 0 {
+		if event := <-events; event.Type == quartz.EventKeyCollision {
+			assertEqual(t, event.Key, key)
+			collisions++
+		}
+	}
+	assertEqual(t, collisions, 2)
+}
+
+func TestJobWithKey(t *testing.T) {
+	ctx := context.Background()
+	sched := quartz.NewStdScheduler()
+
+	// the jobs running the same command are scheduled independently
+	first := quartz.NewShellJob("ls").WithKey(quartz.UniqueJobKey())
+	second := quartz.NewShellJob("ls").WithKey(quartz.UniqueJobKey())
+	assertEqual(t, first.Description(), second.Description())
+	assertNotEqual(t, first.Key(), second.Key())
+	assertNotEqual(t, first.Key(), quartz.NewShellJob("ls").Key())
+	for _, job := range []quartz.Job{first, second, quartz.NewShellJob("ls")} {
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := sched.ScheduleJob(ctx, quartz.NewShellJob("pwd").WithKey(first.Key()), quartz.NewSimpleTrigger(time.Hour))
+	assertEqual(t, err, quartz.ErrJobAlreadyExists)
+
+	if err := sched.DeleteJob(first.Key()); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(sched.GetJobKeys()), 2)
+	if _, err := sched.GetScheduledJob(second.Key()); err != nil {
+		t.Fatal(err)
+	}
+
+	curlJob, err := quartz.NewCurlJob(http.MethodGet, "http://localhost", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, curlJob.WithKey(7).Key(), 7)
+	assertEqual(t, quartz.NewFunctionJob(func(_ context.Context) (int, error) {
+		return 0, nil
+	}).WithKey(8).Key(), 8)
+	assertEqual(t, quartz.NewFunctionJobWithResult(func(_ context.Context) (int, error) {
+		return 0, nil
+	}).WithKey(9).Key(), 9)
+	assertEqual(t, quartz.NewJobChain().WithKey(10).Key(), 10)
+}
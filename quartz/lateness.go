@@ -0,0 +1,110 @@
+package quartz
+
+import (
+	"context"
+	"time"
+)
+
+// defaultLatenessCheckInterval is the LatenessCheckInterval used when no
+// interval is configured.
+const defaultLatenessCheckInterval = time.Second
+
+// warnLogger is implemented by the Loggers with a warning level, such as
+// the *slog.Logger.
+type warnLogger interface {
+	// Warn logs a message at the warning level.
+	Warn(msg string, args ...any)
+}
+
+// LogLateness returns a LatenessHandler logging the late jobs at the
+// warning level of the logger, or at its error level if the Logger has
+// no warning level. It is the LatenessHandler of the StdScheduler unless
+// configured otherwise.
+func LogLateness(logger Logger) func(job ScheduledJob, lateBy time.Duration) {
+	log := logger.Error
+	if warn, ok := logger.(warnLogger); ok {
+		log = warn.Warn
+	}
+
+	return func(job ScheduledJob, lateBy time.Duration) {
+		log("The Job missed its schedule",
+			"key", job.Key,
+			"description", job.Job.Description(),
+			"scheduled_time", time.Unix(0, job.NextRunTime),
+			"late_by", lateBy,
+		)
+	}
+}
+
+// watchLateness checks the lateness of the next fire every
+// LatenessCheckInterval until the run is stopped. It runs apart from the
+// execution loop, so that the lateness is detected while the loop is
+// blocked, e.g. waiting for a worker.
+func (sched *StdScheduler) watchLateness(ctx context.Context) {
+	defer sched.wg.Done()
+
+	timer := sched.opts.Clock.NewTimer(sched.opts.LatenessCheckInterval)
+	defer timer.Stop()
+
+	var late bool
+	for {
+		select {
+		case <-timer.C():
+			late = sched.checkLateness(late)
+			timer.Reset(sched.opts.LatenessCheckInterval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkLateness invokes the LatenessHandler if the next fire is late by
+// more than the LatenessThreshold, unless the lateness episode was
+// already reported, and reports whether the fire is late. The episode
+// ends once a check finds the next fire on time.
+func (sched *StdScheduler) checkLateness(reported bool) bool {
+	sched.mtx.Lock()
+	job, lateBy, late := sched.lateHead()
+	sched.mtx.Unlock()
+
+	if late && !reported {
+		sched.opts.LatenessHandler(job, lateBy)
+	}
+
+	return late
+}
+
+// lateHead returns a snapshot of the earliest Job which is not dispatched
+// yet, either at the head of the queue or waiting in the batch of the
+// execution loop, along with its lateness, and reports whether it is late
+// by more than the LatenessThreshold. The paused jobs are not considered
+// late. The caller must hold the lock.
+func (sched *StdScheduler) lateHead() (ScheduledJob, time.Duration, bool) {
+	var job *ScheduledJob
+	for _, due := range sched.batch {
+		if !due.job.Paused && (job == nil || due.job.NextRunTime < job.NextRunTime) {
+			job = due.job
+		}
+	}
+	// the failure of the queue is reported by the execution loop
+	if head, err := sched.queue.Head(); err == nil && (job == nil || head.NextRunTime < job.NextRunTime) {
+		queued := &ScheduledJob{Job: head.Job, Key: head.Key, NextRunTime: head.NextRunTime}
+		if it, ok := sched.index[head.Key]; ok {
+			queued = it.scheduledJob()
+			queued.NextRunTime = head.NextRunTime
+		}
+		if !queued.Paused {
+			job = queued
+		}
+	}
+	if job == nil {
+		return ScheduledJob{}, 0, false
+	}
+
+	lateBy := time.Duration(sched.nowNano() - job.NextRunTime)
+	if lateBy <= sched.opts.LatenessThreshold {
+		return ScheduledJob{}, 0, false
+	}
+
+	return *job, lateBy, true
+}
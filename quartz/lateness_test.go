@@ -0,0 +1,104 @@
+package quartz_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type warnLogger struct {
+	recordingLogger
+}
+
+func (l *warnLogger) Warn(msg string, args ...any) { l.record("WARN", msg, args) }
+
+func TestSchedulerLateness(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mtx sync.Mutex
+	var lateness []time.Duration
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		BlockingExecution:     true,
+		Clock:                 clock,
+		Logger:                quartz.NewNoopLogger(),
+		LatenessThreshold:     30 * time.Second,
+		LatenessCheckInterval: 10 * time.Second,
+		LatenessHandler: func(job quartz.ScheduledJob, lateBy time.Duration) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			assertEqual(t, job.Job.Description(), "lagging")
+			lateness = append(lateness, lateBy)
+		},
+	})
+
+	// the blocking execution of the blocker delays the lagging Job
+	release := make(chan struct{})
+	blocker := quartz.NewFunctionJobWithDesc("blocker", func(context.Context) (bool, error) {
+		<-release
+		return true, nil
+	})
+	lagging := quartz.NewFunctionJobWithDesc("lagging", func(context.Context) (bool, error) {
+		return true, nil
+	})
+	for _, job := range []quartz.Job{blocker, lagging} {
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	advance := func(d time.Duration) {
+		time.Sleep(10 * time.Millisecond)
+		clock.Advance(d)
+		time.Sleep(10 * time.Millisecond)
+	}
+	reported := func() []time.Duration {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]time.Duration{}, lateness...)
+	}
+
+	// the lateness episode is reported once the threshold is crossed
+	for i := 0; i < 10; i++ {
+		advance(10 * time.Second)
+	}
+	assertEqual(t, reported(), []time.Duration{40 * time.Second})
+	advance(10 * time.Second)
+	assertEqual(t, reported(), []time.Duration{40 * time.Second})
+
+	// the episode ends once the lagging Job is on time, and a new
+	// one is reported
+	release <- struct{}{}
+	for i := 0; i < 5; i++ {
+		advance(10 * time.Second)
+	}
+	assertEqual(t, reported(), []time.Duration{40 * time.Second, 40 * time.Second})
+	close(release)
+}
+
+func TestLogLateness(t *testing.T) {
+	job := quartz.ScheduledJob{
+		Job: quartz.NewFunctionJobWithDesc("late", func(context.Context) (bool, error) {
+			return true, nil
+		}),
+		Key: quartz.NewJobKey("late"),
+	}
+
+	// the lateness is logged at the warning level, if any
+	logger := &warnLogger{}
+	quartz.LogLateness(logger)(job, time.Minute)
+	assertEqual(t, logger.contains("WARN The Job missed its schedule"), true)
+
+	errorLogger := &recordingLogger{}
+	quartz.LogLateness(errorLogger)(job, time.Minute)
+	assertEqual(t, errorLogger.contains("ERROR The Job missed its schedule"), true)
+}
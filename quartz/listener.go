@@ -0,0 +1,125 @@
+package quartz
+
+import "time"
+
+// SchedulerListener receives notifications about the lifecycle of the
+// jobs managed by a StdScheduler. The callbacks are invoked outside of
+// the scheduler lock, but synchronously with the scheduling activity
+// which triggers them, so implementations should return promptly.
+type SchedulerListener interface {
+	// JobScheduled is called when a Job is added to the scheduler.
+	JobScheduled(job ScheduledJob)
+
+	// JobDeleted is called when a Job is removed from the scheduler.
+	JobDeleted(key JobKey)
+
+	// BeforeJobExecution is called before the Job is executed.
+	BeforeJobExecution(job ScheduledJob)
+
+	// AfterJobExecution is called after the Job has returned, with
+	// the duration of the execution and the returned error.
+	AfterJobExecution(job ScheduledJob, duration time.Duration, err error)
+
+	// JobSkippedOutdated is called when a fire of the Job is skipped
+	// because its scheduled time is outdated, with the lateness of
+	// the fire.
+	JobSkippedOutdated(job ScheduledJob, lateness time.Duration)
+
+	// JobTimedOut is called when an execution of the Job exceeds
+	// its timeout and the execution context is canceled.
+	JobTimedOut(job ScheduledJob)
+
+	// JobSkippedConcurrent is called when a fire of the Job is
+	// skipped because its previous execution is still running.
+	JobSkippedConcurrent(job ScheduledJob)
+
+	// JobDropped is called when a fire of the Job is dropped
+	// because the dispatch queue of the worker pool is full.
+	JobDropped(job ScheduledJob)
+
+	// JobSkippedLocked is called when a fire of the Job is skipped
+	// because its lock is held by another instance.
+	JobSkippedLocked(job ScheduledJob)
+
+	// JobLockLost is called when the lock of an executing Job is
+	// lost and the execution context is canceled.
+	JobLockLost(job ScheduledJob)
+
+	// JobUnscheduled is called when the Job leaves the scheduler
+	// because its Trigger returned the error, ErrTriggerExpired
+	// once it can never fire again.
+	JobUnscheduled(job ScheduledJob, err error)
+
+	// JobKeyCollision is called when a Job is scheduled with the key
+	// of a scheduled Job with a different description, before it is
+	// rejected or replaces the scheduled Job.
+	JobKeyCollision(scheduled ScheduledJob, job Job)
+}
+
+// NoopListener implements the SchedulerListener interface, ignoring
+// all of the notifications. It can be embedded by listeners which are
+// only interested in a subset of the callbacks.
+type NoopListener struct{}
+
+// Verify NoopListener satisfies the SchedulerListener interface.
+var _ SchedulerListener = NoopListener{}
+
+// JobScheduled ignores the notification.
+func (NoopListener) JobScheduled(ScheduledJob) {}
+
+// JobDeleted ignores the notification.
+func (NoopListener) JobDeleted(JobKey) {}
+
+// BeforeJobExecution ignores the notification.
+func (NoopListener) BeforeJobExecution(ScheduledJob) {}
+
+// AfterJobExecution ignores the notification.
+func (NoopListener) AfterJobExecution(ScheduledJob, time.Duration, error) {}
+
+// JobSkippedOutdated ignores the notification.
+func (NoopListener) JobSkippedOutdated(ScheduledJob, time.Duration) {}
+
+// JobTimedOut ignores the notification.
+func (NoopListener) JobTimedOut(ScheduledJob) {}
+
+// JobSkippedConcurrent ignores the notification.
+func (NoopListener) JobSkippedConcurrent(ScheduledJob) {}
+
+// JobDropped ignores the notification.
+func (NoopListener) JobDropped(ScheduledJob) {}
+
+// JobSkippedLocked ignores the notification.
+func (NoopListener) JobSkippedLocked(ScheduledJob) {}
+
+// JobLockLost ignores the notification.
+func (NoopListener) JobLockLost(ScheduledJob) {}
+
+// JobUnscheduled ignores the notification.
+func (NoopListener) JobUnscheduled(ScheduledJob, error) {}
+
+// JobKeyCollision ignores the notification.
+func (NoopListener) JobKeyCollision(ScheduledJob, Job) {}
+
+// AddListener registers the SchedulerListener to be notified about
+// the scheduler events.
+func (sched *StdScheduler) AddListener(listener SchedulerListener) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	sched.listeners = append(sched.listeners, listener)
+}
+
+// notify invokes the callback for each of the registered listeners.
+// The caller must not hold the lock.
+func (sched *StdScheduler) notify(callback func(SchedulerListener)) {
+	sched.mtx.Lock()
+	listeners := sched.listeners
+	if sched.events != nil {
+		listeners = append(listeners[:len(listeners):len(listeners)], sched.events)
+	}
+	sched.mtx.Unlock()
+
+	for _, listener := range listeners {
+		callback(listener)
+	}
+}
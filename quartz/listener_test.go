@@ -0,0 +1,176 @@
+package quartz_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type recordingListener struct {
+	quartz.NoopListener
+	mtx               sync.Mutex
+	scheduled         []quartz.JobKey
+	deleted           []quartz.JobKey
+	before            []quartz.JobKey
+	after             []time.Duration
+	skipped           []quartz.JobKey
+	lateness          []time.Duration
+	timedOut          []quartz.JobKey
+	skippedConcurrent []quartz.JobKey
+	dropped           []quartz.JobKey
+	skippedLocked     []quartz.JobKey
+	lockLost          []quartz.JobKey
+	unscheduled       []error
+	collisions        []quartz.JobKey
+}
+
+func (l *recordingListener) JobScheduled(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.scheduled = append(l.scheduled, job.Key)
+}
+
+func (l *recordingListener) JobDeleted(key quartz.JobKey) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.deleted = append(l.deleted, key)
+}
+
+func (l *recordingListener) BeforeJobExecution(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.before = append(l.before, job.Key)
+}
+
+func (l *recordingListener) AfterJobExecution(_ quartz.ScheduledJob, duration time.Duration, _ error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.after = append(l.after, duration)
+}
+
+func (l *recordingListener) JobSkippedOutdated(job quartz.ScheduledJob, lateness time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.skipped = append(l.skipped, job.Key)
+	l.lateness = append(l.lateness, lateness)
+}
+
+func (l *recordingListener) JobTimedOut(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.timedOut = append(l.timedOut, job.Key)
+}
+
+func (l *recordingListener) JobSkippedConcurrent(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.skippedConcurrent = append(l.skippedConcurrent, job.Key)
+}
+
+func (l *recordingListener) JobDropped(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.dropped = append(l.dropped, job.Key)
+}
+
+func (l *recordingListener) JobSkippedLocked(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.skippedLocked = append(l.skippedLocked, job.Key)
+}
+
+func (l *recordingListener) JobLockLost(job quartz.ScheduledJob) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.lockLost = append(l.lockLost, job.Key)
+}
+
+func (l *recordingListener) JobUnscheduled(_ quartz.ScheduledJob, err error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.unscheduled = append(l.unscheduled, err)
+}
+
+func (l *recordingListener) JobKeyCollision(job quartz.ScheduledJob, _ quartz.Job) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.collisions = append(l.collisions, job.Key)
+}
+
+func (l *recordingListener) snapshot() recordingListener {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return recordingListener{
+		scheduled:         append([]quartz.JobKey(nil), l.scheduled...),
+		deleted:           append([]quartz.JobKey(nil), l.deleted...),
+		before:            append([]quartz.JobKey(nil), l.before...),
+		after:             append([]time.Duration(nil), l.after...),
+		skipped:           append([]quartz.JobKey(nil), l.skipped...),
+		lateness:          append([]time.Duration(nil), l.lateness...),
+		timedOut:          append([]quartz.JobKey(nil), l.timedOut...),
+		skippedConcurrent: append([]quartz.JobKey(nil), l.skippedConcurrent...),
+		dropped:           append([]quartz.JobKey(nil), l.dropped...),
+		skippedLocked:     append([]quartz.JobKey(nil), l.skippedLocked...),
+		lockLost:          append([]quartz.JobKey(nil), l.lockLost...),
+		unscheduled:       append([]error(nil), l.unscheduled...),
+		collisions:        append([]quartz.JobKey(nil), l.collisions...),
+	}
+}
+
+func TestSchedulerListener(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true, OutdatedThreshold: quartz.DefaultOutdatedThreshold},
+		{WorkerLimit: 2, OutdatedThreshold: quartz.DefaultOutdatedThreshold},
+		{OutdatedThreshold: quartz.DefaultOutdatedThreshold},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		listener := &recordingListener{}
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.AddListener(listener)
+		sched.Start(ctx)
+
+		runKey := quartz.NewJobKey("run")
+		job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+			time.Sleep(10 * time.Millisecond)
+			return true, nil
+		})
+		if err := sched.ScheduleJobWithKey(ctx, runKey, job,
+			quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+
+		outdatedKey := quartz.NewJobKey("outdated")
+		if err := sched.ScheduleJobWithKey(ctx, outdatedKey, quartz.NewShellJob("ls"),
+			quartz.NewRunOnceTrigger(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		deletedKey := quartz.NewJobKey("42")
+		if err := sched.ScheduleJobWithKey(ctx, deletedKey, quartz.NewShellJob("ls"),
+			quartz.NewSimpleTrigger(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if err := sched.DeleteJob(42); err != nil {
+			t.Fatal(err)
+		}
+
+		sched.Stop()
+		sched.Wait(ctx)
+
+		events := listener.snapshot()
+		assertEqual(t, events.scheduled, []quartz.JobKey{runKey, outdatedKey, deletedKey})
+		assertEqual(t, events.deleted, []quartz.JobKey{deletedKey})
+		assertEqual(t, events.before, []quartz.JobKey{runKey})
+		assertEqual(t, len(events.after), 1)
+		if events.after[0] < 10*time.Millisecond {
+			t.Error("unexpected execution duration", events.after[0])
+		}
+		assertEqual(t, events.skipped, []quartz.JobKey{outdatedKey})
+	}
+}
@@ -0,0 +1,235 @@
+package quartz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultLockTTL is the TTL of the locks acquired from the LockProvider,
+// used when no TTL is configured.
+const defaultLockTTL = time.Minute
+
+// LockProvider provides the distributed locks, which prevent the
+// StdSchedulers of multiple instances from executing the same fire of a
+// shared Job.
+type LockProvider interface {
+	// Acquire attempts to acquire the lock with the key for the ttl,
+	// after which the lock expires unless renewed. It returns false if
+	// the lock is held, including by the same instance. The release
+	// function releases the acquired lock, it has no effect once the
+	// lock has expired.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// LockRenewer is implemented by the LockProviders which can extend the TTL
+// of the locks they acquired.
+type LockRenewer interface {
+	// Renew extends the lock with the key, acquired using the same
+	// LockProvider, for the ttl. It returns false if the lock is no
+	// longer held, e.g. it expired and was acquired by another instance.
+	Renew(ctx context.Context, key string, ttl time.Duration) (ok bool, err error)
+}
+
+// MemoryLocks holds the locks of the MemoryLockProviders created from it,
+// which coordinate the StdSchedulers of a single process, e.g. in tests.
+type MemoryLocks struct {
+	mtx   sync.Mutex
+	clock Clock
+	locks map[string]*memoryLock
+}
+
+// memoryLock is a lock held by a MemoryLockProvider.
+type memoryLock struct {
+	owner   *MemoryLockProvider
+	expires time.Time
+}
+
+// NewMemoryLocks returns a new MemoryLocks, whose locks expire according
+// to the Clock. When nil, the system time is used.
+func NewMemoryLocks(clock Clock) *MemoryLocks {
+	if clock == nil {
+		clock = NewRealClock()
+	}
+
+	return &MemoryLocks{
+		clock: clock,
+		locks: make(map[string]*memoryLock),
+	}
+}
+
+// NewProvider returns a new MemoryLockProvider, representing an instance
+// sharing the locks.
+func (m *MemoryLocks) NewProvider() *MemoryLockProvider {
+	return &MemoryLockProvider{locks: m}
+}
+
+// MemoryLockProvider implements the LockProvider and the LockRenewer
+// interfaces using the MemoryLocks it was created from.
+type MemoryLockProvider struct {
+	locks *MemoryLocks
+}
+
+// Verify MemoryLockProvider satisfies the LockProvider and the LockRenewer
+// interfaces.
+var (
+	_ LockProvider = (*MemoryLockProvider)(nil)
+	_ LockRenewer  = (*MemoryLockProvider)(nil)
+)
+
+// Acquire acquires the lock with the key for the ttl, unless the lock is
+// held and has not expired.
+func (p *MemoryLockProvider) Acquire(_ context.Context, key string,
+	ttl time.Duration) (func(), bool, error) {
+	m := p.locks
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.clock.Now()
+	if held, ok := m.locks[key]; ok && held.expires.After(now) {
+		return nil, false, nil
+	}
+
+	lock := &memoryLock{owner: p, expires: now.Add(ttl)}
+	m.locks[key] = lock
+	release := func() {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+
+		if m.locks[key] == lock {
+			delete(m.locks, key)
+		}
+	}
+
+	return release, true, nil
+}
+
+// Renew extends the lock with the key for the ttl, if the lock is held by
+// the MemoryLockProvider and has not expired.
+func (p *MemoryLockProvider) Renew(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m := p.locks
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.clock.Now()
+	held, ok := m.locks[key]
+	if !ok || held.owner != p || !held.expires.After(now) {
+		return false, nil
+	}
+	held.expires = now.Add(ttl)
+
+	return true, nil
+}
+
+// lock acquires the lock of the fire from the LockProvider, named after
+// the key of the Job. It returns false if the fire is skipped, because
+// the lock is held by another instance or could not be acquired. While
+// the Job is executing, the lock is kept by keepLock. The returned
+// function releases the lock once the execution returns.
+func (sched *StdScheduler) lock(f *fire) (func(), bool) {
+	key := f.job.Key.String()
+	release, ok, err := sched.opts.LockProvider.Acquire(f.ctx, key, sched.opts.LockTTL)
+	if err != nil {
+		sched.opts.Logger.Error("Skipping the Job fire, failed to acquire the lock",
+			"key", f.job.Key,
+			"error", err,
+		)
+		sched.recordSkipped(f.item, f.job, "failed to acquire the lock")
+		return nil, false
+	}
+	if !ok {
+		sched.opts.Logger.Info("Skipping the Job fire, the lock is held by another instance",
+			"key", f.job.Key,
+			"description", f.job.Job.Description(),
+		)
+		sched.recordSkipped(f.item, f.job, "the lock is held by another instance")
+		sched.notify(func(l SchedulerListener) { l.JobSkippedLocked(*f.job) })
+		return nil, false
+	}
+
+	done := make(chan struct{})
+	sched.wg.Add(1)
+	go func() {
+		defer sched.wg.Done()
+		sched.keepLock(f, key, done)
+	}()
+
+	return func() {
+		close(done)
+		sched.releaseLock(f, release)
+	}, true
+}
+
+// keepLock renews the lock of the executing fire every half of the TTL,
+// if the LockProvider is a LockRenewer, until the done channel is closed.
+// Once the lock is lost, because it could not be renewed before it
+// expired, the context of the execution is canceled.
+func (sched *StdScheduler) keepLock(f *fire, key string, done <-chan struct{}) {
+	ttl := sched.opts.LockTTL
+	renewer, renewable := sched.opts.LockProvider.(LockRenewer)
+	expires := sched.opts.Clock.Now().Add(ttl)
+	for {
+		wait := expires.Sub(sched.opts.Clock.Now())
+		if renewable {
+			wait = ttl / 2
+		}
+
+		timer := sched.opts.Clock.NewTimer(wait)
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		now := sched.opts.Clock.Now()
+		if renewable && now.Before(expires) {
+			ok, err := renewer.Renew(f.ctx, key, ttl)
+			if err == nil && ok {
+				expires = now.Add(ttl)
+				continue
+			}
+			if err != nil {
+				sched.opts.Logger.Error("Failed to renew the Job lock",
+					"key", f.job.Key,
+					"error", err,
+				)
+				if now.Add(ttl / 2).Before(expires) {
+					continue
+				}
+			}
+		}
+
+		sched.opts.Logger.Error("The Job lock was lost, canceling the execution",
+			"key", f.job.Key,
+			"description", f.job.Job.Description(),
+		)
+		f.cancel()
+		sched.notify(func(l SchedulerListener) { l.JobLockLost(*f.job) })
+		return
+	}
+}
+
+// releaseLock releases the lock of the fire. Unless the outdated check is
+// disabled, the lock is held until the fire becomes outdated, so that the
+// instances whose clocks lag behind do not execute the same fire once the
+// lock is released.
+func (sched *StdScheduler) releaseLock(f *fire, release func()) {
+	if sched.opts.OutdatedThreshold <= 0 {
+		release()
+		return
+	}
+
+	outdated := time.Unix(0, f.job.NextRunTime).Add(sched.opts.OutdatedThreshold)
+	wait := outdated.Sub(sched.opts.Clock.Now())
+	if wait <= 0 {
+		release()
+		return
+	}
+
+	timer := sched.opts.Clock.NewTimer(wait)
+	go func() {
+		<-timer.C()
+		release()
+	}()
+}
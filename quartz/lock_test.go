@@ -0,0 +1,153 @@
+package quartz_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestMemoryLocks(t *testing.T) {
+	ctx := context.Background()
+	clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	locks := quartz.NewMemoryLocks(clock)
+	first, second := locks.NewProvider(), locks.NewProvider()
+
+	release, ok, err := first.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, err, nil)
+	assertEqual(t, ok, true)
+	_, ok, _ = first.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+	_, ok, _ = second.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+	ok, _ = second.Renew(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+
+	clock.Advance(50 * time.Second)
+	ok, _ = first.Renew(ctx, "job", time.Minute)
+	assertEqual(t, ok, true)
+	clock.Advance(50 * time.Second)
+	_, ok, _ = second.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+
+	// the expired lock is acquired by another instance
+	clock.Advance(time.Minute)
+	ok, _ = first.Renew(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+	releaseSecond, ok, _ := second.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, true)
+	release()
+	_, ok, _ = first.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, false)
+	releaseSecond()
+	_, ok, _ = first.Acquire(ctx, "job", time.Minute)
+	assertEqual(t, ok, true)
+}
+
+func TestSchedulerLockProvider(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	locks := quartz.NewMemoryLocks(clock)
+	listener := &recordingListener{}
+	var executed int32
+	key := quartz.NewJobKey("shared")
+	for i := 0; i < 3; i++ {
+		sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+			LockProvider:      locks.NewProvider(),
+			OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+			Clock:             clock,
+			Logger:            quartz.NewNoopLogger(),
+		})
+		sched.AddListener(listener)
+		sched.Start(ctx)
+		defer sched.Stop()
+
+		job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+			atomic.AddInt32(&executed, 1)
+			return true, nil
+		})
+		if err := sched.ScheduleJobWithKey(ctx, key, job,
+			quartz.NewSimpleTrigger(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the lock of the fire is held until the fire becomes outdated
+	clock.Advance(time.Minute)
+	for {
+		snapshot := listener.snapshot()
+		if len(snapshot.after)+len(snapshot.skippedLocked) == 3 {
+			assertEqual(t, snapshot.skippedLocked, []quartz.JobKey{key, key})
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assertEqual(t, atomic.LoadInt32(&executed), int32(1))
+}
+
+func TestSchedulerLockLost(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		provider quartz.LockProvider
+		lost     bool
+	}{
+		{"renewed", quartz.NewMemoryLocks(nil).NewProvider(), false},
+		{"expired", struct{ quartz.LockProvider }{quartz.NewMemoryLocks(nil).NewProvider()}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				LockProvider: tt.provider,
+				LockTTL:      time.Minute,
+				Clock:        clock,
+				Logger:       quartz.NewNoopLogger(),
+			})
+			sched.AddListener(listener)
+			sched.Start(ctx)
+			defer sched.Stop()
+
+			started, stop := make(chan struct{}), make(chan struct{})
+			var canceled int32
+			job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+				close(started)
+				select {
+				case <-ctx.Done():
+					atomic.StoreInt32(&canceled, 1)
+					return false, ctx.Err()
+				case <-stop:
+					return true, nil
+				}
+			})
+			key := quartz.NewJobKey("long")
+			if err := sched.ScheduleJobWithKey(ctx, key, job, quartz.NewRunOnceTrigger(time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(time.Second)
+			<-started
+
+			// the job executes for three TTLs of the lock
+			for i := 0; i < 24; i++ {
+				clock.Advance(time.Minute / 8)
+				time.Sleep(5 * time.Millisecond)
+			}
+			close(stop)
+			for len(listener.snapshot().after) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			assertEqual(t, atomic.LoadInt32(&canceled) == 1, tt.lost)
+			if tt.lost {
+				assertEqual(t, listener.snapshot().lockLost, []quartz.JobKey{key})
+			} else {
+				assertEqual(t, len(listener.snapshot().lockLost), 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package quartz
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger is used by the StdScheduler to report its internal events.
+// The arguments following the message are alternating keys and values,
+// which makes the method set compatible with the *slog.Logger type.
+type Logger interface {
+	// Debug logs a message at the debug level.
+	Debug(msg string, args ...any)
+
+	// Info logs a message at the info level.
+	Info(msg string, args ...any)
+
+	// Error logs a message at the error level.
+	Error(msg string, args ...any)
+}
+
+// stdLogger implements the Logger interface using a standard library
+// log.Logger.
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger returns a new Logger writing all of the messages to the
+// given log.Logger, formatting the arguments as key=value pairs.
+// The default logger of the log package is used if logger is nil.
+func NewStdLogger(logger *log.Logger) Logger {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &stdLogger{logger: logger}
+}
+
+// Debug logs a message at the debug level.
+func (l *stdLogger) Debug(msg string, args ...any) { l.print(msg, args) }
+
+// Info logs a message at the info level.
+func (l *stdLogger) Info(msg string, args ...any) { l.print(msg, args) }
+
+// Error logs a message at the error level.
+func (l *stdLogger) Error(msg string, args ...any) { l.print(msg, args) }
+
+func (l *stdLogger) print(msg string, args []any) {
+	var sb strings.Builder
+	sb.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&sb, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&sb, " %v=%v", args[i], args[i+1])
+	}
+
+	l.logger.Print(sb.String())
+}
+
+// noopLogger implements the Logger interface, discarding all of the
+// messages.
+type noopLogger struct{}
+
+// NewNoopLogger returns a new Logger which discards all of the messages.
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+// Debug discards the message.
+func (noopLogger) Debug(string, ...any) {}
+
+// Info discards the message.
+func (noopLogger) Info(string, ...any) {}
+
+// Error discards the message.
+func (noopLogger) Error(string, ...any) {}
@@ -0,0 +1,81 @@
+package quartz_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type recordingLogger struct {
+	mtx      sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(level, msg string, args []any) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf("%s %s %v", level, msg, args))
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) { l.record("DEBUG", msg, args) }
+func (l *recordingLogger) Info(msg string, args ...any)  { l.record("INFO", msg, args) }
+func (l *recordingLogger) Error(msg string, args ...any) { l.record("ERROR", msg, args) }
+
+func (l *recordingLogger) contains(substr string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for _, msg := range l.messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := quartz.NewStdLogger(log.New(&buf, "", 0))
+
+	logger.Info("message", "key", 42, "name", "value")
+	logger.Error("odd", "key")
+	assertEqual(t, buf.String(), "message key=42 name=value\nodd !BADKEY=key\n")
+
+	// the noop logger discards everything
+	quartz.NewNoopLogger().Error("discarded", "key", 42)
+}
+
+func TestSchedulerLogger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	logger := &recordingLogger{}
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{Logger: logger})
+	sched.Start(ctx)
+
+	job := quartz.NewShellJob("ls")
+	if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	sched.Stop()
+	sched.Wait(ctx)
+
+	if !logger.contains("INFO The Job trigger is expired [key " + quartz.NewJobKey(
+		fmt.Sprint(job.Key())).String()) {
+		t.Error("the expired job should be logged with its key", logger.messages)
+	}
+	if !logger.contains("INFO Closing the StdScheduler") {
+		t.Error("closing the scheduler should be logged", logger.messages)
+	}
+	if !logger.contains("DEBUG Exit the") {
+		t.Error("exiting the loops should be logged", logger.messages)
+	}
+}
@@ -0,0 +1,205 @@
+package quartz
+
+import (
+	"expvar"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The names of the metrics reported by the StdScheduler.
+const (
+	// MetricJobsScheduled counts the jobs scheduled using the
+	// StdScheduler.
+	MetricJobsScheduled = "jobs_scheduled_total"
+
+	// MetricJobsExecuted counts the executions of the jobs,
+	// including the failed ones.
+	MetricJobsExecuted = "jobs_executed_total"
+
+	// MetricJobsFailed counts the executions of the jobs which
+	// returned an error.
+	MetricJobsFailed = "jobs_failed_total"
+
+	// MetricJobsSkippedOutdated counts the fires skipped because
+	// they are outdated.
+	MetricJobsSkippedOutdated = "jobs_skipped_outdated_total"
+
+	// MetricJobsUnscheduled counts the jobs which got out of the
+	// execution loop because their Trigger returned an error,
+	// including the expired ones.
+	MetricJobsUnscheduled = "jobs_unscheduled_total"
+
+	// MetricQueueLength is the gauge of the number of jobs in the
+	// JobQueue.
+	MetricQueueLength = "queue_length"
+
+	// MetricExecutionDuration is the histogram of the durations of
+	// the job executions.
+	MetricExecutionDuration = "execution_duration"
+
+	// MetricSchedulingDelay is the histogram of the delays between
+	// the scheduled time of the fires and the start of their
+	// executions.
+	MetricSchedulingDelay = "scheduling_delay"
+)
+
+// Metrics receives the metrics of the StdScheduler internals, e.g. to
+// export them to Prometheus. The metrics are identified by the Metric
+// names, the implementations are expected to ignore unknown names.
+type Metrics interface {
+	// IncCounter increments the counter with the name.
+	IncCounter(name string)
+
+	// SetGauge sets the value of the gauge with the name.
+	SetGauge(name string, value float64)
+
+	// ObserveDuration adds the duration to the histogram with the
+	// name.
+	ObserveDuration(name string, value time.Duration)
+}
+
+// noopMetrics implements the Metrics interface, discarding all of the
+// metrics.
+type noopMetrics struct{}
+
+// NewNoopMetrics returns a new Metrics which discards all of the metrics.
+func NewNoopMetrics() Metrics {
+	return noopMetrics{}
+}
+
+// IncCounter discards the metric.
+func (noopMetrics) IncCounter(string) {}
+
+// SetGauge discards the metric.
+func (noopMetrics) SetGauge(string, float64) {}
+
+// ObserveDuration discards the metric.
+func (noopMetrics) ObserveDuration(string, time.Duration) {}
+
+// histogramBuckets are the upper bounds, in seconds, of the buckets of
+// the ExpvarMetrics histograms, matching the default buckets of the
+// Prometheus client.
+var histogramBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ExpvarMetrics implements the Metrics interface using the expvar
+// package. It is an expvar.Var itself, which is exported once published,
+// e.g. using expvar.Publish("quartz", metrics). The counters are exported
+// as integers and the gauges as floats, while the histograms are exported
+// as objects with the count and the sum of the observed seconds, and the
+// cumulative counts of the buckets.
+type ExpvarMetrics struct {
+	mtx  sync.Mutex
+	vars *expvar.Map
+}
+
+// Verify ExpvarMetrics satisfies the Metrics and the expvar.Var interfaces.
+var (
+	_ Metrics    = (*ExpvarMetrics)(nil)
+	_ expvar.Var = (*ExpvarMetrics)(nil)
+)
+
+// NewExpvarMetrics returns a new ExpvarMetrics, which is not published.
+func NewExpvarMetrics() *ExpvarMetrics {
+	return &ExpvarMetrics{vars: new(expvar.Map).Init()}
+}
+
+// IncCounter increments the counter with the name.
+func (m *ExpvarMetrics) IncCounter(name string) {
+	m.vars.Add(name, 1)
+}
+
+// SetGauge sets the value of the gauge with the name.
+func (m *ExpvarMetrics) SetGauge(name string, value float64) {
+	m.get(name, func() expvar.Var { return new(expvar.Float) }).(*expvar.Float).Set(value)
+}
+
+// ObserveDuration adds the duration to the histogram with the name.
+func (m *ExpvarMetrics) ObserveDuration(name string, value time.Duration) {
+	m.get(name, func() expvar.Var {
+		return &expvarHistogram{counts: make([]uint64, len(histogramBuckets))}
+	}).(*expvarHistogram).observe(value.Seconds())
+}
+
+// Get returns the variable of the metric with the name, nil if the metric
+// was not reported.
+func (m *ExpvarMetrics) Get(name string) expvar.Var {
+	return m.vars.Get(name)
+}
+
+// String returns the JSON representation of the metrics.
+func (m *ExpvarMetrics) String() string {
+	return m.vars.String()
+}
+
+// get returns the variable of the metric with the name, creating it if
+// the metric was not reported yet.
+func (m *ExpvarMetrics) get(name string, create func() expvar.Var) expvar.Var {
+	if v := m.vars.Get(name); v != nil {
+		return v
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	v := m.vars.Get(name)
+	if v == nil {
+		v = create()
+		m.vars.Set(name, v)
+	}
+
+	return v
+}
+
+// expvarHistogram is the expvar.Var of a histogram of the ExpvarMetrics.
+type expvarHistogram struct {
+	mtx    sync.Mutex
+	count  uint64
+	sum    float64
+	counts []uint64
+}
+
+func (h *expvarHistogram) observe(value float64) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.count++
+	h.sum += value
+	for i, bound := range histogramBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+}
+
+// String returns the JSON representation of the histogram.
+func (h *expvarHistogram) String() string {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	var sb strings.Builder
+	sb.WriteString(`{"count":`)
+	sb.WriteString(strconv.FormatUint(h.count, 10))
+	sb.WriteString(`,"sum":`)
+	sb.WriteString(strconv.FormatFloat(h.sum, 'g', -1, 64))
+	sb.WriteString(`,"buckets":{`)
+	for i, bound := range histogramBuckets {
+		sb.WriteString(`"`)
+		sb.WriteString(strconv.FormatFloat(bound, 'g', -1, 64))
+		sb.WriteString(`":`)
+		sb.WriteString(strconv.FormatUint(h.counts[i], 10))
+		sb.WriteString(`,`)
+	}
+	sb.WriteString(`"+Inf":`)
+	sb.WriteString(strconv.FormatUint(h.count, 10))
+	sb.WriteString(`}}`)
+
+	return sb.String()
+}
+
+// reportQueueLength sets the MetricQueueLength gauge to the length of
+// the JobQueue. The caller must hold the lock.
+func (sched *StdScheduler) reportQueueLength() {
+	sched.opts.Metrics.SetGauge(MetricQueueLength, float64(sched.queue.Len()))
+}
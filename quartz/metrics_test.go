@@ -0,0 +1,98 @@
+package quartz_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := quartz.NewExpvarMetrics()
+	assertEqual(t, metrics.Get(quartz.MetricJobsExecuted), nil)
+	metrics.IncCounter(quartz.MetricJobsExecuted)
+	metrics.IncCounter(quartz.MetricJobsExecuted)
+	metrics.SetGauge(quartz.MetricQueueLength, 3)
+	metrics.ObserveDuration(quartz.MetricExecutionDuration, 20*time.Millisecond)
+	metrics.ObserveDuration(quartz.MetricExecutionDuration, time.Minute)
+
+	assertEqual(t, metrics.Get(quartz.MetricJobsExecuted).(*expvar.Int).Value(), int64(2))
+	assertEqual(t, metrics.Get(quartz.MetricQueueLength).(*expvar.Float).Value(), 3.0)
+
+	var histogram struct {
+		Count   int            `json:"count"`
+		Sum     float64        `json:"sum"`
+		Buckets map[string]int `json:"buckets"`
+	}
+	if err := json.Unmarshal([]byte(metrics.Get(quartz.MetricExecutionDuration).String()),
+		&histogram); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, histogram.Count, 2)
+	assertEqual(t, histogram.Sum, 60.02)
+	assertEqual(t, histogram.Buckets["0.01"], 0)
+	assertEqual(t, histogram.Buckets["0.025"], 1)
+	assertEqual(t, histogram.Buckets["10"], 1)
+	assertEqual(t, histogram.Buckets["+Inf"], 2)
+	assertEqual(t, json.Valid([]byte(metrics.String())), true)
+}
+
+func TestSchedulerMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	metrics := quartz.NewExpvarMetrics()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Metrics:           metrics,
+		OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	for _, job := range []quartz.Job{
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) { return true, nil }),
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) { return false, errors.New("failed") }),
+	} {
+		if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sched.ScheduleJob(ctx, quartz.NewShellJob("ls"),
+		quartz.NewRunOnceTrigger(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJob(ctx, quartz.NewShellJob("ls -la"),
+		quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Second)
+	counter := func(name string) int64 {
+		if v, ok := metrics.Get(name).(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	for counter(quartz.MetricJobsExecuted) < 2 || counter(quartz.MetricJobsSkippedOutdated) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	for len(sched.GetScheduledJobs()) > 1 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	assertEqual(t, counter(quartz.MetricJobsScheduled), int64(4))
+	assertEqual(t, counter(quartz.MetricJobsExecuted), int64(2))
+	assertEqual(t, counter(quartz.MetricJobsFailed), int64(1))
+	assertEqual(t, counter(quartz.MetricJobsSkippedOutdated), int64(1))
+	assertEqual(t, metrics.Get(quartz.MetricQueueLength).(*expvar.Float).Value(), 1.0)
+	assertNotEqual(t, metrics.Get(quartz.MetricExecutionDuration), nil)
+	assertNotEqual(t, metrics.Get(quartz.MetricSchedulingDelay), nil)
+}
@@ -0,0 +1,24 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package quartz
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on the platforms without process groups.
+func setProcessGroup(*exec.Cmd) {}
+
+// terminateProcessGroup interrupts the started command, killing it on the
+// platforms which do not support the interrupt signal.
+func terminateProcessGroup(cmd *exec.Cmd) {
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// killProcessGroup kills the started command.
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
@@ -1,59 +1,452 @@
 package quartz
 
-import "container/heap"
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-// item is the priorityQueue item.
+// ErrQueueEmpty is returned by the JobQueue operations which require a
+// queued job when the queue is empty.
+var ErrQueueEmpty = errors.New("the job queue is empty")
+
+// QueuedJob is an entry of a JobQueue, a Job scheduled to run at the
+// next run time of its Trigger.
+type QueuedJob struct {
+	// Key is the unique key of the queued Job.
+	Key JobKey
+
+	// Job is the queued Job.
+	Job Job
+
+	// Trigger is the Trigger of the queued Job.
+	Trigger Trigger
+
+	// NextRunTime is the next run time of the Job, set as Unix time
+	// in nanoseconds. The JobQueue is ordered by the NextRunTime.
+	NextRunTime int64
+
+	// Priority breaks the ties among the jobs with the same NextRunTime,
+	// the jobs with a higher Priority being first. It is set using
+	// WithPriority.
+	Priority int
+
+	// Data is the data attached to the Job using WithData, kept by a
+	// persistent JobQueue along with the Job.
+	Data map[string]any
+
+	// StartTime and ExpireTime are the bounds of the fire times of the
+	// Job, set as Unix time in nanoseconds using WithStartTime and
+	// WithExpireTime, zero if none. A persistent JobQueue keeps them
+	// along with the Job.
+	StartTime  int64
+	ExpireTime int64
+}
+
+// JobQueue represents the queue of the jobs scheduled by a StdScheduler,
+// ordered by their next run time. The default JobQueue is an in-memory
+// heap, custom implementations can keep the jobs in a persistent store.
+// The jobs with the same next run time should be ordered by their
+// Priority, and in the order they were pushed in for the same Priority,
+// as the default JobQueue does.
+//
+// The StdScheduler serializes its calls to the JobQueue. The errors of
+// the operations are reported to the Logger of the StdScheduler, and
+// the failed operations are retried as described by the Queue option.
+// The queuetest package provides the conformance tests of the JobQueue
+// implementations.
+type JobQueue interface {
+	// Push adds the job to the queue. ErrJobAlreadyExists is returned
+	// if a job with the same key is queued.
+	Push(job *QueuedJob) error
+
+	// Pop removes and returns the job with the earliest next run time.
+	// ErrQueueEmpty is returned if the queue is empty.
+	Pop() (*QueuedJob, error)
+
+	// Head returns the job with the earliest next run time without
+	// removing it. ErrQueueEmpty is returned if the queue is empty.
+	Head() (*QueuedJob, error)
+
+	// Remove removes and returns the job with the specified key.
+	// ErrJobNotFound is returned if no such job is queued.
+	Remove(key JobKey) (*QueuedJob, error)
+
+	// Len returns the number of the queued jobs.
+	Len() int
+
+	// Clear removes all of the queued jobs.
+	Clear() error
+}
+
+// PersistentJobQueue is implemented by the JobQueues which keep the jobs
+// across the restarts of the process. A popped job is kept until its fire
+// is acknowledged, and the popped jobs which were not acknowledged when the
+// process exited are requeued once the queue is reopened, so that no fire
+// is lost between Pop and its completion. As a consequence, the fires are
+// executed at least once, and the fires interrupted by the exit of the
+// process are executed again.
+type PersistentJobQueue interface {
+	JobQueue
+
+	// Ack acknowledges the completion of the fire of the popped job,
+	// including the fires which were skipped or dropped. The job is
+	// the one returned by Pop.
+	Ack(job *QueuedJob) error
+
+	// Jobs returns the queued jobs, ordered by their next run time.
+	// The StdScheduler reloads the jobs on its first Start.
+	Jobs() ([]*QueuedJob, error)
+}
+
+// item is the scheduler state of a scheduled Job.
 type item struct {
 	Job      Job
 	Trigger  Trigger
+	key      JobKey
+	opts     scheduleOptions
 	priority int64 // item priority, backed by the next run time.
-	index    int   // maintained by the heap.Interface methods.
+	paused   bool
+
+	// jitterBase is the fire time of the Trigger the priority was
+	// jittered from, so that the Trigger is advanced from it.
+	jitterBase int64
+	stats      jobStats
+
+	// held is the fire time of the item held until the end of a quiet
+	// period, so that the Trigger is advanced from it.
+	held int64
+
+	// sem serializes the executions of the item, set unless the
+	// concurrent executions are allowed.
+	sem chan struct{}
+
+	// rescheduled is set when the Trigger was replaced while the
+	// item was in flight, so that the priority is not advanced again.
+	rescheduled bool
+
+	// removed is set when the item was replaced while in flight,
+	// so that it is not returned to the queue.
+	removed bool
+
+	// woken is set when the WakeableTrigger of the item requested a
+	// wakeup, which could not be handled at once.
+	woken bool
+
+	// ahead is the evaluation of the Trigger for the fire after the
+	// one in flight, made for its deadline, which the item is
+	// rescheduled with.
+	ahead *lookahead
+
+	// lastDispatch is the dispatch sequence of the last fire of the
+	// item, ordering the fires of the FairDispatch.
+	lastDispatch uint64
+
+	// missed holds the fire times skipped while the item was paused,
+	// which are caught up once it is resumed.
+	missed []int64
+
+	// startNow is set until the immediate fire of an item scheduled
+	// with the WithStartNow option is rescheduled.
+	startNow bool
+
+	// triggerRetry is set while the item is queued to retry the
+	// computation of its next fire time from retryFrom, after its
+	// Trigger failed triggerRetries times in a row.
+	triggerRetry   bool
+	triggerRetries int
+	retryFrom      int64
+
+	// ack is set while the item popped from a PersistentJobQueue is
+	// not queued again.
+	ack *queueAck
+
+	// history holds the last executions of the item, set when the
+	// HistorySize is configured.
+	history *executionHistory
+}
+
+// lookahead is an evaluation of the Trigger of an item from prev.
+type lookahead struct {
+	prev int64
+	next int64
+	err  error
+}
+
+// queueAck acknowledges a job popped from a PersistentJobQueue once both
+// its fire is completed and its item is queued again, or dropped.
+type queueAck struct {
+	job     *QueuedJob
+	pending int32
 }
 
-// priorityQueue implements the heap.Interface.
-type priorityQueue []*item
+// newQueueAck returns a new queueAck of the popped job.
+func newQueueAck(job *QueuedJob) *queueAck {
+	return &queueAck{job: job, pending: 2}
+}
+
+// done marks one of the steps done, and reports whether the job can be
+// acknowledged.
+func (a *queueAck) done() bool {
+	return a != nil && atomic.AddInt32(&a.pending, -1) == 0
+}
+
+// queuedJob returns the QueuedJob entry of the item.
+func (it *item) queuedJob() *QueuedJob {
+	return &QueuedJob{
+		Key:         it.key,
+		Job:         it.Job,
+		Trigger:     it.Trigger,
+		NextRunTime: it.priority,
+		Priority:    it.opts.priority,
+		Data:        it.opts.data,
+		StartTime:   it.opts.startTime,
+		ExpireTime:  it.opts.expireTime,
+	}
+}
+
+// scheduledJob returns a ScheduledJob snapshot of the item.
+func (it *item) scheduledJob() *ScheduledJob {
+	return &ScheduledJob{
+		Job:                it.Job,
+		Key:                it.key,
+		TriggerDescription: it.Trigger.Description(),
+		NextRunTime:        it.priority,
+		Paused:             it.paused,
+		Timeout:            it.opts.timeout,
+		DeadlineAtNextFire: it.opts.deadline,
+		ConcurrencyPolicy:  it.opts.concurrency,
+		MaxConcurrent:      it.opts.maxRunning,
+		Running:            it.stats.running,
+		MisfirePolicy:      it.opts.misfire,
+		CatchUpPolicy:      it.opts.catchUp,
+		Pool:               it.opts.pool,
+		Jitter:             it.opts.jitter,
+		Priority:           it.opts.priority,
+		Data:               it.opts.data,
+		LastRunTime:        it.stats.lastRunTime,
+		LastCompletedTime:  it.stats.lastCompletedTime,
+		RunCount:           it.stats.runCount,
+		LastError:          it.stats.lastError,
+		RemainingRuns:      remainingRuns(it.Trigger),
+		EndTime:            endTime(it.Trigger),
+		StartTime:          unixTime(it.opts.startTime),
+		ExpireTime:         unixTime(it.opts.expireTime),
+		Location:           triggerLocation(it.Trigger),
+	}
+}
+
+// remainingRuns returns the number of the remaining executions of the
+// item, including the scheduled one, or -1 if it is not limited.
+func remainingRuns(trigger Trigger) int {
+	if n, limited := remainingRepeats(trigger); limited {
+		return n + 1
+	}
+
+	return -1
+}
+
+// endTime returns the end time of the Trigger, or the zero time if it
+// has none.
+func endTime(trigger Trigger) time.Time {
+	end, _ := triggerEndTime(trigger)
+	return end
+}
+
+// jobStats holds the execution stats of an item, which are kept across
+// the reschedules of the item.
+type jobStats struct {
+	lastRunTime       int64
+	lastCompletedTime int64
+	runCount          int64
+	lastError         error
+	running           int
+}
+
+// priorityQueue implements the JobQueue interface using a binary heap.
+type priorityQueue struct {
+	mtx  sync.Mutex
+	heap jobHeap
+	keys map[JobKey]*heapEntry
+	seq  uint64 // the insertion sequence of the entries.
+}
+
+// Verify priorityQueue satisfies the JobQueue interface.
+var _ JobQueue = (*priorityQueue)(nil)
+
+// NewJobQueue returns a new in-memory JobQueue, the default JobQueue of
+// the StdScheduler. It is safe for concurrent use.
+func NewJobQueue() JobQueue {
+	return newPriorityQueue()
+}
+
+// newPriorityQueue returns a new empty priorityQueue.
+func newPriorityQueue() *priorityQueue {
+	return &priorityQueue{keys: make(map[JobKey]*heapEntry)}
+}
+
+// Push adds the job to the priorityQueue.
+func (pq *priorityQueue) Push(job *QueuedJob) error {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	if _, ok := pq.keys[job.Key]; ok {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyExists, job.Key)
+	}
+	pq.seq++
+	entry := &heapEntry{job: job, seq: pq.seq}
+	heap.Push(&pq.heap, entry)
+	pq.keys[job.Key] = entry
+
+	return nil
+}
+
+// Pop removes and returns the first job of the priorityQueue.
+func (pq *priorityQueue) Pop() (*QueuedJob, error) {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	if len(pq.heap) == 0 {
+		return nil, ErrQueueEmpty
+	}
+	entry := heap.Pop(&pq.heap).(*heapEntry)
+	delete(pq.keys, entry.job.Key)
+
+	return entry.job, nil
+}
+
+// Head returns the first job of the priorityQueue without removing it.
+func (pq *priorityQueue) Head() (*QueuedJob, error) {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	if len(pq.heap) == 0 {
+		return nil, ErrQueueEmpty
+	}
+
+	return pq.heap[0].job, nil
+}
+
+// Remove removes and returns the job with the specified key.
+func (pq *priorityQueue) Remove(key JobKey) (*QueuedJob, error) {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	entry, ok := pq.keys[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, key)
+	}
+	heap.Remove(&pq.heap, entry.index)
+	delete(pq.keys, key)
+
+	return entry.job, nil
+}
+
+// jobs returns the queued jobs, ordered by their next run time.
+func (pq *priorityQueue) jobs() []*QueuedJob {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	entries := make(jobHeap, len(pq.heap))
+	copy(entries, pq.heap)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].before(entries[j])
+	})
+	jobs := make([]*QueuedJob, len(entries))
+	for i, entry := range entries {
+		jobs[i] = entry.job
+	}
+
+	return jobs
+}
+
+// contains reports whether a job with the key is queued.
+func (pq *priorityQueue) contains(key JobKey) bool {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	_, ok := pq.keys[key]
+	return ok
+}
 
 // Len returns the priorityQueue length.
-func (pq priorityQueue) Len() int { return len(pq) }
+func (pq *priorityQueue) Len() int {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	return len(pq.heap)
+}
+
+// Clear removes all of the jobs from the priorityQueue.
+func (pq *priorityQueue) Clear() error {
+	pq.mtx.Lock()
+	defer pq.mtx.Unlock()
+
+	pq.heap = nil
+	pq.keys = make(map[JobKey]*heapEntry)
 
-// Less is the items less comparator.
-func (pq priorityQueue) Less(i, j int) bool {
-	return pq[i].priority < pq[j].priority
+	return nil
 }
 
-// Swap exchanges the indexes of the items.
-func (pq priorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
+// heapEntry is the jobHeap element.
+type heapEntry struct {
+	job   *QueuedJob
+	seq   uint64
+	index int // maintained by the heap.Interface methods.
+}
+
+// before reports whether the entry is ordered before the other one: by
+// the next run time, then by the priority, and by the insertion order
+// for the same priority.
+func (e *heapEntry) before(other *heapEntry) bool {
+	switch {
+	case e.job.NextRunTime != other.job.NextRunTime:
+		return e.job.NextRunTime < other.job.NextRunTime
+	case e.job.Priority != other.job.Priority:
+		return e.job.Priority > other.job.Priority
+	default:
+		return e.seq < other.seq
+	}
+}
+
+// jobHeap implements the heap.Interface.
+type jobHeap []*heapEntry
+
+// Len returns the jobHeap length.
+func (h jobHeap) Len() int { return len(h) }
+
+// Less is the entries less comparator.
+func (h jobHeap) Less(i, j int) bool {
+	return h[i].before(h[j])
+}
+
+// Swap exchanges the indexes of the entries.
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
 }
 
 // Push implements the heap.Interface.Push.
 // Adds x as element Len().
-func (pq *priorityQueue) Push(x interface{}) {
-	n := len(*pq)
-	item := x.(*item)
-	item.index = n
-	*pq = append(*pq, item)
+func (h *jobHeap) Push(x interface{}) {
+	entry := x.(*heapEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
 }
 
 // Pop implements the heap.Interface.Pop.
 // Removes and returns element Len() - 1.
-func (pq *priorityQueue) Pop() interface{} {
-	old := *pq
+func (h *jobHeap) Pop() interface{} {
+	old := *h
 	n := len(old)
-	item := old[n-1]
-	item.index = -1 // for safety
-	*pq = old[0 : n-1]
-	return item
-}
-
-// Head returns the first item of the priorityQueue without removing it.
-func (pq *priorityQueue) Head() *item {
-	return (*pq)[0]
-}
-
-// Remove removes and returns the element at index i from the priorityQueue.
-func (pq *priorityQueue) Remove(i int) interface{} {
-	return heap.Remove(pq, i)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1 // for safety
+	*h = old[0 : n-1]
+	return entry
 }
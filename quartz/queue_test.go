@@ -0,0 +1,269 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/reugn/go-quartz/quartz/queuetest"
+)
+
+var errQueueUnavailable = errors.New("queue unavailable")
+
+// flakyQueue wraps a JobQueue, failing the requested number of the next
+// calls of the operations.
+type flakyQueue struct {
+	quartz.JobQueue
+	mtx      sync.Mutex
+	failures map[string]int
+}
+
+func newFlakyQueue() *flakyQueue {
+	return &flakyQueue{
+		JobQueue: quartz.NewJobQueue(),
+		failures: make(map[string]int),
+	}
+}
+
+func (q *flakyQueue) failNext(op string, n int) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.failures[op] = n
+}
+
+func (q *flakyQueue) fail(op string) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.failures[op] > 0 {
+		q.failures[op]--
+		return errQueueUnavailable
+	}
+	return nil
+}
+
+func (q *flakyQueue) Push(job *quartz.QueuedJob) error {
+	if err := q.fail("push"); err != nil {
+		return err
+	}
+	return q.JobQueue.Push(job)
+}
+
+func (q *flakyQueue) Pop() (*quartz.QueuedJob, error) {
+	if err := q.fail("pop"); err != nil {
+		return nil, err
+	}
+	return q.JobQueue.Pop()
+}
+
+func (q *flakyQueue) Head() (*quartz.QueuedJob, error) {
+	if err := q.fail("head"); err != nil {
+		return nil, err
+	}
+	return q.JobQueue.Head()
+}
+
+func (q *flakyQueue) Remove(key quartz.JobKey) (*quartz.QueuedJob, error) {
+	if err := q.fail("remove"); err != nil {
+		return nil, err
+	}
+	return q.JobQueue.Remove(key)
+}
+
+func TestJobQueue(t *testing.T) {
+	queuetest.TestJobQueue(t, quartz.NewJobQueue)
+}
+
+func TestSchedulerQueueErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	queue := newFlakyQueue()
+	logger := &recordingLogger{}
+	clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+		Queue:             queue,
+		Clock:             clock,
+		Logger:            logger,
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var runs int32
+	job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		return true, nil
+	})
+	trigger := quartz.NewSimpleTrigger(time.Minute)
+
+	// the errors of the requested operations are returned
+	queue.failNext("push", 1)
+	assertEqual(t, errors.Is(sched.ScheduleJob(ctx, job, trigger), errQueueUnavailable), true)
+	assertEqual(t, len(sched.GetJobKeys()), 0)
+	if err := sched.ScheduleJob(ctx, job, trigger); err != nil {
+		t.Fatal(err)
+	}
+	queue.failNext("remove", 1)
+	assertEqual(t, errors.Is(sched.DeleteJob(job.Key()), errQueueUnavailable), true)
+	assertEqual(t, sched.GetJobKeys(), []int{job.Key()})
+
+	// a failed push of the rescheduled job is retried
+	queue.failNext("push", 1)
+	clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+	assertEqual(t, logger.contains("The JobQueue operation failed [operation push"), true)
+	assertEqual(t, queue.Len(), 0)
+	assertEqual(t, sched.GetJobKeys(), []int{job.Key()})
+
+	clock.Advance(time.Second)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, queue.Len(), 1)
+	clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(2))
+
+	// the execution loop backs off after a failed read
+	queue.failNext("head", 1)
+	clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(2))
+	assertEqual(t, logger.contains("The JobQueue operation failed [operation head"), true)
+	clock.Advance(time.Second)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(3))
+}
+
+func TestSchedulerQueueAdoption(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the jobs found in the queue are executed with the default options
+	done := make(chan struct{})
+	queue := quartz.NewJobQueue()
+	key := quartz.NewJobKeyWithGroup("restored", "jobs")
+	err := queue.Push(&quartz.QueuedJob{
+		Key: key,
+		Job: quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+			close(done)
+			return true, nil
+		}),
+		Trigger:     quartz.NewRunOnceTrigger(0),
+		NextRunTime: time.Now().UnixNano(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Queue:  queue,
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("the queued job was not executed")
+	}
+	time.Sleep(10 * time.Millisecond)
+	assertEqual(t, len(sched.GetJobKeysWithGroup("jobs")), 0)
+	assertEqual(t, queue.Len(), 0)
+}
+
+func TestJobQueueTieBreak(t *testing.T) {
+	queue := quartz.NewJobQueue()
+	var expected, rest []quartz.JobKey
+	for i := 0; i < 100; i++ {
+		job := &quartz.QueuedJob{
+			Key:         quartz.NewJobKey(fmt.Sprintf("job%d", i)),
+			Job:         quartz.NewShellJob("ls"),
+			Trigger:     quartz.NewRunOnceTrigger(time.Second),
+			NextRunTime: 1000,
+		}
+		if i%10 == 0 {
+			job.Priority = 1
+			expected = append(expected, job.Key)
+		} else {
+			rest = append(rest, job.Key)
+		}
+		if err := queue.Push(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected = append(expected, rest...)
+
+	keys := make([]quartz.JobKey, 0, 100)
+	for queue.Len() > 0 {
+		job, err := queue.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, job.Key)
+	}
+	assertEqual(t, keys, expected)
+}
+
+func TestSchedulerSimultaneousFires(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		BlockingExecution: true,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+
+	var mtx sync.Mutex
+	var fired []int
+	var expected, rest []int
+	for i := 0; i < 100; i++ {
+		i := i
+		job := quartz.NewFunctionJobWithDesc(fmt.Sprintf("job%d", i), func(_ context.Context) (bool, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			fired = append(fired, i)
+			return true, nil
+		})
+		var opts []quartz.ScheduleOption
+		if i%10 == 5 {
+			opts = append(opts, quartz.WithPriority(1))
+			expected = append(expected, i)
+		} else {
+			rest = append(rest, i)
+		}
+		if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTriggerAt(now.Add(time.Minute)), opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected = append(expected, rest...)
+
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+	clock.Advance(time.Minute)
+
+	for {
+		mtx.Lock()
+		n := len(fired)
+		mtx.Unlock()
+		if n == 100 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%d of the jobs fired", n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	assertEqual(t, fired, expected)
+}
@@ -0,0 +1,195 @@
+// Package queuetest implements the conformance tests of the quartz.JobQueue
+// implementations.
+package queuetest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// TestJobQueue runs the conformance tests against the JobQueue returned by
+// newQueue, which is called once for each of the tests and has to return an
+// empty queue. The queued jobs are ShellJobs with SimpleTriggers, so that
+// the queues persisting the jobs can round-trip them. The jobs returned by
+// the queue are compared by their keys, descriptions and next run times.
+func TestJobQueue(t *testing.T, newQueue func() quartz.JobQueue) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(*testing.T, quartz.JobQueue)
+	}{
+		{"Empty", testEmpty},
+		{"Order", testOrder},
+		{"Head", testHead},
+		{"Duplicate", testDuplicate},
+		{"Remove", testRemove},
+		{"Clear", testClear},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newQueue())
+		})
+	}
+}
+
+// queuedJob returns a new QueuedJob with the given name and next run time.
+func queuedJob(name string, nextRunTime int64) *quartz.QueuedJob {
+	return &quartz.QueuedJob{
+		Key:         quartz.NewJobKeyWithGroup(name, "queuetest"),
+		Job:         quartz.NewShellJob(fmt.Sprintf("echo %s", name)),
+		Trigger:     quartz.NewSimpleTrigger(time.Minute),
+		NextRunTime: nextRunTime,
+	}
+}
+
+func push(t *testing.T, queue quartz.JobQueue, jobs ...*quartz.QueuedJob) {
+	t.Helper()
+	for _, job := range jobs {
+		if err := queue.Push(job); err != nil {
+			t.Fatalf("Push(%s): %v", job.Key, err)
+		}
+	}
+}
+
+func assertJob(t *testing.T, job *quartz.QueuedJob, err error, expected *quartz.QueuedJob) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error for %s: %v", expected.Key, err)
+	}
+	if job.Key != expected.Key {
+		t.Fatalf("key %s != %s", job.Key, expected.Key)
+	}
+	if job.NextRunTime != expected.NextRunTime {
+		t.Fatalf("next run time of %s: %d != %d", job.Key, job.NextRunTime, expected.NextRunTime)
+	}
+	if job.Job == nil || job.Job.Description() != expected.Job.Description() {
+		t.Fatalf("unexpected job of %s: %v", job.Key, job.Job)
+	}
+	if job.Trigger == nil || job.Trigger.Description() != expected.Trigger.Description() {
+		t.Fatalf("unexpected trigger of %s: %v", job.Key, job.Trigger)
+	}
+}
+
+func assertLen(t *testing.T, queue quartz.JobQueue, expected int) {
+	t.Helper()
+	if n := queue.Len(); n != expected {
+		t.Fatalf("Len() = %d, expected %d", n, expected)
+	}
+}
+
+func assertErr(t *testing.T, err, expected error) {
+	t.Helper()
+	if !errors.Is(err, expected) {
+		t.Fatalf("error %v, expected %v", err, expected)
+	}
+}
+
+func testEmpty(t *testing.T, queue quartz.JobQueue) {
+	assertLen(t, queue, 0)
+	_, err := queue.Head()
+	assertErr(t, err, quartz.ErrQueueEmpty)
+	_, err = queue.Pop()
+	assertErr(t, err, quartz.ErrQueueEmpty)
+	_, err = queue.Remove(quartz.NewJobKeyWithGroup("missing", "queuetest"))
+	assertErr(t, err, quartz.ErrJobNotFound)
+	assertErr(t, queue.Clear(), nil)
+}
+
+func testOrder(t *testing.T, queue quartz.JobQueue) {
+	jobs := []*quartz.QueuedJob{
+		queuedJob("a", 5),
+		queuedJob("b", 1),
+		queuedJob("c", 4),
+		queuedJob("d", 2),
+		queuedJob("e", 3),
+	}
+	push(t, queue, jobs...)
+	assertLen(t, queue, len(jobs))
+
+	for i, expected := range []*quartz.QueuedJob{jobs[1], jobs[3], jobs[4], jobs[2], jobs[0]} {
+		job, err := queue.Pop()
+		assertJob(t, job, err, expected)
+		assertLen(t, queue, len(jobs)-i-1)
+	}
+	_, err := queue.Pop()
+	assertErr(t, err, quartz.ErrQueueEmpty)
+}
+
+func testHead(t *testing.T, queue quartz.JobQueue) {
+	later, earlier := queuedJob("later", 20), queuedJob("earlier", 10)
+	push(t, queue, later)
+	job, err := queue.Head()
+	assertJob(t, job, err, later)
+
+	push(t, queue, earlier)
+	job, err = queue.Head()
+	assertJob(t, job, err, earlier)
+	job, err = queue.Head()
+	assertJob(t, job, err, earlier)
+	assertLen(t, queue, 2)
+}
+
+func testDuplicate(t *testing.T, queue quartz.JobQueue) {
+	job := queuedJob("job", 10)
+	push(t, queue, job)
+	assertErr(t, queue.Push(queuedJob("job", 5)), quartz.ErrJobAlreadyExists)
+	assertLen(t, queue, 1)
+
+	head, err := queue.Head()
+	assertJob(t, head, err, job)
+
+	// the key is unique within the group
+	other := queuedJob("job", 5)
+	other.Key = quartz.NewJobKeyWithGroup("job", "other")
+	push(t, queue, other)
+	assertLen(t, queue, 2)
+}
+
+func testRemove(t *testing.T, queue quartz.JobQueue) {
+	jobs := []*quartz.QueuedJob{
+		queuedJob("a", 1),
+		queuedJob("b", 2),
+		queuedJob("c", 3),
+	}
+	push(t, queue, jobs...)
+
+	job, err := queue.Remove(jobs[0].Key)
+	assertJob(t, job, err, jobs[0])
+	_, err = queue.Remove(jobs[0].Key)
+	assertErr(t, err, quartz.ErrJobNotFound)
+	assertLen(t, queue, 2)
+
+	job, err = queue.Head()
+	assertJob(t, job, err, jobs[1])
+
+	// a removed key can be pushed again
+	readded := queuedJob("a", 4)
+	push(t, queue, readded)
+	job, err = queue.Remove(jobs[2].Key)
+	assertJob(t, job, err, jobs[2])
+	for _, expected := range []*quartz.QueuedJob{jobs[1], readded} {
+		job, err := queue.Pop()
+		assertJob(t, job, err, expected)
+	}
+	assertLen(t, queue, 0)
+}
+
+func testClear(t *testing.T, queue quartz.JobQueue) {
+	push(t, queue, queuedJob("a", 1), queuedJob("b", 2))
+	assertErr(t, queue.Clear(), nil)
+	assertLen(t, queue, 0)
+	_, err := queue.Head()
+	assertErr(t, err, quartz.ErrQueueEmpty)
+
+	// the queue is usable once cleared
+	job := queuedJob("a", 3)
+	push(t, queue, job)
+	head, err := queue.Head()
+	assertJob(t, head, err, job)
+}
@@ -0,0 +1,132 @@
+package quartz
+
+import "time"
+
+// QuietPeriod is a recurring window, e.g. a nightly maintenance window,
+// during which the StdScheduler does not dispatch the jobs. The fires
+// which come due during the window are held until it closes, and are
+// then handled according to the MisfirePolicy, as late fires.
+type QuietPeriod struct {
+	// Start and End are the offsets from midnight of the [Start, End)
+	// window of each day. The window wraps around midnight if End is
+	// before Start.
+	Start time.Duration
+	End   time.Duration
+
+	// Location is the location of the time of day of the window. When
+	// nil, UTC is used.
+	Location *time.Location
+
+	// Calendar, when set, defines the window instead of Start and End:
+	// the instants it excludes are quiet.
+	Calendar Calendar
+}
+
+// calendar returns the Calendar excluding the instants of the quiet
+// period, or false if the period is empty or invalid.
+func (p QuietPeriod) calendar() (Calendar, bool) {
+	if p.Calendar != nil {
+		return p.Calendar, true
+	}
+	if p.Start == p.End {
+		return nil, false
+	}
+	calendar, err := NewDailyCalendar(p.Start, p.End, p.Location)
+	if err != nil {
+		return nil, false
+	}
+
+	return calendar, true
+}
+
+// quietCalendars returns the calendars of the valid quiet periods.
+func quietCalendars(periods []QuietPeriod) []Calendar {
+	calendars := make([]Calendar, 0, len(periods))
+	for _, period := range periods {
+		if calendar, ok := period.calendar(); ok {
+			calendars = append(calendars, calendar)
+		}
+	}
+
+	return calendars
+}
+
+// boundedCalendar is implemented by the calendars which can compute the
+// end of their excluded ranges.
+type boundedCalendar interface {
+	// excludedUntil returns the end of the excluded range containing
+	// the excluded instant.
+	excludedUntil(t time.Time) time.Time
+}
+
+// excludedUntil returns the first instant after the excluded instant
+// which is not excluded by the Calendar. The calendars which cannot
+// compute it are probed a minute at a time, then narrowed down to the
+// nanosecond.
+func excludedUntil(calendar Calendar, t time.Time) time.Time {
+	if bounded, ok := calendar.(boundedCalendar); ok {
+		return bounded.excludedUntil(t)
+	}
+
+	lo, hi := t, t.Add(time.Minute)
+	for i := 0; calendar.IsExcluded(hi); i++ {
+		if i == calendarMaxIterations {
+			return hi
+		}
+		lo, hi = hi, hi.Add(time.Minute)
+	}
+	for hi.Sub(lo) > time.Nanosecond {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if calendar.IsExcluded(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return hi
+}
+
+// quietUntil returns the end of the quiet periods the current time falls
+// within, if the fire of the item is to be held. The retries of a failed
+// Trigger, the paused items and the items scheduled using the
+// WithIgnoreQuietPeriod option are not held. The caller must hold the
+// lock.
+func (sched *StdScheduler) quietUntil(it *item) (time.Time, bool) {
+	if len(sched.quiet) == 0 || it.opts.ignoreQuiet || it.triggerRetry || it.paused {
+		return time.Time{}, false
+	}
+
+	now := sched.opts.Clock.Now()
+	until := now
+	// the overlapping periods extend the window
+	for i, moved := 0, true; moved && i < calendarMaxIterations; i++ {
+		moved = false
+		for _, calendar := range sched.quiet {
+			if calendar.IsExcluded(until) {
+				until = excludedUntil(calendar, until)
+				moved = true
+			}
+		}
+	}
+
+	return until, until.After(now)
+}
+
+// hold returns the popped item to the queue until the end of the quiet
+// period, keeping its fire time, so that its Trigger is advanced from it
+// once the held fire is handled. The caller must hold the lock.
+func (sched *StdScheduler) hold(it *item, until time.Time) {
+	if it.held == 0 {
+		it.held = it.priority
+	}
+	it.priority = until.UnixNano()
+	sched.opts.Logger.Debug("Holding the Job fire during the quiet period",
+		"key", it.key,
+		"scheduled_time", time.Unix(0, it.held),
+		"until", until,
+	)
+	sched.push(it)
+	sched.reportQueueLength()
+	sched.resetHead()
+}
@@ -0,0 +1,115 @@
+package quartz_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerQuietPeriods(t *testing.T) {
+	maintenance, err := quartz.NewDailyCalendar(time.Hour, time.Hour+30*time.Minute, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, period := range map[string]quartz.QuietPeriod{
+		"time of day": {Start: time.Hour, End: time.Hour + 30*time.Minute},
+		// the end of the window is searched for
+		"calendar": {Calendar: quartz.NewCompositeCalendar(maintenance)},
+	} {
+		period := period
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			now := time.Date(2024, 6, 1, 0, 55, 0, 0, time.UTC)
+			clock := quartz.NewMockClock(now)
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				Clock:             clock,
+				Logger:            quartz.NewNoopLogger(),
+				MisfirePolicy:     quartz.MisfireFireNow,
+				OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+				QuietPeriods:      []quartz.QuietPeriod{period},
+			})
+			listener := &recordingListener{}
+			sched.AddListener(listener)
+
+			var held, skipped, ignoring int32
+			count := func(runs *int32) func(context.Context) error {
+				return func(ctx context.Context) error {
+					atomic.AddInt32(runs, 1)
+					return nil
+				}
+			}
+			heldKey, err := sched.ScheduleFunc(ctx, count(&held), quartz.NewSimpleTrigger(10*time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			skippedKey, err := sched.ScheduleFunc(ctx, count(&skipped), quartz.NewSimpleTrigger(10*time.Minute),
+				quartz.WithMisfirePolicy(quartz.MisfireSkip))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ignoringKey, err := sched.ScheduleFunc(ctx, count(&ignoring), quartz.NewRunOnceTrigger(10*time.Minute),
+				quartz.WithIgnoreQuietPeriod())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer sched.Stop()
+
+			waitForNextRun := func(key int, expected time.Time) {
+				t.Helper()
+				for {
+					job, err := sched.GetScheduledJob(key)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if job.NextRunTime == expected.UnixNano() {
+						return
+					}
+					select {
+					case <-ctx.Done():
+						t.Fatalf("unexpected next run time %s", job.NextRun())
+					case <-time.After(5 * time.Millisecond):
+					}
+				}
+			}
+
+			// the fires due during the quiet period are held until it
+			// ends, unlike the fire of the job ignoring it
+			time.Sleep(20 * time.Millisecond)
+			errs := make(chan error, 1)
+			go func() { errs <- sched.WaitForJob(ctx, ignoringKey) }()
+			time.Sleep(10 * time.Millisecond)
+			clock.Advance(10 * time.Minute)
+			assertEqual(t, <-errs, nil)
+			end := time.Date(2024, 6, 1, 1, 30, 0, 0, time.UTC)
+			waitForNextRun(heldKey, end)
+			waitForNextRun(skippedKey, end)
+			assertEqual(t, atomic.LoadInt32(&held), int32(0))
+			assertEqual(t, atomic.LoadInt32(&skipped), int32(0))
+			assertEqual(t, atomic.LoadInt32(&ignoring), int32(1))
+
+			// once released, the held fires are handled according to
+			// their MisfirePolicy
+			go func() { errs <- sched.WaitForJob(ctx, heldKey) }()
+			time.Sleep(10 * time.Millisecond)
+			clock.Advance(25 * time.Minute)
+			assertEqual(t, <-errs, nil)
+			waitForNextRun(heldKey, end.Add(10*time.Minute))
+			waitForNextRun(skippedKey, end.Add(5*time.Minute))
+			assertEqual(t, atomic.LoadInt32(&held), int32(1))
+			assertEqual(t, atomic.LoadInt32(&skipped), int32(0))
+			listener.mtx.Lock()
+			assertEqual(t, len(listener.skipped), 3)
+			assertEqual(t, listener.lateness[0], 25*time.Minute)
+			listener.mtx.Unlock()
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package quartz
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// typeRegistry maps the type names to the factories of the registered
+// types, used to encode the values of an interface type as JSON objects
+// holding their type name in the "type" field.
+type typeRegistry[T any] struct {
+	sync.RWMutex
+	kind       string
+	errUnknown error
+	factories  map[string]func() T
+	names      map[reflect.Type]string
+}
+
+// newTypeRegistry returns a new typeRegistry of the kind of the values,
+// returning the errUnknown for the values of the unregistered types.
+func newTypeRegistry[T any](kind string, errUnknown error) *typeRegistry[T] {
+	return &typeRegistry[T]{
+		kind:       kind,
+		errUnknown: errUnknown,
+		factories:  make(map[string]func() T),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// register registers the type of the values returned by the factory under
+// the type name. It panics if the type name or the type is registered.
+func (r *typeRegistry[T]) register(typeName string, factory func() T) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.factories[typeName]; ok {
+		panic(fmt.Sprintf("quartz: %s type %q is already registered", r.kind, typeName))
+	}
+	valueType := reflect.TypeOf(factory())
+	if _, ok := r.names[valueType]; ok {
+		panic(fmt.Sprintf("quartz: %s type %s is already registered", r.kind, valueType))
+	}
+
+	r.factories[typeName] = factory
+	r.names[valueType] = typeName
+}
+
+// marshal returns the JSON encoding of the value, extended with its type
+// name in the "type" field.
+func (r *typeRegistry[T]) marshal(value T) ([]byte, error) {
+	r.RLock()
+	typeName, ok := r.names[reflect.TypeOf(value)]
+	r.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", r.errUnknown, value)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"], _ = json.Marshal(typeName)
+
+	return json.Marshal(fields)
+}
+
+// unmarshal decodes a value encoded by marshal, using the registered type
+// of its "type" field.
+func (r *typeRegistry[T]) unmarshal(data []byte) (T, error) {
+	var zero T
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return zero, err
+	}
+
+	r.RLock()
+	factory, ok := r.factories[header.Type]
+	r.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", r.errUnknown, header.Type)
+	}
+
+	value := factory()
+	if err := json.Unmarshal(data, value); err != nil {
+		return zero, err
+	}
+
+	return value, nil
+}
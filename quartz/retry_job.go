@@ -0,0 +1,180 @@
+package quartz
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// RetryPolicy decides whether and when a failed execution of a RetryJob
+// is retried.
+type RetryPolicy interface {
+	// NextRetry returns the delay before the next attempt, following the
+	// error of the given attempt, and false if the execution should not
+	// be retried. The attempts are numbered from 1.
+	NextRetry(attempt int, err error) (time.Duration, bool)
+}
+
+// RetryPolicyFunc is a function implementing the RetryPolicy interface.
+type RetryPolicyFunc func(attempt int, err error) (time.Duration, bool)
+
+// NextRetry calls the function.
+func (f RetryPolicyFunc) NextRetry(attempt int, err error) (time.Duration, bool) {
+	return f(attempt, err)
+}
+
+// FixedRetryPolicy implements the RetryPolicy interface; retries at a
+// fixed interval, until the maximum number of attempts is reached.
+type FixedRetryPolicy struct {
+	interval    time.Duration
+	maxAttempts int
+}
+
+// Verify FixedRetryPolicy satisfies the RetryPolicy interface.
+var _ RetryPolicy = (*FixedRetryPolicy)(nil)
+
+// NewFixedRetryPolicy returns a new FixedRetryPolicy. The maxAttempts
+// include the first attempt, a non-positive maxAttempts does not limit
+// the number of attempts.
+func NewFixedRetryPolicy(interval time.Duration, maxAttempts int) *FixedRetryPolicy {
+	return &FixedRetryPolicy{
+		interval:    interval,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// NextRetry returns the fixed interval, unless the maximum number of
+// attempts is reached.
+func (p *FixedRetryPolicy) NextRetry(attempt int, _ error) (time.Duration, bool) {
+	if p.maxAttempts > 0 && attempt >= p.maxAttempts {
+		return 0, false
+	}
+
+	return p.interval, true
+}
+
+// ExponentialRetryPolicy implements the RetryPolicy interface; increases
+// the delay between the attempts exponentially, until the maximum number
+// of attempts is reached.
+type ExponentialRetryPolicy struct {
+	initial     time.Duration
+	max         time.Duration
+	factor      float64
+	maxAttempts int
+}
+
+// Verify ExponentialRetryPolicy satisfies the RetryPolicy interface.
+var _ RetryPolicy = (*ExponentialRetryPolicy)(nil)
+
+// NewExponentialRetryPolicy returns a new ExponentialRetryPolicy, whose
+// first delay is initial. Each following delay is multiplied by the factor,
+// capped at max. A non-positive max leaves the delays uncapped, a factor
+// below 1 is treated as 1. The maxAttempts include the first attempt, a
+// non-positive maxAttempts does not limit the number of attempts.
+func NewExponentialRetryPolicy(initial, max time.Duration, factor float64,
+	maxAttempts int) *ExponentialRetryPolicy {
+	if factor < 1 {
+		factor = 1
+	}
+
+	return &ExponentialRetryPolicy{
+		initial:     initial,
+		max:         max,
+		factor:      factor,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// NextRetry returns the delay following the attempt, unless the maximum
+// number of attempts is reached.
+func (p *ExponentialRetryPolicy) NextRetry(attempt int, _ error) (time.Duration, bool) {
+	if p.maxAttempts > 0 && attempt >= p.maxAttempts {
+		return 0, false
+	}
+
+	delay := float64(p.initial) * math.Pow(p.factor, float64(attempt-1))
+	if p.max > 0 && delay > float64(p.max) {
+		return p.max, true
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64, true
+	}
+
+	return time.Duration(delay), true
+}
+
+// RetryListener is implemented by the SchedulerListeners interested in
+// the outcome of the RetryJob executions.
+type RetryListener interface {
+	// RetryJobCompleted is called when an execution of a RetryJob
+	// returns, with the number of the attempts made and the error of
+	// the last attempt, nil if the execution succeeded.
+	RetryJobCompleted(job ScheduledJob, attempts int, err error)
+}
+
+// RetryJob wraps a Job, retrying its failed executions according to
+// the RetryPolicy. A panic of the wrapped Job is retried as an error.
+type RetryJob struct {
+	job    Job
+	policy RetryPolicy
+}
+
+// Verify RetryJob satisfies the Job interface.
+var _ Job = (*RetryJob)(nil)
+
+// NewRetryJob returns a new RetryJob wrapping the given Job.
+func NewRetryJob(job Job, policy RetryPolicy) *RetryJob {
+	return &RetryJob{
+		job:    job,
+		policy: policy,
+	}
+}
+
+// Description returns the description of the wrapped Job.
+func (rj *RetryJob) Description() string {
+	return rj.job.Description()
+}
+
+// Key returns the key of the wrapped Job.
+func (rj *RetryJob) Key() int {
+	return rj.job.Key()
+}
+
+// Execute executes the wrapped Job, retrying it while the RetryPolicy
+// allows it and the context is not done. The error of the last attempt
+// is returned. The outcome is reported to the RetryListeners of the
+// StdScheduler executing the RetryJob.
+func (rj *RetryJob) Execute(ctx context.Context) error {
+	attempt, err := rj.execute(ctx)
+	notifyListeners(ctx, func(l SchedulerListener, job ScheduledJob) {
+		if listener, ok := l.(RetryListener); ok {
+			listener.RetryJobCompleted(job, attempt, err)
+		}
+	})
+
+	return err
+}
+
+// execute runs the attempts, returning the number of the attempts and the
+// error of the last one.
+func (rj *RetryJob) execute(ctx context.Context) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := executeJob(ctx, rj.job)
+		if err == nil || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		delay, retry := rj.policy.NextRetry(attempt, err)
+		if !retry {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
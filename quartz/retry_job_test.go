@@ -0,0 +1,143 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+type retryOutcome struct {
+	key      quartz.JobKey
+	attempts int
+	err      error
+}
+
+type retryListener struct {
+	quartz.NoopListener
+	outcomes chan retryOutcome
+}
+
+func (l *retryListener) RetryJobCompleted(job quartz.ScheduledJob, attempts int, err error) {
+	l.outcomes <- retryOutcome{job.Key, attempts, err}
+}
+
+// failingJob fails the given number of executions, then succeeds.
+func failingJob(failures int32, runs *int32) quartz.Job {
+	return quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		if atomic.AddInt32(runs, 1) <= failures {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	})
+}
+
+func TestRetryJob(t *testing.T) {
+	ctx := context.Background()
+
+	var runs int32
+	job := quartz.NewRetryJob(failingJob(2, &runs), quartz.NewFixedRetryPolicy(time.Millisecond, 3))
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(3))
+
+	runs = 0
+	job = quartz.NewRetryJob(failingJob(3, &runs), quartz.NewFixedRetryPolicy(time.Millisecond, 3))
+	assertEqual(t, job.Execute(ctx).Error(), "failed")
+	assertEqual(t, atomic.LoadInt32(&runs), int32(3))
+
+	// the panics are retried
+	runs = 0
+	job = quartz.NewRetryJob(quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			panic("boom")
+		}
+		return true, nil
+	}), quartz.NewFixedRetryPolicy(time.Millisecond, 0))
+	assertEqual(t, job.Execute(ctx), nil)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(2))
+
+	// the policy decides given the error
+	runs = 0
+	permanent := errors.New("permanent")
+	job = quartz.NewRetryJob(quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		return false, permanent
+	}), quartz.RetryPolicyFunc(func(_ int, err error) (time.Duration, bool) {
+		return time.Millisecond, !errors.Is(err, permanent)
+	}))
+	assertEqual(t, job.Execute(ctx), permanent)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+}
+
+func TestRetryJobCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var runs int32
+	job := quartz.NewRetryJob(failingJob(2, &runs), quartz.NewFixedRetryPolicy(time.Hour, 3))
+	start := time.Now()
+	assertEqual(t, job.Execute(ctx).Error(), "failed")
+	assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatal("the retry did not respect the cancellation", elapsed)
+	}
+}
+
+func TestExponentialRetryPolicy(t *testing.T) {
+	policy := quartz.NewExponentialRetryPolicy(10*time.Millisecond, 50*time.Millisecond, 2, 5)
+	for attempt, expected := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		50 * time.Millisecond,
+	} {
+		delay, retry := policy.NextRetry(attempt+1, nil)
+		assertEqual(t, retry, true)
+		assertEqual(t, delay, expected)
+	}
+	_, retry := policy.NextRetry(5, nil)
+	assertEqual(t, retry, false)
+
+	// an uncapped policy saturates
+	policy = quartz.NewExponentialRetryPolicy(time.Second, 0, 10, 0)
+	delay, retry := policy.NextRetry(100, nil)
+	assertEqual(t, retry, true)
+	assertEqual(t, delay, time.Duration(math.MaxInt64))
+}
+
+func TestRetryJobListener(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := &retryListener{outcomes: make(chan retryOutcome, 2)}
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.AddListener(listener)
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var runs int32
+	succeeding := quartz.NewRetryJob(failingJob(1, &runs), quartz.NewFixedRetryPolicy(time.Millisecond, 3))
+	if err := sched.ScheduleJob(ctx, succeeding, quartz.NewRunOnceTrigger(0)); err != nil {
+		t.Fatal(err)
+	}
+	outcome := <-listener.outcomes
+	assertEqual(t, outcome.key, quartz.NewJobKey(strconv.Itoa(succeeding.Key())))
+	assertEqual(t, outcome.attempts, 2)
+	assertEqual(t, outcome.err, nil)
+
+	var failures int32
+	failing := quartz.NewRetryJob(failingJob(5, &failures), quartz.NewFixedRetryPolicy(time.Millisecond, 3))
+	if err := sched.ScheduleJob(ctx, failing, quartz.NewRunOnceTrigger(0)); err != nil {
+		t.Fatal(err)
+	}
+	outcome = <-listener.outcomes
+	assertEqual(t, outcome.attempts, 3)
+	assertEqual(t, outcome.err.Error(), "failed")
+}
@@ -0,0 +1,366 @@
+package quartz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ErrInvalidSchedule is returned by ImportSchedule for a document which
+// cannot be decoded, and for its duplicate entries.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// scheduleDocument is the JSON document of an exported schedule.
+type scheduleDocument struct {
+	Jobs []scheduleEntry `json:"jobs"`
+}
+
+// scheduleEntry is a scheduled Job of an exported schedule.
+type scheduleEntry struct {
+	Name        string              `json:"name"`
+	Group       string              `json:"group,omitempty"`
+	Job         json.RawMessage     `json:"job"`
+	Trigger     json.RawMessage     `json:"trigger"`
+	NextRunTime time.Time           `json:"next_run_time"`
+	Paused      bool                `json:"paused,omitempty"`
+	Options     scheduleOptionsJSON `json:"options"`
+}
+
+// scheduleOptionsJSON is the JSON representation of the scheduleOptions
+// which outlive the scheduling of a Job. The policies are encoded only
+// when they override the ones of the StdSchedulerOptions.
+type scheduleOptionsJSON struct {
+	Timeout            jsonDuration      `json:"timeout,omitempty"`
+	DeadlineAtNextFire bool              `json:"deadline_at_next_fire,omitempty"`
+	Concurrency        ConcurrencyPolicy `json:"concurrency,omitempty"`
+	MaxConcurrent      int               `json:"max_concurrent,omitempty"`
+	Pool               string            `json:"pool,omitempty"`
+	Jitter             jsonDuration      `json:"jitter,omitempty"`
+	Priority           int               `json:"priority,omitempty"`
+	IgnoreQuietPeriod  bool              `json:"ignore_quiet_period,omitempty"`
+	Misfire            *MisfirePolicy    `json:"misfire,omitempty"`
+	CatchUp            *CatchUpPolicy    `json:"catch_up,omitempty"`
+	Data               map[string]any    `json:"data,omitempty"`
+	StartTime          *time.Time        `json:"start_time,omitempty"`
+	ExpireTime         *time.Time        `json:"expire_time,omitempty"`
+}
+
+func (opts *scheduleOptions) toJSON() scheduleOptionsJSON {
+	options := scheduleOptionsJSON{
+		Timeout:            jsonDuration(opts.timeout),
+		DeadlineAtNextFire: opts.deadline,
+		Concurrency:        opts.concurrency,
+		MaxConcurrent:      opts.maxRunning,
+		Pool:               opts.pool,
+		Jitter:             jsonDuration(opts.jitter),
+		Priority:           opts.priority,
+		IgnoreQuietPeriod:  opts.ignoreQuiet,
+		Data:               opts.data,
+	}
+	if opts.misfireSet {
+		misfire := opts.misfire
+		options.Misfire = &misfire
+	}
+	if opts.catchUpSet {
+		catchUp := opts.catchUp
+		options.CatchUp = &catchUp
+	}
+	if opts.startTime != 0 {
+		start := time.Unix(0, opts.startTime).UTC()
+		options.StartTime = &start
+	}
+	if opts.expireTime != 0 {
+		expire := time.Unix(0, opts.expireTime).UTC()
+		options.ExpireTime = &expire
+	}
+
+	return options
+}
+
+// scheduleOptions returns the ScheduleOptions of the JSON representation.
+func (opts *scheduleOptionsJSON) scheduleOptions() []ScheduleOption {
+	options := []ScheduleOption{
+		WithTimeout(time.Duration(opts.Timeout)),
+		WithConcurrencyPolicy(opts.Concurrency),
+		WithMaxConcurrent(opts.MaxConcurrent),
+		WithPool(opts.Pool),
+		WithJitter(time.Duration(opts.Jitter)),
+		WithPriority(opts.Priority),
+		WithData(opts.Data),
+	}
+	if opts.DeadlineAtNextFire {
+		options = append(options, WithDeadlineAtNextFire())
+	}
+	if opts.IgnoreQuietPeriod {
+		options = append(options, WithIgnoreQuietPeriod())
+	}
+	if opts.Misfire != nil {
+		options = append(options, WithMisfirePolicy(*opts.Misfire))
+	}
+	if opts.CatchUp != nil {
+		options = append(options, WithCatchUp(*opts.CatchUp, time.Time{}))
+	}
+	if opts.StartTime != nil {
+		options = append(options, WithStartTime(*opts.StartTime))
+	}
+	if opts.ExpireTime != nil {
+		options = append(options, WithExpireTime(*opts.ExpireTime))
+	}
+
+	return options
+}
+
+// ExportSchedule writes all of the scheduled jobs to w as a JSON document,
+// which ImportSchedule recreates on another StdScheduler, e.g. for disaster
+// recovery. Each Job is encoded along with its Trigger, using MarshalJob
+// and MarshalTrigger, its next run time, its paused state and its options,
+// except for the callbacks. The next run time of a Job being executed is
+// the fire time of the execution. The jobs whose Job or Trigger types are
+// not registered are reported in a BatchError, while the other jobs are
+// written.
+func (sched *StdScheduler) ExportSchedule(w io.Writer) error {
+	var batchErr BatchError
+	var doc scheduleDocument
+	sched.mtx.Lock()
+	for i, it := range sched.items() {
+		entry, err := it.scheduleEntry()
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: i, Key: it.key, Err: err})
+			continue
+		}
+		doc.Jobs = append(doc.Jobs, *entry)
+	}
+	sched.mtx.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if len(batchErr.Errors) > 0 {
+		return &batchErr
+	}
+
+	return nil
+}
+
+// scheduleEntry returns the exported entry of the item. The caller must
+// hold the lock.
+func (it *item) scheduleEntry() (*scheduleEntry, error) {
+	job, err := MarshalJob(it.Job)
+	if err != nil {
+		return nil, err
+	}
+	trigger, err := MarshalTrigger(it.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fire time of the Trigger, before the jitter and the
+	// quiet periods are applied
+	nextRunTime := it.priority
+	switch {
+	case it.held != 0:
+		nextRunTime = it.held
+	case it.jitterBase != 0:
+		nextRunTime = it.jitterBase
+	}
+
+	return &scheduleEntry{
+		Name:        it.key.Name,
+		Group:       it.key.Group,
+		Job:         job,
+		Trigger:     trigger,
+		NextRunTime: time.Unix(0, nextRunTime).UTC(),
+		Paused:      it.paused,
+		Options:     it.opts.toJSON(),
+	}, nil
+}
+
+// importedJob is a validated entry of an imported schedule.
+type importedJob struct {
+	index   int
+	key     JobKey
+	job     Job
+	trigger Trigger
+	options scheduleOptions
+}
+
+// ImportSchedule schedules the jobs of a document written by
+// ExportSchedule. All of the entries are validated before any of them is
+// scheduled: if any of the entries is invalid, e.g. of a Job or Trigger
+// type which is not registered, or with the key of a scheduled Job, the
+// errors of the entries are returned in a BatchError and no Job is
+// scheduled. The next run times which have passed are handled according
+// to the MisfirePolicy of the jobs: the fire is executed at once with
+// MisfireFireNow, the Trigger is advanced past the missed fires one by
+// one with MisfireSkip, and straight to its next fire time after now with
+// MisfireRescheduleNext. The jobs which the JobQueue fails to push are
+// reported in a BatchError, while the other jobs are scheduled.
+func (sched *StdScheduler) ImportSchedule(ctx context.Context, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var doc scheduleDocument
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	var batchErr BatchError
+	declared := make(map[JobKey]struct{}, len(doc.Jobs))
+	jobs := make([]*importedJob, 0, len(doc.Jobs))
+	for i := range doc.Jobs {
+		entry := &doc.Jobs[i]
+		key := NewJobKeyWithGroup(entry.Name, entry.Group)
+		if _, ok := declared[key]; ok {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: i, Key: key,
+				Err: fmt.Errorf("%w: duplicate key", ErrInvalidSchedule)})
+			continue
+		}
+		declared[key] = struct{}{}
+
+		job, err := entry.importedJob(i, key)
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: i, Key: key, Err: err})
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	sched.mtx.Lock()
+	now := sched.nowNano()
+	valid := 0
+	for _, job := range jobs {
+		err := sched.validateImport(job, now)
+		if err == nil {
+			// the imported jobs are new, so the entries beyond
+			// the MaxQueueSize are rejected
+			valid++
+			if sched.queueFull(valid) {
+				err = ErrQueueFull
+			}
+		}
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: job.index, Key: job.key, Err: err})
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		sched.mtx.Unlock()
+		sort.Slice(batchErr.Errors, func(i, j int) bool {
+			return batchErr.Errors[i].Index < batchErr.Errors[j].Index
+		})
+		return &batchErr
+	}
+
+	scheduled := make([]ScheduledJob, 0, len(jobs))
+	for _, job := range jobs {
+		entry, err := sched.schedule(job.key, job.job, job.trigger, job.options)
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &JobError{Index: job.index, Key: job.key, Err: err})
+			continue
+		}
+		scheduled = append(scheduled, entry)
+	}
+	if len(scheduled) > 0 {
+		sched.reportQueueLength()
+		if sched.isRunning() {
+			sched.resetHead()
+		}
+	}
+	sched.mtx.Unlock()
+
+	for _, job := range scheduled {
+		job := job
+		sched.opts.Metrics.IncCounter(MetricJobsScheduled)
+		sched.notify(func(l SchedulerListener) { l.JobScheduled(job) })
+	}
+	if len(batchErr.Errors) > 0 {
+		return &batchErr
+	}
+
+	return nil
+}
+
+// importedJob decodes the Job, the Trigger and the options of the entry.
+func (e *scheduleEntry) importedJob(index int, key JobKey) (*importedJob, error) {
+	job, err := UnmarshalJob(e.Job)
+	if err != nil {
+		return nil, err
+	}
+	trigger, err := UnmarshalTrigger(e.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	options := newScheduleOptions(e.Options.scheduleOptions())
+	options.paused = e.Paused
+	options.nextRunTime = e.NextRunTime.UnixNano()
+
+	return &importedJob{
+		index:   index,
+		key:     key,
+		job:     job,
+		trigger: trigger,
+		options: options,
+	}, nil
+}
+
+// validateImport checks that the imported Job can be scheduled, and
+// applies its MisfirePolicy to its next run time if it has passed. The
+// caller must hold the lock.
+func (sched *StdScheduler) validateImport(job *importedJob, now int64) error {
+	if _, ok := sched.opts.Pools[job.options.pool]; job.options.pool != "" && !ok {
+		return ErrPoolNotFound
+	}
+	if sched.findItem(job.key) != nil {
+		return ErrJobAlreadyExists
+	}
+	if err := sched.advanceImport(job, now); err != nil {
+		return err
+	}
+	if job.options.expired(job.options.bounded(job.options.nextRunTime)) {
+		return ErrJobExpired
+	}
+
+	return nil
+}
+
+// advanceImport advances the passed next run time of the imported Job
+// according to its MisfirePolicy.
+func (sched *StdScheduler) advanceImport(job *importedJob, now int64) error {
+	if job.options.nextRunTime >= now {
+		return nil
+	}
+
+	policy := sched.opts.MisfirePolicy
+	if job.options.misfireSet {
+		policy = job.options.misfire
+	}
+	switch policy {
+	case MisfireFireNow:
+		job.options.nextRunTime = now
+	case MisfireRescheduleNext:
+		nextRunTime, err := job.trigger.NextFireTime(now)
+		if err != nil {
+			return err
+		}
+		job.options.nextRunTime = nextRunTime
+	default:
+		for job.options.nextRunTime < now {
+			nextRunTime, err := job.trigger.NextFireTime(job.options.nextRunTime)
+			if err != nil {
+				return err
+			}
+			job.options.nextRunTime = nextRunTime
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package quartz_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestScheduleExportImport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	newScheduler := func(clock quartz.Clock) *quartz.StdScheduler {
+		return quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+			Clock:  clock,
+			Logger: quartz.NewNoopLogger(),
+			Pools:  map[string]int{"io": 1},
+		})
+	}
+
+	source := newScheduler(quartz.NewMockClock(now))
+	backup := quartz.NewJobKey("backup")
+	report := quartz.NewJobKeyWithGroup("report", "reports")
+	refresh := quartz.NewJobKey("refresh")
+	cron, err := quartz.NewCronTrigger("0 0 3 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	curl, err := quartz.NewCurlJob("GET", "http://localhost/report", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range []struct {
+		key     quartz.JobKey
+		job     quartz.Job
+		trigger quartz.Trigger
+		opts    []quartz.ScheduleOption
+	}{
+		{backup, quartz.NewShellJob("tar -czf backup.tgz data"), cron, []quartz.ScheduleOption{
+			quartz.WithTimeout(time.Hour),
+			quartz.WithDeadlineAtNextFire(),
+			quartz.WithPool("io"),
+			quartz.WithPriority(2),
+			quartz.WithData(map[string]any{"target": "s3"}),
+			quartz.WithConcurrencyPolicy(quartz.ConcurrencySkip),
+		}},
+		{report, curl, quartz.NewSimpleTrigger(time.Hour), []quartz.ScheduleOption{
+			quartz.WithPaused(),
+			quartz.WithMisfirePolicy(quartz.MisfireFireNow),
+		}},
+		{refresh, quartz.NewShellJob("make refresh"), quartz.NewSimpleTrigger(time.Hour), []quartz.ScheduleOption{
+			quartz.WithMisfirePolicy(quartz.MisfireRescheduleNext),
+		}},
+	} {
+		if err := source.ScheduleJobWithKey(ctx, job.key, job.job, job.trigger, job.opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the jobs of the unregistered types are reported, while the
+	// other jobs are exported
+	function := quartz.NewFunctionJob(func(context.Context) (bool, error) { return true, nil })
+	if err := source.ScheduleJob(ctx, function, quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = source.ExportSchedule(&buf)
+	var batchErr *quartz.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, len(batchErr.Errors), 1)
+	assertEqual(t, errors.Is(err, quartz.ErrUnknownJobType), true)
+	exported := buf.String()
+
+	// the schedule is recreated as it was
+	target := newScheduler(quartz.NewMockClock(now))
+	if err := target.ImportSchedule(ctx, strings.NewReader(exported)); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, target.JobCount(), 3)
+	sourceJobs, targetJobs := scheduledJobsByKey(source), scheduledJobsByKey(target)
+	for _, key := range []quartz.JobKey{backup, report, refresh} {
+		original, imported := sourceJobs[key], targetJobs[key]
+		assertEqual(t, imported.Job.Description(), original.Job.Description())
+		assertEqual(t, imported.TriggerDescription, original.TriggerDescription)
+		assertEqual(t, imported.NextRunTime, original.NextRunTime)
+		assertEqual(t, imported.Paused, original.Paused)
+		assertEqual(t, imported.Timeout, original.Timeout)
+		assertEqual(t, imported.DeadlineAtNextFire, original.DeadlineAtNextFire)
+		assertEqual(t, imported.ConcurrencyPolicy, original.ConcurrencyPolicy)
+		assertEqual(t, imported.MisfirePolicy, original.MisfirePolicy)
+		assertEqual(t, imported.Pool, original.Pool)
+		assertEqual(t, imported.Priority, original.Priority)
+		assertEqual(t, imported.Data, original.Data)
+	}
+
+	// nothing is imported unless all of the entries are valid
+	err = target.ImportSchedule(ctx, strings.NewReader(exported))
+	if !errors.As(err, &batchErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, len(batchErr.Errors), 3)
+	assertEqual(t, errors.Is(err, quartz.ErrJobAlreadyExists), true)
+
+	invalid := newScheduler(quartz.NewMockClock(now))
+	err = invalid.ImportSchedule(ctx, strings.NewReader(`{"jobs": [
+		{"name": "a", "job": {"type": "shell", "cmd": "ls"}, "trigger": {"type": "simple", "interval": "1h"},
+		 "next_run_time": "2024-06-01T09:00:00Z", "options": {}},
+		{"name": "b", "job": {"type": "unknown"}, "trigger": {"type": "simple", "interval": "1h"},
+		 "next_run_time": "2024-06-01T09:00:00Z", "options": {}},
+		{"name": "c", "job": {"type": "shell", "cmd": "ls"}, "trigger": {"type": "unknown"},
+		 "next_run_time": "2024-06-01T09:00:00Z", "options": {}}
+	]}`))
+	if !errors.As(err, &batchErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, len(batchErr.Errors), 2)
+	assertEqual(t, batchErr.Errors[0].Index, 1)
+	assertEqual(t, errors.Is(batchErr.Errors[0], quartz.ErrUnknownJobType), true)
+	assertEqual(t, batchErr.Errors[1].Index, 2)
+	assertEqual(t, errors.Is(batchErr.Errors[1], quartz.ErrUnknownTriggerType), true)
+	assertEqual(t, invalid.JobCount(), 0)
+
+	err = invalid.ImportSchedule(ctx, strings.NewReader(`{"jobs": 1}`))
+	assertEqual(t, errors.Is(err, quartz.ErrInvalidSchedule), true)
+
+	// the passed next run times are handled by the MisfirePolicy
+	later := now.Add(90 * time.Minute)
+	late := newScheduler(quartz.NewMockClock(later))
+	if err := late.ImportSchedule(ctx, strings.NewReader(exported)); err != nil {
+		t.Fatal(err)
+	}
+	lateJobs := scheduledJobsByKey(late)
+	for _, tt := range []struct {
+		key         quartz.JobKey
+		nextRunTime time.Time
+	}{
+		{backup, time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)},
+		{report, later},
+		{refresh, later.Add(time.Hour)},
+	} {
+		assertEqual(t, lateJobs[tt.key].NextRunTime, tt.nextRunTime.UnixNano())
+	}
+}
+
+func scheduledJobsByKey(sched *quartz.StdScheduler) map[quartz.JobKey]*quartz.ScheduledJob {
+	jobs := make(map[quartz.JobKey]*quartz.ScheduledJob)
+	for _, job := range sched.GetScheduledJobs() {
+		jobs[job.Key] = job
+	}
+	return jobs
+}
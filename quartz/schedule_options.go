@@ -0,0 +1,292 @@
+package quartz
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConcurrencyPolicy determines how a fire of a Job is handled while a
+// previous execution of the same Job is still running.
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyAllow executes the fires regardless of the running
+	// executions of the Job.
+	ConcurrencyAllow ConcurrencyPolicy = iota
+
+	// ConcurrencySkip skips the fire and reports it if the previous
+	// execution of the Job, or the WithMaxConcurrent executions, have
+	// not returned.
+	ConcurrencySkip
+
+	// ConcurrencyQueue delays the fire until the previous execution
+	// of the Job, or one of the WithMaxConcurrent executions, returns.
+	// In the WorkerLimit mode, a delayed fire occupies a worker while
+	// waiting.
+	ConcurrencyQueue
+)
+
+// ScheduleOption configures how a Job is scheduled.
+type ScheduleOption func(*scheduleOptions)
+
+// scheduleOptions holds the per-job scheduling configuration, which is
+// carried by the queue item across reschedules.
+type scheduleOptions struct {
+	timeout     time.Duration
+	deadline    bool
+	replace     bool
+	startNow    bool
+	paused      bool
+	concurrency ConcurrencyPolicy
+	maxRunning  int
+	pool        string
+	jitter      time.Duration
+	priority    int
+	ignoreQuiet bool
+	data        map[string]any
+
+	// misfire overrides the MisfirePolicy of the StdSchedulerOptions
+	// when misfireSet is set.
+	misfire    MisfirePolicy
+	misfireSet bool
+
+	// catchUp overrides the CatchUpPolicy of the StdSchedulerOptions
+	// when catchUpSet is set. The Trigger is advanced from the
+	// lastFireTime, if set, when the Job is scheduled.
+	catchUp      CatchUpPolicy
+	catchUpSet   bool
+	lastFireTime time.Time
+
+	// nextRunTime, when set by ImportSchedule, is the first fire
+	// time of the Job, instead of the next fire time of its Trigger.
+	nextRunTime int64
+
+	// startTime and expireTime, in Unix nanoseconds, bound the fire
+	// times of the Job when set.
+	startTime  int64
+	expireTime int64
+
+	onError   func(ctx context.Context, job Job, err error)
+	onSuccess func(ctx context.Context, job Job)
+}
+
+// newScheduleOptions applies the options to the default configuration.
+func newScheduleOptions(opts []ScheduleOption) scheduleOptions {
+	var options scheduleOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithTimeout bounds each execution of the Job by the given timeout,
+// after which the context passed to Execute is canceled. It overrides
+// the JobTimeout of the StdSchedulerOptions.
+func WithTimeout(timeout time.Duration) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// WithDeadlineAtNextFire bounds each execution of the Job by the next fire
+// time of its Trigger, after which the context passed to Execute is
+// canceled, e.g. for the polling jobs whose late results are superseded
+// by the next fire. Along with a timeout, the earlier of the deadlines
+// applies, and reaching either is reported as a timeout. The deadline is
+// the fire time before the WithJitter delay, and applies to the scheduled
+// fires only, not to the ones of TriggerJob or of the catch-up. The fires
+// of a Trigger with no next fire time are not bounded.
+func WithDeadlineAtNextFire() ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.deadline = true
+	}
+}
+
+// WithConcurrencyPolicy sets the policy for the fires of the Job which
+// happen while a previous execution of the Job is running. By default,
+// the executions are allowed to overlap.
+func WithConcurrencyPolicy(policy ConcurrencyPolicy) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.concurrency = policy
+	}
+}
+
+// WithMaxConcurrent allows up to n executions of the Job to overlap. The
+// fires beyond n are handled according to the ConcurrencyPolicy of the
+// Job, skipped unless it is ConcurrencyQueue. A limit of 1 is the same
+// as a ConcurrencyPolicy other than ConcurrencyAllow alone, while zero
+// leaves the executions to the ConcurrencyPolicy.
+func WithMaxConcurrent(n int) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.maxRunning = n
+	}
+}
+
+// WithStartNow fires the Job once at the moment it is scheduled, or when
+// the scheduler is started if it is not running, before following the
+// schedule of its Trigger. The immediate fire is in addition to the fire
+// times of the Trigger, which are counted from the immediate fire, and is
+// never considered outdated.
+func WithStartNow() ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.startNow = true
+	}
+}
+
+// WithReplaceExisting replaces an already scheduled Job with the same
+// key instead of failing with ErrJobAlreadyExists. A replaced Job that
+// is being executed at the moment is allowed to complete, but is not
+// scheduled again.
+func WithReplaceExisting() ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.replace = true
+	}
+}
+
+// WithPool dispatches the fires of the Job to the named worker pool,
+// which has to be defined in the Pools of the StdSchedulerOptions.
+// Otherwise, scheduling the Job fails with ErrPoolNotFound. The jobs
+// scheduled without the option follow the WorkerLimit configuration.
+func WithPool(name string) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.pool = name
+	}
+}
+
+// WithPaused schedules the Job in the paused state. Its fires are
+// skipped until it is resumed.
+func WithPaused() ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.paused = true
+	}
+}
+
+// WithMisfirePolicy overrides the MisfirePolicy of the StdSchedulerOptions
+// for the outdated fires of the Job.
+func WithMisfirePolicy(policy MisfirePolicy) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.misfire = policy
+		opts.misfireSet = true
+	}
+}
+
+// WithCatchUp overrides the CatchUpPolicy of the StdSchedulerOptions for
+// the fires of the Job missed while the scheduler was not running or the
+// Job was paused. Unless zero, the lastFireTime is the recorded time of
+// the last fire of the Job, e.g. by a previous run of the process, which
+// the Trigger is advanced from when the Job is scheduled, so that the
+// fires missed since are caught up.
+func WithCatchUp(policy CatchUpPolicy, lastFireTime time.Time) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.catchUp = policy
+		opts.catchUpSet = true
+		opts.lastFireTime = lastFireTime
+	}
+}
+
+// WithPriority sets the priority of the Job among the jobs which fire at
+// the same time, the jobs with a higher priority being executed first.
+// The jobs with the same priority fire in the order they were queued in.
+// Defaults to zero.
+func WithPriority(priority int) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.priority = priority
+	}
+}
+
+// WithIgnoreQuietPeriod exempts the Job from the QuietPeriods of the
+// StdSchedulerOptions, so that its fires are dispatched during the quiet
+// periods.
+func WithIgnoreQuietPeriod() ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.ignoreQuiet = true
+	}
+}
+
+// WithJitter delays each of the fire times of the Job by a random offset
+// in [0, jitter), e.g. to spread the jobs scheduled on the same Trigger.
+// The offsets do not accumulate, and apply to the Trigger the Job is
+// rescheduled with.
+func WithJitter(jitter time.Duration) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.jitter = jitter
+	}
+}
+
+// WithStartTime delays the fires of the Job until the start time: the fire
+// times of its Trigger before it are pushed to the start time, and the
+// Trigger is advanced from there, e.g. to schedule a Job today which only
+// becomes active next week. The zero time sets no start time.
+func WithStartTime(start time.Time) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.startTime = unixNano(start)
+	}
+}
+
+// WithExpireTime retires the Job once the next fire time of its Trigger is
+// after the expire time: the Job is removed from the scheduler and reported
+// as unscheduled with ErrJobExpired, while scheduling it fails with
+// ErrJobExpired if it would never fire. The fires delayed by WithJitter may
+// happen after the expire time, by less than the jitter. Unlike the
+// WithEndTime option of the Triggers, it applies to any Trigger. The zero
+// time sets no expire time.
+func WithExpireTime(expire time.Time) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.expireTime = unixNano(expire)
+	}
+}
+
+// bounded returns the fire time pushed to the start time of the Job, if
+// it is before it.
+func (opts *scheduleOptions) bounded(fireTime int64) int64 {
+	if fireTime < opts.startTime {
+		return opts.startTime
+	}
+
+	return fireTime
+}
+
+// expired reports whether the fire time is after the expire time of the
+// Job.
+func (opts *scheduleOptions) expired(fireTime int64) bool {
+	return opts.expireTime != 0 && fireTime > opts.expireTime
+}
+
+// WithOnError sets the callback invoked after each execution of the Job
+// which returns an error, including when it panics or times out.
+func WithOnError(callback func(ctx context.Context, job Job, err error)) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.onError = callback
+	}
+}
+
+// WithOnSuccess sets the callback invoked after each execution of the
+// Job which returns no error.
+func WithOnSuccess(callback func(ctx context.Context, job Job)) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.onSuccess = callback
+	}
+}
+
+// callback invokes the callback of the outcome of the execution, on the
+// goroutine of the execution once the Job has returned. The context is
+// the one passed to Execute. A panic of the callback is recovered and
+// returned as an error.
+func (opts *scheduleOptions) callback(ctx context.Context, job Job, err error) (panicErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = fmt.Errorf("job callback panicked: %v", r)
+		}
+	}()
+
+	switch {
+	case err != nil && opts.onError != nil:
+		opts.onError(ctx, job, err)
+	case err == nil && opts.onSuccess != nil:
+		opts.onSuccess(ctx, job)
+	}
+
+	return nil
+}
@@ -1,19 +1,144 @@
 package quartz
 
 import (
-	"container/heap"
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrJobNotFound is returned when there is no Job with the given key
+// in the Scheduler's execution queue.
+var ErrJobNotFound = errors.New("no Job with the given Key found")
+
+// ErrSchedulerNotStarted is returned by operations which require a
+// running Scheduler.
+var ErrSchedulerNotStarted = errors.New("the Scheduler is not started")
+
+// ErrJobAlreadyExists is returned when scheduling a Job with the key of
+// an already scheduled Job.
+var ErrJobAlreadyExists = errors.New("a Job with the given Key already exists")
+
+// ErrSchedulerAlreadyStarted is returned by StartErr when starting a running
+// Scheduler.
+var ErrSchedulerAlreadyStarted = errors.New("the Scheduler is already started")
+
+// ErrPoolNotFound is returned when scheduling a Job to a worker pool
+// which is not configured in the StdSchedulerOptions.
+var ErrPoolNotFound = errors.New("no worker pool with the given name found")
+
+// ErrJobExpired is returned when a Job scheduled using WithExpireTime has
+// no fire time left before its expire time.
+var ErrJobExpired = errors.New("the Job is expired")
+
+// ErrQueueFull is returned when scheduling a new Job while the number of
+// the scheduled jobs has reached the MaxQueueSize.
+var ErrQueueFull = errors.New("the Scheduler queue is full")
+
 // ScheduledJob wraps a scheduled Job with its metadata.
 type ScheduledJob struct {
 	Job                Job
+	Key                JobKey
 	TriggerDescription string
 	NextRunTime        int64
+	Paused             bool
+
+	// Timeout is the maximum duration of each execution of the
+	// Job, zero if the executions are not bounded.
+	Timeout time.Duration
+
+	// DeadlineAtNextFire is set when each execution of the Job is
+	// bounded by the next fire time of its Trigger.
+	DeadlineAtNextFire bool
+
+	// ConcurrencyPolicy is the policy of the fires of the Job which
+	// happen while a previous execution is running.
+	ConcurrencyPolicy ConcurrencyPolicy
+
+	// MisfirePolicy is the policy of the outdated fires of the Job,
+	// either set using WithMisfirePolicy or the MisfirePolicy of
+	// the StdSchedulerOptions.
+	MisfirePolicy MisfirePolicy
+
+	// CatchUpPolicy is the policy of the fires of the Job missed while
+	// the scheduler was not running or the Job was paused.
+	CatchUpPolicy CatchUpPolicy
+
+	// MaxConcurrent is the number of the executions of the Job
+	// allowed to overlap, set using WithMaxConcurrent, zero if the
+	// executions are limited by the ConcurrencyPolicy alone.
+	MaxConcurrent int
+
+	// Running is the number of the executions of the Job running
+	// at the moment.
+	Running int
+
+	// Pool is the name of the worker pool the Job is dispatched
+	// to, empty if it follows the WorkerLimit configuration.
+	Pool string
+
+	// Jitter is the maximum random delay of the fire times.
+	Jitter time.Duration
+
+	// Priority is the priority of the Job among the jobs which fire
+	// at the same time.
+	Priority int
+
+	// Data is the data attached to the Job using WithData or
+	// SetJobData, nil if none. It must not be modified.
+	Data map[string]any
+
+	// LastRunTime is the time, in Unix nanoseconds, the last
+	// execution of the Job started at, zero if never executed.
+	LastRunTime int64
+
+	// LastCompletedTime is the time, in Unix nanoseconds, the
+	// last execution of the Job returned at.
+	LastCompletedTime int64
+
+	// RunCount is the number of the started executions.
+	RunCount int64
+
+	// LastError is the error of the last completed execution,
+	// nil if the execution succeeded.
+	LastError error
+
+	// RemainingRuns is the number of the remaining executions of
+	// the Job, including the scheduled one, or -1 if the Trigger
+	// is not limited by a repeat count.
+	RemainingRuns int
+
+	// EndTime is the time after which the Trigger no longer fires,
+	// zero if the Trigger does not implement the StatefulTrigger
+	// interface or has no end time.
+	EndTime time.Time
+
+	// StartTime is the time the fires of the Job are delayed until,
+	// set using WithStartTime, zero if none.
+	StartTime time.Time
+
+	// ExpireTime is the time after which the Job is retired, set
+	// using WithExpireTime, zero if none.
+	ExpireTime time.Time
+
+	// Location is the location of the Trigger, nil if the Trigger
+	// is not evaluated in a location.
+	Location *time.Location
+}
+
+// NextRun returns the next run time of the Job, in the location of its
+// Trigger if it has one.
+func (job *ScheduledJob) NextRun() time.Time {
+	next := time.Unix(0, job.NextRunTime)
+	if job.Location != nil {
+		return next.In(job.Location)
+	}
+
+	return next
 }
 
 // Scheduler represents a Job orchestrator.
@@ -29,7 +154,7 @@ type Scheduler interface {
 	IsStarted() bool
 
 	// ScheduleJob schedules a job using a specified trigger.
-	ScheduleJob(ctx context.Context, job Job, trigger Trigger) error
+	ScheduleJob(ctx context.Context, job Job, trigger Trigger, opts ...ScheduleOption) error
 
 	// GetJobKeys returns the keys of all of the scheduled jobs.
 	GetJobKeys() []int
@@ -37,6 +162,10 @@ type Scheduler interface {
 	// GetScheduledJob returns the scheduled job with the specified key.
 	GetScheduledJob(key int) (*ScheduledJob, error)
 
+	// GetScheduledJobs returns a snapshot of all of the scheduled
+	// jobs, ordered by their next run time.
+	GetScheduledJobs() []*ScheduledJob
+
 	// DeleteJob removes the job with the specified key from the Scheduler's execution queue.
 	DeleteJob(key int) error
 
@@ -53,17 +182,143 @@ type Scheduler interface {
 	Stop()
 }
 
+// MisfirePolicy determines how the StdScheduler handles a fire of a
+// Job whose scheduled time is outdated, e.g. after the system was
+// suspended or when the execution was delayed by other jobs.
+type MisfirePolicy int
+
+const (
+	// MisfireSkip skips the outdated fire and reports it, then
+	// advances the Job to the fire that follows the missed one.
+	// Each of the missed fires is skipped in turn.
+	MisfireSkip MisfirePolicy = iota
+
+	// MisfireFireNow executes the outdated fire immediately, then
+	// advances the Job to its next fire time after now.
+	MisfireFireNow
+
+	// MisfireRescheduleNext skips the outdated fire and reports it,
+	// then advances the Job straight to its next fire time after now.
+	MisfireRescheduleNext
+)
+
+// CatchUpPolicy determines how the StdScheduler handles the fires of a
+// Job which were missed while the scheduler was not running, or while
+// the Job was paused. The missed fires are found by advancing the
+// Trigger from the last known fire time of the Job, once the scheduler
+// is started or the Job is resumed.
+type CatchUpPolicy int
+
+const (
+	// CatchUpDisabled handles the missed fires as the outdated ones,
+	// according to the MisfirePolicy.
+	CatchUpDisabled CatchUpPolicy = iota
+
+	// CatchUpOnce executes a single catch-up run for the missed fires,
+	// scheduled at the latest of them.
+	CatchUpOnce
+
+	// CatchUpAll executes a catch-up run for each of the missed fires.
+	// The runs are dispatched in the order of their scheduled times,
+	// and may overlap unless the ConcurrencyPolicy serializes them.
+	CatchUpAll
+
+	// CatchUpSkip skips the missed fires, advancing the Job straight
+	// to its next fire time after now.
+	CatchUpSkip
+)
+
+// Limiter limits the rate of the job executions. It is satisfied by the
+// golang.org/x/time/rate Limiter.
+type Limiter interface {
+	// Wait blocks until an execution is allowed, or returns an
+	// error if the context is done first.
+	Wait(ctx context.Context) error
+}
+
+// OverflowPolicy determines how a fire is handled when all of the
+// workers are busy and the dispatch queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the execution loop until the fire is
+	// dispatched to a worker.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDrop drops the fire and reports it.
+	OverflowDrop
+
+	// OverflowSpill executes the fire in an extra goroutine,
+	// outside of the worker pool.
+	OverflowSpill
+)
+
+// DefaultOutdatedThreshold is the lateness after which a fire is
+// considered outdated by the StdScheduler returned by NewStdScheduler.
+const DefaultOutdatedThreshold = 10 * time.Millisecond
+
+// queueRetryInterval is the delay after which the execution loop retries
+// the failed JobQueue operations.
+const queueRetryInterval = time.Second
+
+// defaultTriggerRetryInterval is the delay after which the next fire time
+// of a Job is computed again when its Trigger failed, used when no
+// interval is configured.
+const defaultTriggerRetryInterval = time.Second
+
+// defaultDispatchBatchSize is the DispatchBatchSize used when no batch
+// size is configured.
+const defaultDispatchBatchSize = 256
+
+// OutdatedCheckDisabled is an OutdatedThreshold which disables the
+// outdated check, so that the late fires are always executed.
+const OutdatedCheckDisabled time.Duration = 0
+
+// The lifecycle states of the StdScheduler.
+const (
+	stateIdle int32 = iota
+	stateRunning
+	stateStopped
+)
+
 // StdScheduler implements the quartz.Scheduler interface.
 type StdScheduler struct {
-	mtx       sync.Mutex
-	wg        *sync.WaitGroup
-	queue     *priorityQueue
-	interrupt chan time.Time
-	cancel    context.CancelFunc
-	feeder    chan *item
-	dispatch  chan *item
-	started   bool
-	opts      StdSchedulerOptions
+	// droppedEvents is accessed atomically, it is kept first for
+	// its 64-bit alignment
+	droppedEvents uint64
+
+	mtx         sync.Mutex
+	wg          *waitGroup
+	queue       JobQueue
+	interrupt   chan time.Time
+	cancel      context.CancelFunc
+	cancelJobs  context.CancelFunc
+	feeder      chan *item
+	dispatch    chan *fire
+	immediate   chan *fire
+	inflight    map[*item]struct{}
+	pending     map[*item]struct{}
+	retryAt     time.Time
+	jumpedUntil int64
+	quiet       []Calendar
+	index       map[JobKey]*item
+	running     map[JobKey]map[*fire]struct{}
+	waiters     map[JobKey][]chan error
+	pool        *workerPool
+	pools       map[string]*workerPool
+	rand        *rand.Rand
+	workerLimit int
+	listeners   []SchedulerListener
+	events      *eventStream
+	done        <-chan struct{}
+	state       int32
+	lastKey     int
+	reloaded    bool
+	dispatches  uint64
+	batch       []*dueFire      // the popped due fires not dispatched yet
+	startCtx    context.Context // the context of the current run
+	started     chan struct{}   // closed once the OnStart jobs have returned
+	opts        StdSchedulerOptions
 }
 
 type StdSchedulerOptions struct {
@@ -77,9 +332,243 @@ type StdSchedulerOptions struct {
 	// of goroutines of WorkerLimit size to limit the total number
 	// of processes usable by the Scheduler. If all worker threads
 	// are in use, job scheduling will wait till a job can be
-	// dispatched. If BlockingExecution is set, then WorkerLimit
-	// is ignored.
+	// dispatched, unless configured otherwise by the
+	// DispatchQueueSize and DispatchOverflow options. If
+	// BlockingExecution is set, then WorkerLimit is ignored.
 	WorkerLimit int
+
+	// DispatchQueueSize is the number of fires which can wait for
+	// a worker without blocking the execution loop. Only used
+	// when WorkerLimit is greater than 0.
+	DispatchQueueSize int
+
+	// DispatchBatchSize is the maximum number of the due jobs the
+	// execution loop pops from the queue at once, under a single
+	// lock, before dispatching them. The jobs beyond it are popped
+	// by the next pass of the loop. When 0, a batch size of 256 is
+	// used.
+	DispatchBatchSize int
+
+	// FairDispatch, when set, makes the execution loop dispatch each
+	// batch of the due jobs starting with the jobs dispatched the
+	// longest time ago, so that a Job which is always due, e.g. when
+	// its fires lag behind while the workers are saturated, does not
+	// starve the other due jobs. Otherwise, the due jobs are
+	// dispatched in the order of their fire times.
+	FairDispatch bool
+
+	// FeederBuffer is the capacity of the channel handing the jobs
+	// rescheduled by the execution loop over to the feed reader,
+	// which queues them. A buffered feeder lets the execution loop
+	// move on to the next due fire without waiting for the queue,
+	// e.g. when many jobs fire at once. The jobs left in the buffer
+	// are queued once the scheduler is stopped. Defaults to 0, an
+	// unbuffered channel.
+	FeederBuffer int
+
+	// MaxQueueSize, when greater than 0, is the maximum number of the
+	// scheduled jobs. Scheduling a new Job once it is reached fails
+	// with ErrQueueFull, while replacing a scheduled Job and the
+	// rescheduling of the jobs after their fires are never rejected,
+	// nor are the jobs reloaded from a PersistentJobQueue. Defaults to
+	// 0, an unlimited queue.
+	MaxQueueSize int
+
+	// DispatchOverflow determines how a fire is handled when all
+	// of the workers are busy and the dispatch queue is full.
+	// Defaults to OverflowBlock.
+	DispatchOverflow OverflowPolicy
+
+	// Pools defines the named worker pools, along with their fixed
+	// number of workers, to which the jobs scheduled using the
+	// WithPool option are dispatched. Each pool has a dispatch
+	// queue of DispatchQueueSize fires, and the DispatchOverflow
+	// policy applies once it is full. A blocked fire only delays
+	// the jobs of its pool, not the execution loop. The pools are
+	// ignored if BlockingExecution is set.
+	Pools map[string]int
+
+	// JobTimeout, when greater than 0, bounds each execution
+	// of the jobs, canceling the context passed to Execute once
+	// the timeout expires. Jobs scheduled using the WithTimeout
+	// option use their own timeout instead.
+	JobTimeout time.Duration
+
+	// MisfirePolicy determines how the outdated fires of the jobs
+	// are handled. Defaults to MisfireSkip.
+	MisfirePolicy MisfirePolicy
+
+	// CatchUpPolicy determines how the fires of the jobs missed
+	// while the scheduler was not running, e.g. the fires of the
+	// jobs reloaded from a PersistentJobQueue, or while the jobs
+	// were paused are handled. Jobs scheduled using the WithCatchUp
+	// option use their own policy instead. Defaults to
+	// CatchUpDisabled.
+	CatchUpPolicy CatchUpPolicy
+
+	// OutdatedThreshold is the lateness after which a fire is
+	// considered outdated and handled according to the
+	// MisfirePolicy. Zero, or OutdatedCheckDisabled, disables the
+	// check entirely, so that the late fires are never skipped.
+	// See DefaultOutdatedThreshold.
+	OutdatedThreshold time.Duration
+
+	// ClockCheckInterval, when greater than 0, is the interval at
+	// which the execution loop compares the elapsed wall clock time
+	// with the monotonic one its timers run on, as it does when the
+	// timer of a fire expires, to detect the jumps of the clock, e.g.
+	// when the system is suspended or the clock is set. Once the
+	// clock jumps by more than the ClockJumpThreshold, the timer of
+	// the next fire is armed again, and the fires missed during a
+	// forward jump are handled according to the MisfirePolicy
+	// instead of being executed late. The jumps are detected using
+	// the real clock and the MockClock. See DefaultClockCheckInterval.
+	ClockCheckInterval time.Duration
+
+	// ClockJumpThreshold is the difference of the elapsed wall
+	// clock time beyond which the clock is considered to have
+	// jumped. When 0, a threshold of five seconds is used.
+	ClockJumpThreshold time.Duration
+
+	// LatenessThreshold, when greater than 0, enables the lateness
+	// watchdog, which checks every LatenessCheckInterval whether the
+	// next fire of the queue is late by more than the threshold, e.g.
+	// while the workers are saturated and the execution loop waits
+	// for one of them, and invokes the LatenessHandler once for each
+	// lateness episode: the handler is not invoked again until a check
+	// finds the next fire on time.
+	LatenessThreshold time.Duration
+
+	// LatenessCheckInterval is the interval at which the lateness
+	// watchdog checks the next fire. When 0, an interval of one
+	// second is used.
+	LatenessCheckInterval time.Duration
+
+	// LatenessHandler is invoked with the late Job and its lateness
+	// once the LatenessThreshold is crossed. When nil, the late jobs
+	// are logged using LogLateness.
+	LatenessHandler func(job ScheduledJob, lateBy time.Duration)
+
+	// QuietPeriods are the windows during which the jobs are not
+	// dispatched. The fires which come due during a quiet period
+	// are held until it ends, and are then handled according to the
+	// MisfirePolicy, being as late as they were held. The periods
+	// with an invalid range are ignored. Jobs scheduled using the
+	// WithIgnoreQuietPeriod option are dispatched regardless.
+	QuietPeriods []QuietPeriod
+
+	// RateLimiter, when set, caps the rate of the job executions
+	// across the StdScheduler. The fires held back by the
+	// RateLimiter are executed late, without blocking the
+	// execution loop or being considered outdated. With
+	// BlockingExecution, the execution loop waits for the
+	// RateLimiter as it waits for the jobs to return.
+	RateLimiter Limiter
+
+	// LockProvider, when set, is used to acquire a lock named
+	// after the key of the Job before each execution, so that the
+	// StdSchedulers of multiple instances sharing the jobs do not
+	// execute the same fire. The fires whose lock is held by
+	// another instance, or could not be acquired, are skipped.
+	// The lock is renewed while the Job is executing if the
+	// LockProvider implements the LockRenewer interface; once it
+	// is lost, the execution context is canceled. Unless the
+	// outdated check is disabled, the lock is held until the fire
+	// becomes outdated.
+	LockProvider LockProvider
+
+	// LockTTL is the TTL of the locks acquired from the
+	// LockProvider. A Job executing longer than the LockTTL
+	// requires a LockProvider implementing the LockRenewer
+	// interface, the lock is renewed every half of the LockTTL.
+	// When 0, a TTL of one minute is used.
+	LockTTL time.Duration
+
+	// JobWrappers are applied around each Job when it is
+	// dispatched for execution, e.g. to add logging or metrics to
+	// all of the jobs. The first wrapper is the outermost one: it
+	// is executed first and returns last. The wrappers receive the
+	// context carrying the ExecutionContext. The key of a wrapping
+	// Job does not change the identity of the scheduled Job, while
+	// its description is used to report the execution errors.
+	JobWrappers []func(Job) Job
+
+	// ExecutionHook, when set, is invoked before each execution
+	// of the jobs, in all of the dispatch modes, e.g. to start a
+	// tracing span of the fire. The context it returns, derived
+	// from the context carrying the ExecutionContext, is passed
+	// to the JobWrappers and to Execute. The returned finish
+	// function, if not nil, is invoked with the outcome once the
+	// execution returns.
+	ExecutionHook func(ctx context.Context, job ScheduledJob) (context.Context, func(err error))
+
+	// TriggerRetryLimit is the number of times the next fire time
+	// of a Job is computed again, every TriggerRetryInterval, when
+	// its Trigger returns an error other than ErrTriggerExpired,
+	// before the Job is unscheduled. The Job is not executed while
+	// its Trigger is retried. When 0, the Job is unscheduled on
+	// the first error. A Job whose Trigger is expired is always
+	// unscheduled at once.
+	TriggerRetryLimit int
+
+	// TriggerRetryInterval is the delay between the retries of a
+	// failed Trigger. When 0, a delay of one second is used.
+	TriggerRetryInterval time.Duration
+
+	// HistorySize, when greater than 0, is the number of the last
+	// fires of each Job kept in its execution history, returned
+	// by GetJobHistory. The history of a Job is kept while it is
+	// scheduled, including when it is rescheduled or replaced.
+	HistorySize int
+
+	// EventBufferSize is the capacity of the channel returned by
+	// Events. When 0, a capacity of 64 events is used.
+	EventBufferSize int
+
+	// OnStart are the jobs executed once each time the scheduler
+	// is started, in order, along with the fires of the scheduled
+	// jobs. They are executed even if the scheduler is stopped in
+	// the meantime, with the context of the stopped run, and before
+	// the OnStop jobs. Their keys are in the OnStartGroup.
+	OnStart []Job
+
+	// OnStop are the jobs executed once each time the scheduler
+	// is stopped, in order, after the fires of the scheduled jobs
+	// have ceased. Wait returns once they have returned. Their
+	// keys are in the OnStopGroup.
+	OnStop []Job
+
+	// OnStopTimeout bounds the execution of the OnStop jobs when
+	// the scheduler is stopped by Stop or by its context, while
+	// Shutdown bounds them by its own context. When 0, a timeout
+	// of 10 seconds is used.
+	OnStopTimeout time.Duration
+
+	// Queue is the JobQueue holding the scheduled jobs. When nil,
+	// an in-memory queue returned by NewJobQueue is used. Jobs
+	// found in the Queue which were not scheduled by the
+	// StdScheduler are executed with the default options. When an
+	// operation of the Queue fails, the error is logged and the
+	// execution loop backs off for a second before accessing the
+	// Queue again. The jobs which could not be pushed to the Queue
+	// remain scheduled and their push is retried, while failures
+	// of the operations requested by the caller, such as DeleteJob,
+	// are returned to the caller.
+	Queue JobQueue
+
+	// Clock is the time source used to fire the jobs. When nil,
+	// the system time is used.
+	Clock Clock
+
+	// Metrics receives the metrics of the scheduler internals,
+	// such as the executed jobs and the length of the JobQueue.
+	// When nil, the metrics are discarded.
+	Metrics Metrics
+
+	// Logger is used to report the internal events of the
+	// scheduler. When nil, the messages are written using the
+	// standard library log package.
+	Logger Logger
 }
 
 // Verify StdScheduler satisfies the Scheduler interface.
@@ -87,194 +576,893 @@ var _ Scheduler = (*StdScheduler)(nil)
 
 // NewStdScheduler returns a new StdScheduler with the default configuration.
 func NewStdScheduler() Scheduler {
-	return NewStdSchedulerWithOptions(StdSchedulerOptions{})
+	return NewStdSchedulerWithOptions(StdSchedulerOptions{
+		OutdatedThreshold:  DefaultOutdatedThreshold,
+		ClockCheckInterval: DefaultClockCheckInterval,
+	})
 }
 
 // NewStdSchedulerWithOptions returns a new StdScheduler configured as specified.
 func NewStdSchedulerWithOptions(opts StdSchedulerOptions) *StdScheduler {
+	if opts.Logger == nil {
+		opts.Logger = NewStdLogger(nil)
+	}
+	if opts.Clock == nil {
+		opts.Clock = NewRealClock()
+	}
+	if opts.TriggerRetryInterval == 0 {
+		opts.TriggerRetryInterval = defaultTriggerRetryInterval
+	}
+	if opts.EventBufferSize <= 0 {
+		opts.EventBufferSize = defaultEventBufferSize
+	}
+	if opts.Queue == nil {
+		opts.Queue = NewJobQueue()
+	}
+	if opts.Metrics == nil {
+		opts.Metrics = NewNoopMetrics()
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = defaultLockTTL
+	}
+	if opts.OnStopTimeout <= 0 {
+		opts.OnStopTimeout = defaultOnStopTimeout
+	}
+	if opts.ClockJumpThreshold <= 0 {
+		opts.ClockJumpThreshold = defaultClockJumpThreshold
+	}
+	if opts.DispatchBatchSize <= 0 {
+		opts.DispatchBatchSize = defaultDispatchBatchSize
+	}
+	if opts.LatenessCheckInterval <= 0 {
+		opts.LatenessCheckInterval = defaultLatenessCheckInterval
+	}
+	if opts.LatenessHandler == nil {
+		opts.LatenessHandler = LogLateness(opts.Logger)
+	}
+	pools := make(map[string]int, len(opts.Pools))
+	for name, limit := range opts.Pools {
+		if limit > 0 {
+			pools[name] = limit
+		}
+	}
+	opts.Pools = pools
+
 	return &StdScheduler{
-		queue:     &priorityQueue{},
-		wg:        &sync.WaitGroup{},
-		interrupt: make(chan time.Time, 1),
-		feeder:    make(chan *item),
-		dispatch:  make(chan *item),
-		opts:      opts,
+		quiet:       quietCalendars(opts.QuietPeriods),
+		queue:       opts.Queue,
+		wg:          &waitGroup{},
+		interrupt:   make(chan time.Time, 1),
+		feeder:      make(chan *item, opts.FeederBuffer),
+		dispatch:    make(chan *fire, opts.DispatchQueueSize),
+		immediate:   make(chan *fire),
+		inflight:    make(map[*item]struct{}),
+		pending:     make(map[*item]struct{}),
+		index:       make(map[JobKey]*item),
+		running:     make(map[JobKey]map[*fire]struct{}),
+		waiters:     make(map[JobKey][]chan error),
+		workerLimit: opts.WorkerLimit,
+		opts:        opts,
 	}
 }
 
 // ScheduleJob schedules a Job using a specified Trigger.
-func (sched *StdScheduler) ScheduleJob(ctx context.Context, job Job, trigger Trigger) error {
-	nextRunTime, err := trigger.NextFireTime(NowNano())
+// Jobs scheduled before the StdScheduler is started are queued and
+// begin to fire once Start is called.
+func (sched *StdScheduler) ScheduleJob(
+	ctx context.Context,
+	job Job,
+	trigger Trigger,
+	opts ...ScheduleOption,
+) error {
+	return sched.ScheduleJobWithKey(ctx, intJobKey(job.Key()), job, trigger, opts...)
+}
+
+// ScheduleJobWithKey schedules a Job identified by the JobKey using a
+// specified Trigger. Jobs with the same name in different groups are
+// scheduled independently. If a Job with the same key is already
+// scheduled, ErrJobAlreadyExists is returned, unless the
+// WithReplaceExisting option is used.
+func (sched *StdScheduler) ScheduleJobWithKey(
+	ctx context.Context,
+	key JobKey,
+	job Job,
+	trigger Trigger,
+	opts ...ScheduleOption,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sched.mtx.Lock()
+	collision := sched.collision(key, job)
+	scheduled, err := sched.schedule(key, job, trigger, newScheduleOptions(opts))
+	if err == nil {
+		sched.reportQueueLength()
+		if sched.isRunning() {
+			sched.resetHead()
+		}
+	}
+	sched.mtx.Unlock()
+	if collision != nil {
+		sched.notify(func(l SchedulerListener) { l.JobKeyCollision(*collision, job) })
+	}
 	if err != nil {
 		return err
 	}
 
-	select {
-	case sched.feeder <- &item{
+	sched.opts.Metrics.IncCounter(MetricJobsScheduled)
+	sched.notify(func(l SchedulerListener) { l.JobScheduled(scheduled) })
+	return nil
+}
+
+// collision returns a snapshot of the scheduled Job sharing the key of the
+// Job to schedule, if their descriptions differ, as the distinct jobs
+// whose keys collide would replace or reject each other. The caller must
+// hold the lock.
+func (sched *StdScheduler) collision(key JobKey, job Job) *ScheduledJob {
+	existing := sched.findItem(NewJobKeyWithGroup(key.Name, key.Group))
+	if existing == nil || existing.Job.Description() == job.Description() {
+		return nil
+	}
+
+	sched.opts.Logger.Error("The Job key is shared by different jobs",
+		"key", existing.key,
+		"description", job.Description(),
+		"scheduled_description", existing.Job.Description(),
+	)
+	return existing.scheduledJob()
+}
+
+// schedule queues and indexes a new item of the Job, and returns its
+// ScheduledJob snapshot. The caller must hold the lock, and has to wake
+// up the execution loop.
+func (sched *StdScheduler) schedule(
+	key JobKey,
+	job Job,
+	trigger Trigger,
+	options scheduleOptions,
+) (ScheduledJob, error) {
+	if _, ok := sched.opts.Pools[options.pool]; options.pool != "" && !ok {
+		return ScheduledJob{}, ErrPoolNotFound
+	}
+	it := sched.newItem(NewJobKeyWithGroup(key.Name, key.Group), job, trigger, options)
+
+	// the duplicate check covers the items in flight, so it has
+	// to happen under the lock along with the push, and before
+	// the Trigger is advanced, so that a rejected Trigger can be
+	// scheduled again
+	existing := sched.findItem(it.key)
+	if existing != nil && !options.replace {
+		return ScheduledJob{}, ErrJobAlreadyExists
+	}
+	if existing == nil && sched.queueFull(1) {
+		return ScheduledJob{}, ErrQueueFull
+	}
+
+	it.priority = sched.nowNano()
+	switch {
+	case options.nextRunTime != 0:
+		if options.expired(options.bounded(options.nextRunTime)) {
+			return ScheduledJob{}, ErrJobExpired
+		}
+		sched.setPriority(it, options.nextRunTime)
+	case !options.startNow:
+		from := it.priority
+		if last := options.lastFireTime; !last.IsZero() && last.UnixNano() < from {
+			from = last.UnixNano()
+		}
+		nextRunTime, err := trigger.NextFireTime(from)
+		if err != nil {
+			return ScheduledJob{}, err
+		}
+		if options.expired(options.bounded(nextRunTime)) {
+			return ScheduledJob{}, ErrJobExpired
+		}
+		sched.setPriority(it, nextRunTime)
+	default:
+		// the immediate fire is not jittered
+		it.priority = options.bounded(it.priority)
+		if options.expired(it.priority) {
+			return ScheduledJob{}, ErrJobExpired
+		}
+	}
+	scheduled := *it.scheduledJob()
+
+	if existing != nil {
+		// the waiters of the replaced Job wait for the new one
+		waiters := sched.waiters[it.key]
+		delete(sched.waiters, it.key)
+		err := sched.remove(existing)
+		if len(waiters) > 0 {
+			sched.waiters[it.key] = waiters
+		}
+		if err != nil {
+			return ScheduledJob{}, err
+		}
+		it.history = existing.history
+	}
+	err := sched.queue.Push(it.queuedJob())
+	if errors.Is(err, ErrJobAlreadyExists) && options.replace {
+		// the Job is queued but not indexed, e.g. a Job of a
+		// PersistentJobQueue which has not been reloaded yet
+		if _, err = sched.queue.Remove(it.key); err == nil {
+			err = sched.queue.Push(it.queuedJob())
+		}
+	}
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	sched.index[it.key] = it
+	sched.registerWakeup(it, trigger)
+	if sched.isRunning() {
+		sched.dispatchCatchUp(sched.catchUp(it, sched.nowNano()))
+		scheduled = *it.scheduledJob()
+	}
+
+	return scheduled, nil
+}
+
+// queueFull reports whether scheduling n new jobs would exceed the
+// MaxQueueSize. The caller must hold the lock.
+func (sched *StdScheduler) queueFull(n int) bool {
+	return sched.opts.MaxQueueSize > 0 && len(sched.index)+n > sched.opts.MaxQueueSize
+}
+
+// newItem returns a new item of the Job, configured by the options.
+func (sched *StdScheduler) newItem(key JobKey, job Job, trigger Trigger, options scheduleOptions) *item {
+	if options.timeout == 0 {
+		options.timeout = sched.opts.JobTimeout
+	}
+	if !options.misfireSet {
+		options.misfire = sched.opts.MisfirePolicy
+	}
+	if !options.catchUpSet {
+		options.catchUp = sched.opts.CatchUpPolicy
+	}
+
+	it := &item{
 		Job:      job,
 		Trigger:  trigger,
-		priority: nextRunTime,
-		index:    0,
-	}:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
+		key:      key,
+		opts:     options,
+		paused:   options.paused,
+		startNow: options.startNow,
+	}
+	switch {
+	case options.maxRunning > 0:
+		it.sem = make(chan struct{}, options.maxRunning)
+	case options.concurrency != ConcurrencyAllow:
+		it.sem = make(chan struct{}, 1)
+	}
+	if sched.opts.HistorySize > 0 {
+		it.history = newExecutionHistory(sched.opts.HistorySize)
+	}
+
+	return it
+}
+
+// ScheduleFunc schedules the function to be invoked using a specified
+// Trigger. It returns the generated key of the Job, which can be used
+// to manage the Job once scheduled.
+func (sched *StdScheduler) ScheduleFunc(
+	ctx context.Context,
+	function func(context.Context) error,
+	trigger Trigger,
+	opts ...ScheduleOption,
+) (int, error) {
+	job := &funcJob{
+		function: function,
+		key:      sched.generateKey(),
+	}
+	if err := sched.ScheduleJob(ctx, job, trigger, opts...); err != nil {
+		return 0, err
+	}
+
+	return job.key, nil
+}
+
+// ScheduleOnceAt schedules the function to be invoked once at the
+// specified time, or immediately if the time is in the past. It returns
+// the generated key of the Job.
+func (sched *StdScheduler) ScheduleOnceAt(
+	ctx context.Context,
+	function func(context.Context) error,
+	at time.Time,
+	opts ...ScheduleOption,
+) (int, error) {
+	trigger := NewRunOnceTriggerAt(at)
+	trigger.FireIfPast = true
+	return sched.ScheduleFunc(ctx, function, trigger, opts...)
+}
+
+// ScheduleAt schedules the Job to be executed once at the specified
+// time. ErrFireTimeInPast is returned if the time is in the past.
+func (sched *StdScheduler) ScheduleAt(
+	ctx context.Context,
+	job Job,
+	at time.Time,
+	opts ...ScheduleOption,
+) error {
+	return sched.ScheduleJob(ctx, job, NewRunOnceTriggerAt(at), opts...)
+}
+
+// generateKey returns a new Job key, which is not used by any of the
+// scheduled jobs.
+func (sched *StdScheduler) generateKey() int {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	for {
+		sched.lastKey++
+		if sched.findItem(intJobKey(sched.lastKey)) == nil {
+			return sched.lastKey
+		}
 	}
 }
 
-// Start starts the StdScheduler execution loop.
+// Start starts the StdScheduler execution loop. A stopped
+// StdScheduler can be started again, resuming the execution of the
+// jobs remaining in the queue. Starting a StdScheduler that is already
+// running has no effect, use StartErr to tell.
 func (sched *StdScheduler) Start(ctx context.Context) {
+	_ = sched.StartErr(ctx)
+}
+
+// StartErr starts the StdScheduler execution loop, as Start does, and
+// returns ErrSchedulerAlreadyStarted if the StdScheduler is running, or
+// the error of reloading the jobs of a PersistentJobQueue.
+//
+// The first Start reloads the jobs of a PersistentJobQueue, which were
+// not scheduled since the StdScheduler was created. Their fire times
+// which passed while the process was down are handled according to the
+// MisfirePolicy. The jobs are reloaded with the default options.
+//
+// The context passed to Execute always derives from the given context,
+// in all of the dispatch modes, so that its values are available to the
+// jobs. The derivation chain of each fire is: the jobs context of the
+// run, canceled by Stop and once the given context is done; the
+// ScheduledJob of ScheduledJobFromContext, and the cancellation of
+// CancelRunningJob; the deadline of the Job timeout or of the next fire,
+// if any; the ExecutionContext; and the context returned by the
+// ExecutionHook, if any. The OnStop jobs, which are executed once the
+// run is stopped, carry the values of the given context, bounded by the
+// OnStopTimeout or the context passed to Shutdown instead of its
+// cancellation.
+func (sched *StdScheduler) StartErr(ctx context.Context) error {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	if sched.started {
-		return
+	if sched.isRunning() {
+		select {
+		case <-sched.done:
+			// the context of the previous run is done, but
+			// the run has not been stopped yet
+			sched.stop()
+		default:
+			return ErrSchedulerAlreadyStarted
+		}
+	}
+	if !sched.reloaded {
+		if err := sched.reload(); err != nil {
+			return err
+		}
+		sched.reloaded = true
 	}
 
+	// the jobs context is separate from the loop context, so
+	// that running jobs can be drained by Shutdown
+	sched.startCtx = ctx
+	jobCtx, cancelJobs := context.WithCancel(ctx)
 	ctx, sched.cancel = context.WithCancel(ctx)
-	go func() { <-ctx.Done(); sched.Stop() }()
+	sched.cancelJobs = cancelJobs
+	sched.done = ctx.Done()
+	go sched.stopOnDone(sched.done)
+
 	// start the feed reader
 	sched.wg.Add(1)
 	go sched.startFeedReader(ctx)
 
 	// start scheduler execution loop
 	sched.wg.Add(1)
-	go sched.startExecutionLoop(ctx)
+	go sched.startExecutionLoop(ctx, jobCtx)
+
+	// start the lateness watchdog
+	if sched.opts.LatenessThreshold > 0 {
+		sched.wg.Add(1)
+		go sched.watchLateness(ctx)
+	}
 
 	// starts worker pool when WorkerLimit is > 0
+	sched.drainDispatch()
 	sched.startWorkers(ctx)
+	sched.startHooks(jobCtx)
+	sched.dispatchCatchUp(sched.catchUpAll())
+
+	sched.setState(stateRunning)
+	return nil
+}
+
+// reload indexes the jobs of a PersistentJobQueue which are not scheduled
+// by the StdScheduler. The caller must hold the lock.
+func (sched *StdScheduler) reload() error {
+	queue, ok := sched.queue.(PersistentJobQueue)
+	if !ok {
+		return nil
+	}
 
-	sched.started = true
+	jobs, err := queue.Jobs()
+	if err != nil {
+		return err
+	}
+	for _, queued := range jobs {
+		if _, ok := sched.index[queued.Key]; !ok {
+			sched.adopt(queued)
+		}
+	}
+
+	return nil
 }
 
 // Wait blocks until the scheduler shuts down.
 func (sched *StdScheduler) Wait(ctx context.Context) {
-	sig := make(chan struct{})
-	go func() { defer close(sig); sched.wg.Wait() }()
+	_ = sched.WaitErr(ctx)
+}
+
+// WaitErr blocks until the scheduler shuts down and all of its jobs
+// have returned, and returns nil. If the context expires first, WaitErr
+// returns the context error.
+func (sched *StdScheduler) WaitErr(ctx context.Context) error {
 	select {
+	case <-sched.wg.Idle():
+		return nil
 	case <-ctx.Done():
-	case <-sig:
+		return ctx.Err()
 	}
 }
 
 // IsStarted determines whether the scheduler has been started.
+// It is safe to call concurrently with Start and Stop.
 func (sched *StdScheduler) IsStarted() bool {
-	return sched.started
+	return sched.isRunning()
+}
+
+// isRunning reads the lifecycle state atomically.
+func (sched *StdScheduler) isRunning() bool {
+	return atomic.LoadInt32(&sched.state) == stateRunning
+}
+
+// setState updates the lifecycle state. The caller must hold the lock.
+func (sched *StdScheduler) setState(state int32) {
+	atomic.StoreInt32(&sched.state, state)
 }
 
 // GetJobKeys returns the keys of all of the scheduled jobs.
+// Jobs scheduled with a JobKey that does not correspond to an integer
+// key in the DefaultGroup are not included, use GetJobKeysWithGroup
+// to list them.
 func (sched *StdScheduler) GetJobKeys() []int {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	keys := make([]int, 0, sched.queue.Len())
-	for _, item := range *sched.queue {
-		keys = append(keys, item.Job.Key())
+	keys := make([]int, 0, len(sched.index))
+	for _, item := range sched.items() {
+		if key, ok := item.key.intKey(); ok {
+			keys = append(keys, key)
+		}
 	}
 
 	return keys
 }
 
-// GetScheduledJob returns the ScheduledJob with the specified key.
-func (sched *StdScheduler) GetScheduledJob(key int) (*ScheduledJob, error) {
+// GetJobKeysWithGroup returns the keys of the scheduled jobs in the
+// specified group.
+func (sched *StdScheduler) GetJobKeysWithGroup(group string) []JobKey {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	for _, item := range *sched.queue {
-		if item.Job.Key() == key {
-			return &ScheduledJob{
-				Job:                item.Job,
-				TriggerDescription: item.Trigger.Description(),
-				NextRunTime:        item.priority,
-			}, nil
+	var keys []JobKey
+	for _, item := range sched.items() {
+		if item.key.Group == group {
+			keys = append(keys, item.key)
 		}
 	}
 
-	return nil, errors.New("no Job with the given Key found")
+	return keys
 }
 
-// DeleteJob removes the Job with the specified key if present.
-func (sched *StdScheduler) DeleteJob(key int) error {
+// GetScheduledJob returns the ScheduledJob with the specified key. The
+// state of a StatefulTrigger is read from the Trigger held by the queue,
+// under the lock it is advanced under.
+func (sched *StdScheduler) GetScheduledJob(key int) (*ScheduledJob, error) {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	for i, item := range *sched.queue {
-		if item.Job.Key() == key {
-			sched.queue.Remove(i)
-			return nil
-		}
+	if item := sched.findItem(intJobKey(key)); item != nil {
+		return item.scheduledJob(), nil
 	}
 
-	return errors.New("no Job with the given Key found")
+	return nil, ErrJobNotFound
 }
 
-// Clear removes all of the scheduled jobs.
-func (sched *StdScheduler) Clear() {
+// GetScheduledJobs returns a snapshot of all of the scheduled jobs,
+// sorted by the next run time in ascending order.
+func (sched *StdScheduler) GetScheduledJobs() []*ScheduledJob {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	jobs := make([]*ScheduledJob, 0, len(sched.index))
+	for _, item := range sched.items() {
+		jobs = append(jobs, item.scheduledJob())
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].NextRunTime < jobs[j].NextRunTime
+	})
+
+	return jobs
+}
+
+// JobCount returns the number of the scheduled jobs, including the jobs
+// in flight.
+func (sched *StdScheduler) JobCount() int {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	// reset the job queue
-	sched.queue = &priorityQueue{}
+	return len(sched.index)
 }
 
-// Stop exits the StdScheduler execution loop.
-func (sched *StdScheduler) Stop() {
+// NextRunTime returns the earliest next run time of the scheduled jobs,
+// or false if there are none. A Job popped from the queue for its fire
+// keeps the run time of that fire until it is rescheduled, so that
+// NextRunTime never reports a later time than the actual head of the
+// schedule, and may be in the past while the fire is dispatched.
+func (sched *StdScheduler) NextRunTime() (time.Time, bool) {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	if !sched.started {
-		return
+	var next int64
+	found := false
+	earliest := func(runTime int64) {
+		if !found || runTime < next {
+			next, found = runTime, true
+		}
+	}
+	if head, err := sched.queue.Head(); err == nil {
+		earliest(head.NextRunTime)
+	}
+	for it := range sched.inflight {
+		earliest(it.priority)
+	}
+	for it := range sched.pending {
+		earliest(it.priority)
+	}
+	if !found {
+		return time.Time{}, false
 	}
 
-	log.Printf("Closing the StdScheduler.")
-	sched.cancel()
-	sched.started = false
+	return time.Unix(0, next), true
 }
 
-func (sched *StdScheduler) startExecutionLoop(ctx context.Context) {
-	defer sched.wg.Done()
-
-	t := time.NewTimer(0)
-	defer t.Stop()
+// DeleteJob removes the Job with the specified key if present. A Job
+// which is executing is not scheduled again once its execution returns.
+func (sched *StdScheduler) DeleteJob(key int) error {
+	return sched.DeleteJobWithKey(intJobKey(key))
+}
 
-	for {
-		if sched.queueLen() == 0 {
-			select {
-			case nextJobAt := <-sched.interrupt:
-				safeSetTimer(t, nextJobAt)
-			case <-ctx.Done():
-				log.Printf("Exit the empty execution loop.")
-				return
-			}
-			continue
-		}
-		select {
-		case <-t.C:
-			sched.executeAndReschedule(ctx)
-			safeSetTimer(t, sched.calculateNextTick())
-		case nextJobAt := <-sched.interrupt:
-			safeSetTimer(t, nextJobAt)
-		case <-ctx.Done():
-			log.Printf("Exit the execution loop.")
-			return
-		}
+// DeleteJobWithKey removes the Job identified by the JobKey if present,
+// as DeleteJob does.
+func (sched *StdScheduler) DeleteJobWithKey(key JobKey) error {
+	key = NewJobKeyWithGroup(key.Name, key.Group)
+	if err := sched.deleteJob(key); err != nil {
+		return err
 	}
+
+	sched.notify(func(l SchedulerListener) { l.JobDeleted(key) })
+	return nil
 }
 
-func safeSetTimer(timer *time.Timer, next time.Time) {
-	// reset/stop the timer
-	if !timer.Stop() {
-		// drain if needed
-		select {
-		case <-timer.C:
-		default:
-		}
+func (sched *StdScheduler) deleteJob(key JobKey) error {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
 
+	item := sched.findItem(key)
+	if item == nil {
+		return ErrJobNotFound
 	}
 
+	// an item in flight is marked as removed, so that it is
+	// dropped instead of being queued again once it returns
+	if err := sched.remove(item); err != nil {
+		return err
+	}
+	sched.resetHead()
+	return nil
+}
+
+// DeleteJobGroup removes all of the jobs in the specified group and
+// returns the number of removed jobs. The executing jobs are not
+// scheduled again once their executions return.
+func (sched *StdScheduler) DeleteJobGroup(group string) int {
+	keys := sched.deleteJobGroup(group)
+	for _, key := range keys {
+		key := key
+		sched.notify(func(l SchedulerListener) { l.JobDeleted(key) })
+	}
+
+	return len(keys)
+}
+
+func (sched *StdScheduler) deleteJobGroup(group string) []JobKey {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	var keys []JobKey
+	for _, item := range sched.items() {
+		if item.key.Group != group {
+			continue
+		}
+		if err := sched.remove(item); err != nil {
+			sched.queueFailed("remove", err, "key", item.key)
+			continue
+		}
+		keys = append(keys, item.key)
+	}
+	if len(keys) > 0 {
+		sched.resetHead()
+	}
+
+	return keys
+}
+
+// PauseGroup pauses all of the jobs in the specified group and returns
+// the number of affected jobs. Paused jobs remain scheduled, but their
+// fires are skipped until the group is resumed.
+func (sched *StdScheduler) PauseGroup(group string) int {
+	return sched.setGroupPaused(group, true)
+}
+
+// ResumeGroup resumes all of the paused jobs in the specified group
+// and returns the number of affected jobs. The fires missed while the
+// jobs were paused are caught up according to their CatchUpPolicy.
+func (sched *StdScheduler) ResumeGroup(group string) int {
+	return sched.setGroupPaused(group, false)
+}
+
+func (sched *StdScheduler) setGroupPaused(group string, paused bool) int {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	var count int
+	var fires []*fire
+	now, running := sched.nowNano(), sched.isRunning()
+	for _, item := range sched.items() {
+		if item.key.Group == group && item.paused != paused {
+			item.paused = paused
+			count++
+			if !paused && running {
+				fires = append(fires, sched.catchUp(item, now)...)
+			}
+		}
+	}
+	if !paused && running && count > 0 {
+		sched.resetHead()
+		sched.dispatchCatchUp(sortFires(fires))
+	}
+
+	return count
+}
+
+// RescheduleJob replaces the Trigger of the Job with the specified key
+// and recalculates its next run time. If the Job is being executed at
+// the moment, the new Trigger takes effect once the Job is returned to
+// the execution queue.
+func (sched *StdScheduler) RescheduleJob(ctx context.Context, key int, trigger Trigger) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nextRunTime, err := trigger.NextFireTime(sched.nowNano())
+	if err != nil {
+		return err
+	}
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	item := sched.findItem(intJobKey(key))
+	if item == nil {
+		return ErrJobNotFound
+	}
+	if item.opts.expired(item.opts.bounded(nextRunTime)) {
+		return ErrJobExpired
+	}
+
+	// a retry of the previous Trigger is abandoned
+	item.triggerRetry = false
+	item.triggerRetries = 0
+	if _, ok := sched.inflight[item]; ok {
+		// the item was popped from the queue by the execution
+		// loop and will be returned by the feed reader
+		item.Trigger = trigger
+		item.jitterBase, item.held = 0, 0
+		sched.setPriority(item, nextRunTime)
+		item.rescheduled = true
+		sched.registerWakeup(item, trigger)
+		return nil
+	}
+
+	if err := sched.dequeue(item); err != nil {
+		return err
+	}
+	item.Trigger = trigger
+	item.jitterBase, item.held = 0, 0
+	sched.setPriority(item, nextRunTime)
+	sched.registerWakeup(item, trigger)
+	sched.push(item)
+	sched.resetHead()
+	return nil
+}
+
+// Clear removes all of the scheduled jobs, notifying the listeners of
+// their deletion. The executing jobs are not scheduled again once their
+// executions return. The execution loop waiting on a removed job is
+// woken up to move to its idle state.
+func (sched *StdScheduler) Clear() {
+	sched.mtx.Lock()
+	if err := sched.queue.Clear(); err != nil {
+		sched.queueFailed("clear", err)
+		sched.mtx.Unlock()
+		return
+	}
+
+	// the items in flight are removed along with the queued ones,
+	// so that they are dropped once returned
+	cleared := sched.items()
+	for _, item := range cleared {
+		delete(sched.pending, item)
+		sched.drop(item)
+	}
+	// the queue is operational again, so a backoff from a
+	// previous failure must not delay the next scheduled job
+	sched.retryAt = time.Time{}
+	sched.reportQueueLength()
+	sched.resetHead()
+	sched.mtx.Unlock()
+
+	for _, item := range cleared {
+		key := item.key
+		sched.notify(func(l SchedulerListener) { l.JobDeleted(key) })
+	}
+}
+
+// Stop exits the StdScheduler execution loop. Stopping a StdScheduler
+// that is not running has no effect, use StopErr to tell.
+func (sched *StdScheduler) Stop() {
+	_ = sched.StopErr()
+}
+
+// StopErr exits the StdScheduler execution loop, as Stop does, and
+// returns ErrSchedulerNotStarted if the StdScheduler was not running,
+// e.g. when it was never started or it was already stopped, in which
+// case it has no effect.
+func (sched *StdScheduler) StopErr() error {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if !sched.isRunning() {
+		return ErrSchedulerNotStarted
+	}
+
+	sched.stop()
+	return nil
+}
+
+// Shutdown gracefully stops the StdScheduler. No new executions are
+// started, while the running jobs are allowed to complete with a live
+// context. Shutdown returns once all of the jobs have returned, or
+// cancels the running jobs and returns the context error if the given
+// context expires first.
+func (sched *StdScheduler) Shutdown(ctx context.Context) error {
+	sched.mtx.Lock()
+	if !sched.isRunning() {
+		sched.mtx.Unlock()
+		return nil
+	}
+
+	sched.opts.Logger.Info("Shutting down the StdScheduler")
+	cancelJobs := sched.cancelJobs
+	sched.cancel()
+	sched.setState(stateStopped)
+	sched.stopHooks(valuesOf(sched.startCtx, ctx), func() {})
+	sched.closeEvents()
+	sched.mtx.Unlock()
+
+	defer cancelJobs()
+	return sched.WaitErr(ctx)
+}
+
+// stopOnDone stops the StdScheduler once the done channel of the run is
+// closed, unless the StdScheduler has been restarted in the meantime.
+func (sched *StdScheduler) stopOnDone(done <-chan struct{}) {
+	<-done
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if sched.isRunning() && sched.done == done {
+		sched.stop()
+	}
+}
+
+// stop cancels the current run. The caller must hold the lock.
+func (sched *StdScheduler) stop() {
+	sched.opts.Logger.Info("Closing the StdScheduler")
+	sched.cancel()
+	sched.cancelJobs()
+	sched.setState(stateStopped)
+
+	sched.stopHooks(context.WithTimeout(valuesOf(sched.startCtx, context.Background()),
+		sched.opts.OnStopTimeout))
+	sched.closeEvents()
+}
+
+func (sched *StdScheduler) startExecutionLoop(ctx, jobCtx context.Context) {
+	defer sched.wg.Done()
+
+	t := sched.opts.Clock.NewTimer(0)
+	defer t.Stop()
+
+	watchdog := newClockWatchdog(sched.opts.Clock, sched.opts.ClockCheckInterval)
+	defer watchdog.stop()
+	defer sched.drainFeeder()
+
+	for {
+		if sched.idle() {
+			// a timer armed for a removed head must not fire once
+			// a job is scheduled again
+			stopTimer(t)
+			select {
+			case nextJobAt := <-sched.interrupt:
+				sched.safeSetTimer(t, nextJobAt)
+			case f := <-sched.immediate:
+				sched.execute(ctx, jobCtx, f)
+			case <-watchdog.C():
+				sched.clockJumped(watchdog.tick())
+			case <-ctx.Done():
+				sched.opts.Logger.Debug("Exit the empty execution loop")
+				return
+			}
+			continue
+		}
+		select {
+		case <-t.C():
+			sched.clockJumped(watchdog.check())
+			sched.executeAndReschedule(ctx, jobCtx)
+			sched.safeSetTimer(t, sched.calculateNextTick())
+		case nextJobAt := <-sched.interrupt:
+			sched.safeSetTimer(t, nextJobAt)
+		case f := <-sched.immediate:
+			sched.execute(ctx, jobCtx, f)
+		case <-watchdog.C():
+			// the timer armed before the jump fires at the wrong
+			// time of the wall clock
+			if sched.clockJumped(watchdog.tick()) {
+				sched.safeSetTimer(t, sched.calculateNextTick())
+			}
+		case <-ctx.Done():
+			sched.opts.Logger.Debug("Exit the execution loop")
+			return
+		}
+	}
+}
+
+func (sched *StdScheduler) safeSetTimer(timer Timer, next time.Time) {
+	stopTimer(timer)
+
 	// if the "next" time is in the future, we reset the timer to
 	// this point.
-	if wait := time.Until(next); wait >= 0 {
+	if wait := next.Sub(sched.opts.Clock.Now()); wait >= 0 {
 		timer.Reset(wait)
 		return
 	}
@@ -282,125 +1470,1213 @@ func safeSetTimer(timer *time.Timer, next time.Time) {
 	timer.Reset(0)
 }
 
-func (sched *StdScheduler) startWorkers(ctx context.Context) {
-	if sched.opts.WorkerLimit > 0 {
-		for i := 0; i < sched.opts.WorkerLimit; i++ {
-			sched.wg.Add(1)
-			go func() {
-				defer sched.wg.Done()
-				for {
-					select {
-					case <-ctx.Done():
-						return
-					case item := <-sched.dispatch:
-						item.Job.Execute(ctx)
-					}
-				}
-			}()
+// stopTimer stops the timer, draining its channel if it has fired.
+func stopTimer(timer Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C():
+		default:
 		}
 	}
 }
 
-func (sched *StdScheduler) queueLen() int {
+// idle reports whether there are no queued jobs, including the jobs
+// whose push to the queue is pending.
+func (sched *StdScheduler) idle() bool {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	return sched.queue.Len()
+	return len(sched.pending) == 0 && sched.queue.Len() == 0
 }
 
 func (sched *StdScheduler) calculateNextTick() time.Time {
 	sched.mtx.Lock()
 	defer sched.mtx.Unlock()
 
-	if sched.queue.Len() > 0 {
-		return time.Unix(0, sched.queue.Head().priority)
+	return sched.nextTick()
+}
+
+// nextTick returns the next run time of the head of the queue, or the
+// current time if the queue is empty or there are pending pushes. While
+// backing off after a failed queue operation, the end of the backoff is
+// returned. The caller must hold the lock.
+func (sched *StdScheduler) nextTick() time.Time {
+	now := sched.opts.Clock.Now()
+	if now.Before(sched.retryAt) {
+		return sched.retryAt
+	}
+	if len(sched.pending) > 0 {
+		return now
+	}
+
+	head, err := sched.queue.Head()
+	switch {
+	case err == nil:
+		return time.Unix(0, head.NextRunTime)
+	case errors.Is(err, ErrQueueEmpty):
+		return now
+	default:
+		sched.queueFailed("head", err)
+		return sched.retryAt
 	}
+}
+
+// queueFailed reports the failed queue operation and backs off the
+// execution loop. The caller must hold the lock.
+func (sched *StdScheduler) queueFailed(op string, err error, args ...any) {
+	sched.retryAt = sched.opts.Clock.Now().Add(queueRetryInterval)
+	sched.opts.Logger.Error("The JobQueue operation failed",
+		append([]any{"operation", op, "error", err, "retry_in", queueRetryInterval}, args...)...)
+}
+
+// nowNano returns the current Unix time of the configured Clock in
+// nanoseconds.
+func (sched *StdScheduler) nowNano() int64 {
+	return sched.opts.Clock.Now().UnixNano()
+}
+
+// dueFire is an item popped by the execution loop once due, along with
+// the state of its fire.
+type dueFire struct {
+	it  *item
+	job *ScheduledJob
+	ack *queueAck
 
-	return time.Now()
+	startNow, triggerRetry, jumped bool
+
+	// lastDispatch is the dispatch sequence of the previous fire of
+	// the item, zero if it was not dispatched before.
+	lastDispatch uint64
 }
 
-func (sched *StdScheduler) executeAndReschedule(ctx context.Context) {
-	// fetch an item
-	var it *item
-	func() {
+// executeAndReschedule executes a batch of the due jobs, popped from the
+// queue at once, and reschedules them. The items popped once the
+// scheduler is stopping are returned to the queue.
+func (sched *StdScheduler) executeAndReschedule(ctx, jobCtx context.Context) {
+	batch := sched.popDueBatch()
+	if sched.opts.FairDispatch {
+		sort.SliceStable(batch, func(i, j int) bool {
+			return batch[i].lastDispatch < batch[j].lastDispatch
+		})
+	}
+
+	for i, due := range batch {
 		sched.mtx.Lock()
-		defer sched.mtx.Unlock()
-		if sched.queue.Len() == 0 {
-			// return if the job queue is empty
-			return
+		sched.batch = nil
+		if i+1 < len(batch) {
+			// the lateness watchdog checks the fires waiting
+			// for the previous ones to be dispatched
+			sched.batch = batch[i+1:]
 		}
+		sched.mtx.Unlock()
 
-		if next := time.Unix(0, sched.queue.Head().priority); time.Until(next) > 0 {
-			// return early
-			sched.reset(ctx, next)
-			return
+		if ctx.Err() != nil && !due.job.Paused && !due.triggerRetry {
+			sched.requeueDue(due)
+			continue
 		}
-		it = heap.Pop(sched.queue).(*item)
-	}()
+		sched.executeDue(ctx, jobCtx, due)
+	}
+}
+
+// popDueBatch pops up to DispatchBatchSize due items from the queue,
+// under a single lock, so that the jobs due at the same time are
+// dispatched in a single pass of the execution loop. All of the items
+// of a batch are popped before any is dispatched, so that a Job which is
+// rescheduled for now does not get ahead of them.
+func (sched *StdScheduler) popDueBatch() []*dueFire {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	var batch []*dueFire
+	for len(batch) < sched.opts.DispatchBatchSize {
+		due, popped := sched.popDue()
+		if !popped {
+			break
+		}
+		if due != nil {
+			batch = append(batch, due)
+		}
+	}
+	if len(batch) > 0 {
+		sched.reportQueueLength()
+	}
+
+	return batch
+}
+
+// requeueDue returns the due item, which is not executed as the
+// scheduler is stopping, to the queue, so that it fires on the next
+// start.
+func (sched *StdScheduler) requeueDue(due *dueFire) {
+	due.ack.done()
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	due.it.lastDispatch = due.lastDispatch
+	sched.push(due.it)
+}
 
-	// if there isn't actually a job ready to run now, we'll
-	// return early and try again.
+// popDue pops the head of the queue if it is due, and reports whether
+// an item was popped. A nil dueFire is returned for the popped items
+// which are not to be fired, e.g. held until the end of a quiet period.
+// The caller must hold the lock.
+func (sched *StdScheduler) popDue() (*dueFire, bool) {
+	if !sched.retryPending() {
+		return nil, false
+	}
+
+	head, err := sched.queue.Head()
+	if err != nil {
+		// return if the job queue is empty
+		if !errors.Is(err, ErrQueueEmpty) {
+			sched.queueFailed("head", err)
+		}
+		return nil, false
+	}
+
+	if next := time.Unix(0, head.NextRunTime); next.Sub(sched.opts.Clock.Now()) > 0 {
+		// return early
+		sched.reset(next)
+		return nil, false
+	}
+	it, queued := sched.pop()
 	if it == nil {
-		return
+		return nil, queued != nil
 	}
+	due := &dueFire{it: it}
+	if _, ok := sched.queue.(PersistentJobQueue); ok {
+		due.ack = newQueueAck(queued)
+		it.ack = due.ack
+	}
+	sched.inflight[it] = struct{}{}
+	if until, ok := sched.quietUntil(it); ok {
+		// the held fire is neither completed nor executed
+		due.ack.done()
+		sched.hold(it, until)
+		return nil, true
+	}
+	if it.paused && !it.triggerRetry {
+		sched.recordMissed(it)
+	}
+	due.job = it.scheduledJob()
+	if it.held != 0 {
+		// the lateness of the held fire includes the quiet period
+		due.job.NextRunTime = it.held
+	}
+	due.startNow = it.startNow
+	due.triggerRetry = it.triggerRetry
+	due.jumped = it.priority <= sched.jumpedUntil
+	sched.dispatches++
+	due.lastDispatch, it.lastDispatch = it.lastDispatch, sched.dispatches
+
+	return due, true
+}
+
+// executeDue executes the due fire according to the policies of its
+// Job, and reschedules the Job.
+func (sched *StdScheduler) executeDue(ctx, jobCtx context.Context, due *dueFire) {
+	it, job, ack := due.it, due.job, due.ack
+	startNow, triggerRetry, jumped := due.startNow, due.triggerRetry, due.jumped
 
 	// execute the Job
-	if !isOutdated(it.priority) {
-		switch {
-		case sched.opts.BlockingExecution:
-			it.Job.Execute(ctx)
-		case sched.opts.WorkerLimit > 0:
-			select {
-			case sched.dispatch <- it:
-			case <-ctx.Done():
-				return
-			}
-		default:
-			sched.wg.Add(1)
-			go func() {
-				defer sched.wg.Done()
-				it.Job.Execute(ctx)
-			}()
+	now := sched.nowNano()
+	misfired := !startNow && !triggerRetry && (jumped || sched.opts.OutdatedThreshold > 0 &&
+		isOutdated(job.NextRunTime, now, sched.opts.OutdatedThreshold))
+	var executed bool
+	switch {
+	case job.Paused, triggerRetry:
+		// the retry of a failed Trigger is not a fire
+		sched.completeFire(ack)
+	case misfired && job.MisfirePolicy != MisfireFireNow:
+		lateness := time.Duration(now - job.NextRunTime)
+		sched.opts.Logger.Info("Skipping the outdated Job fire",
+			"key", job.Key,
+			"description", job.Job.Description(),
+			"scheduled_time", time.Unix(0, job.NextRunTime),
+			"lateness", lateness,
+		)
+		sched.opts.Metrics.IncCounter(MetricJobsSkippedOutdated)
+		sched.recordSkipped(it, job, "outdated")
+		sched.notify(func(l SchedulerListener) { l.JobSkippedOutdated(*job, lateness) })
+		sched.completeFire(ack)
+	default:
+		f := newFire(it, job)
+		f.misfire = misfired
+		f.ack = ack
+		if it.opts.deadline {
+			f.deadline = sched.lookAhead(it, misfired)
 		}
+		sched.execute(ctx, jobCtx, f)
+		executed = true
 	}
 
 	// reschedule the Job
-	nextRunTime, err := it.Trigger.NextFireTime(it.priority)
-	if err != nil {
-		log.Printf("The Job '%s' got out the execution loop: %q", it.Job.Description(), err.Error())
-		sched.reset(ctx, time.Now().Add(-time.Millisecond))
+	if err := sched.nextRunTime(it, misfired && !job.Paused); err != nil {
+		sched.unscheduled(job, err)
+		if !executed {
+			// no execution is left to release the waiters
+			sched.mtx.Lock()
+			if sched.findItem(job.Key) == nil {
+				sched.releaseWaiters(job.Key, fmt.Errorf("%w: %s", ErrJobDeleted, err))
+			}
+			sched.mtx.Unlock()
+		}
+		sched.reset(sched.opts.Clock.Now().Add(-time.Millisecond))
 		return
 	}
-	it.priority = nextRunTime
 	select {
 	case <-ctx.Done():
+		// the scheduler is stopping, return the item to the
+		// queue so that it is executed on the next start
+		sched.mtx.Lock()
+		sched.push(it)
+		sched.mtx.Unlock()
 	case sched.feeder <- it:
 	}
 }
 
+// TriggerJob executes the Job with the specified key immediately,
+// following the configured execution semantics. The regular schedule
+// of the Job is not affected.
+func (sched *StdScheduler) TriggerJob(ctx context.Context, key int) error {
+	sched.mtx.Lock()
+	if !sched.isRunning() {
+		sched.mtx.Unlock()
+		return ErrSchedulerNotStarted
+	}
+	it := sched.findItem(intJobKey(key))
+	done := sched.done
+	var f *fire
+	if it != nil {
+		job := it.scheduledJob()
+		job.NextRunTime = sched.nowNano()
+		f = newFire(it, job)
+	}
+	sched.mtx.Unlock()
+
+	if f == nil {
+		return ErrJobNotFound
+	}
+
+	select {
+	case sched.immediate <- f:
+		return nil
+	case <-done:
+		return ErrSchedulerNotStarted
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunningJobs returns the keys of the jobs being executed at the moment.
+func (sched *StdScheduler) RunningJobs() []int {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	keys := make([]int, 0, len(sched.running))
+	for jobKey := range sched.running {
+		if key, ok := jobKey.intKey(); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// RunningExecution describes an execution of a Job running at the
+// moment.
+type RunningExecution struct {
+	Key         JobKey
+	Description string
+
+	// ScheduledTime is the time the execution was scheduled to fire at.
+	ScheduledTime time.Time
+
+	// StartTime is the time the execution started.
+	StartTime time.Time
+
+	// Pool is the name of the worker pool executing the Job, empty for
+	// the default worker pool.
+	Pool string
+
+	// Worker is the id of the worker executing the Job within its pool,
+	// starting at 1, or zero if the Job is executed in its own goroutine
+	// or by the execution loop.
+	Worker int
+}
+
+// RunningExecutions returns the executions of the jobs running at the
+// moment, ordered by their start times.
+func (sched *StdScheduler) RunningExecutions() []RunningExecution {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	var executions []RunningExecution
+	for _, fires := range sched.running {
+		for f := range fires {
+			executions = append(executions, RunningExecution{
+				Key:           f.job.Key,
+				Description:   f.job.Job.Description(),
+				ScheduledTime: time.Unix(0, f.job.NextRunTime),
+				StartTime:     f.started,
+				Pool:          f.pool,
+				Worker:        f.worker,
+			})
+		}
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].StartTime.Before(executions[j].StartTime)
+	})
+
+	return executions
+}
+
+// CancelRunningJob cancels the context of the running executions of the
+// Job with the specified key, without affecting its schedule. It
+// returns ErrJobNotFound if the Job is not being executed.
+func (sched *StdScheduler) CancelRunningJob(key int) error {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	fires, ok := sched.running[intJobKey(key)]
+	if !ok {
+		return ErrJobNotFound
+	}
+	for f := range fires {
+		f.cancel()
+	}
+
+	return nil
+}
+
+// track registers the fire as running until the returned function is
+// called, recording its start time.
+func (sched *StdScheduler) track(f *fire) func() {
+	key := f.job.Key
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	f.started = sched.opts.Clock.Now()
+
+	fires, ok := sched.running[key]
+	if !ok {
+		fires = make(map[*fire]struct{})
+		sched.running[key] = fires
+	}
+	fires[f] = struct{}{}
+	f.item.stats.running++
+
+	return func() {
+		sched.mtx.Lock()
+		defer sched.mtx.Unlock()
+
+		f.item.stats.running--
+		delete(fires, f)
+		if len(fires) == 0 {
+			delete(sched.running, key)
+		}
+	}
+}
+
+// findItem returns the item for the Job with the specified key, either
+// from the queue or from the items in flight. The caller must hold the
+// lock.
+func (sched *StdScheduler) findItem(key JobKey) *item {
+	return sched.index[key]
+}
+
+// remove removes the item from the queue, or marks it as removed if
+// the item is in flight, so that it is dropped once returned. The
+// caller must hold the lock.
+func (sched *StdScheduler) remove(it *item) error {
+	if _, ok := sched.inflight[it]; !ok {
+		if err := sched.dequeue(it); err != nil {
+			return err
+		}
+	}
+	sched.drop(it)
+	sched.reportQueueLength()
+	return nil
+}
+
+// drop marks the item, which is no longer queued, as removed, so that
+// it is not queued again once returned by the execution loop if it is
+// in flight, and removes it from the key index. The caller must hold
+// the lock.
+func (sched *StdScheduler) drop(it *item) {
+	delete(sched.inflight, it)
+	it.removed = true
+	sched.unindex(it)
+	sched.requeued(it)
+	sched.releaseWaiters(it.key, ErrJobDeleted)
+}
+
+// dequeue removes the item from the queue, or from the pending pushes.
+// The caller must hold the lock.
+func (sched *StdScheduler) dequeue(it *item) error {
+	if _, ok := sched.pending[it]; ok {
+		delete(sched.pending, it)
+		return nil
+	}
+
+	_, err := sched.queue.Remove(it.key)
+	return err
+}
+
+// pop removes the head of the queue and returns its item along with the
+// popped QueuedJob. The jobs which were not scheduled by the StdScheduler
+// are adopted, while the stale entries of the scheduled jobs are dropped,
+// returning a nil item along with the dropped QueuedJob. The caller must
+// hold the lock.
+func (sched *StdScheduler) pop() (*item, *QueuedJob) {
+	queued, err := sched.queue.Pop()
+	if err != nil {
+		if !errors.Is(err, ErrQueueEmpty) {
+			sched.queueFailed("pop", err)
+		}
+		return nil, nil
+	}
+
+	it, ok := sched.index[queued.Key]
+	if !ok {
+		it = sched.adopt(queued)
+	} else if _, stale := sched.inflight[it]; stale {
+		sched.opts.Logger.Debug("Dropping the stale JobQueue entry", "key", queued.Key)
+		return nil, queued
+	}
+	it.priority = queued.NextRunTime
+
+	return it, queued
+}
+
+// adopt indexes a new item of the queued Job, which was not scheduled by
+// the StdScheduler, using the default options along with the priority, the
+// data and the bounds of the queued Job. The caller must hold the lock.
+func (sched *StdScheduler) adopt(queued *QueuedJob) *item {
+	it := sched.newItem(queued.Key, queued.Job, queued.Trigger, scheduleOptions{
+		priority:   queued.Priority,
+		data:       queued.Data,
+		startTime:  queued.StartTime,
+		expireTime: queued.ExpireTime,
+	})
+	it.priority = queued.NextRunTime
+	sched.index[it.key] = it
+	sched.registerWakeup(it, it.Trigger)
+
+	return it
+}
+
+// completeFire marks the fire of the job popped from a PersistentJobQueue
+// completed, acknowledging the job once its item is queued again.
+func (sched *StdScheduler) completeFire(ack *queueAck) {
+	if !ack.done() {
+		return
+	}
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	sched.acknowledge(ack)
+}
+
+// requeued marks the item popped from a PersistentJobQueue queued again,
+// or dropped, acknowledging the popped job once its fire is completed.
+// The caller must hold the lock.
+func (sched *StdScheduler) requeued(it *item) {
+	if ack := it.ack; ack != nil {
+		it.ack = nil
+		if ack.done() {
+			sched.acknowledge(ack)
+		}
+	}
+}
+
+// acknowledge acknowledges the job popped from the PersistentJobQueue.
+// The caller must hold the lock.
+func (sched *StdScheduler) acknowledge(ack *queueAck) {
+	if err := sched.queue.(PersistentJobQueue).Ack(ack.job); err != nil {
+		sched.queueFailed("ack", err, "key", ack.job.Key)
+	}
+}
+
+// retryPending retries pushing the items which the queue failed to
+// accept, unless backing off. It reports whether the queue can be
+// accessed. The caller must hold the lock.
+func (sched *StdScheduler) retryPending() bool {
+	if sched.opts.Clock.Now().Before(sched.retryAt) {
+		return false
+	}
+	for it := range sched.pending {
+		if err := sched.queue.Push(it.queuedJob()); err != nil {
+			sched.queueFailed("push", err, "key", it.key)
+			return false
+		}
+		delete(sched.pending, it)
+		sched.requeued(it)
+	}
+
+	return true
+}
+
+// unindex removes the item from the key index, unless the key has been
+// taken over by another item. The caller must hold the lock.
+func (sched *StdScheduler) unindex(it *item) {
+	if sched.index[it.key] == it {
+		delete(sched.index, it.key)
+	}
+}
+
+// items returns the scheduled items, including the items in flight,
+// ordered by their next run time. The caller must hold the lock.
+func (sched *StdScheduler) items() []*item {
+	items := make([]*item, 0, len(sched.index))
+	for _, item := range sched.index {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].priority < items[j].priority
+	})
+
+	return items
+}
+
+// nextRunTime advances the item's priority to the next fire time of
+// its Trigger, unless the item was rescheduled or removed while in
+// flight. Unless the MisfirePolicy is MisfireSkip, a misfired item
+// advances to its next fire time after now. When the Trigger returns
+// an error other than ErrTriggerExpired, the item is queued to retry
+// the computation until the TriggerRetryLimit is reached. Items whose
+// Trigger returns an error which is not retried, or whose next fire
+// time is after their expire time, are no longer tracked.
+func (sched *StdScheduler) nextRunTime(it *item, misfired bool) error {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	startNow := it.startNow
+	it.startNow = false
+	ahead := it.ahead
+	it.ahead = nil
+	if it.rescheduled || it.removed {
+		it.rescheduled = false
+		return nil
+	}
+
+	prev := sched.advanceFrom(it, misfired, startNow)
+	it.held = 0
+	if it.triggerRetry {
+		it.triggerRetry = false
+	} else {
+		it.woken = false
+	}
+	var nextRunTime int64
+	var err error
+	if ahead != nil {
+		// the Trigger was evaluated for the deadline of the fire
+		prev, nextRunTime, err = ahead.prev, ahead.next, ahead.err
+	} else {
+		nextRunTime, err = it.Trigger.NextFireTime(prev)
+	}
+	if err == nil && it.opts.expired(it.opts.bounded(nextRunTime)) {
+		err = ErrJobExpired
+	}
+	if err != nil {
+		if !errors.Is(err, ErrTriggerExpired) && !errors.Is(err, ErrJobExpired) &&
+			it.triggerRetries < sched.opts.TriggerRetryLimit {
+			it.triggerRetries++
+			it.triggerRetry = true
+			it.retryFrom = prev
+			it.priority = sched.opts.Clock.Now().Add(sched.opts.TriggerRetryInterval).UnixNano()
+			sched.opts.Logger.Info("Retrying the failed Job trigger",
+				"key", it.key,
+				"trigger", it.Trigger.Description(),
+				"attempt", it.triggerRetries,
+				"retry_in", sched.opts.TriggerRetryInterval,
+				"error", err,
+			)
+			return nil
+		}
+		delete(sched.inflight, it)
+		sched.unindex(it)
+		sched.requeued(it)
+		return err
+	}
+	sched.setPriority(it, nextRunTime)
+	it.triggerRetries = 0
+
+	return nil
+}
+
+// advanceFrom returns the time the Trigger of the fired item is advanced
+// from. The caller must hold the lock.
+func (sched *StdScheduler) advanceFrom(it *item, misfired, startNow bool) int64 {
+	if it.triggerRetry {
+		// the time the failed Trigger is advanced from
+		return it.retryFrom
+	}
+
+	prev := it.priority
+	if it.held != 0 {
+		// the Trigger is advanced from the fire time of the held fire
+		prev = it.held
+	}
+	if it.jitterBase != 0 {
+		// the Trigger is advanced from its own fire time
+		prev = it.jitterBase
+	}
+	if misfired && it.opts.misfire != MisfireSkip {
+		prev = sched.nowNano()
+	}
+	if it.woken || startNow {
+		if now := sched.nowNano(); now > prev {
+			prev = now
+		}
+	}
+
+	return prev
+}
+
+// lookAhead evaluates the Trigger of the item for the fire after the one
+// being dispatched, so that the item is rescheduled with the evaluation,
+// and returns the next fire time, or 0 if there is none.
+func (sched *StdScheduler) lookAhead(it *item, misfired bool) int64 {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	prev := sched.advanceFrom(it, misfired, it.startNow)
+	next, err := it.Trigger.NextFireTime(prev)
+	it.ahead = &lookahead{prev: prev, next: next, err: err}
+	if err != nil || it.opts.expired(it.opts.bounded(next)) {
+		return 0
+	}
+
+	return it.opts.bounded(next)
+}
+
+// setPriority sets the priority of the item to the fire time of its
+// Trigger, pushed to the start time of the item if it is before it, and
+// delayed by a random offset if the item is scheduled with a jitter. A
+// jittered fire time is never earlier than the previous one. The caller
+// must hold the lock.
+func (sched *StdScheduler) setPriority(it *item, fireTime int64) {
+	fireTime = it.opts.bounded(fireTime)
+	if it.opts.jitter <= 0 {
+		it.priority = fireTime
+		return
+	}
+
+	if sched.rand == nil {
+		sched.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	next := fireTime + sched.rand.Int63n(it.opts.jitter.Nanoseconds())
+	if it.jitterBase != 0 && next < it.priority {
+		next = it.priority
+	}
+	it.jitterBase = fireTime
+	it.priority = next
+}
+
+// unscheduled reports the Job which got out of the execution loop
+// because its Trigger returned the error.
+func (sched *StdScheduler) unscheduled(job *ScheduledJob, err error) {
+	switch {
+	case errors.Is(err, ErrTriggerExpired):
+		sched.opts.Logger.Info("The Job trigger is expired",
+			"key", job.Key,
+			"description", job.Job.Description(),
+		)
+	case errors.Is(err, ErrJobExpired):
+		sched.opts.Logger.Info("The Job is expired",
+			"key", job.Key,
+			"description", job.Job.Description(),
+			"expire_time", job.ExpireTime,
+		)
+	default:
+		sched.opts.Logger.Error("The Job got out of the execution loop",
+			"key", job.Key,
+			"description", job.Job.Description(),
+			"trigger", job.TriggerDescription,
+			"last_run_time", time.Unix(0, job.NextRunTime),
+			"error", err,
+		)
+	}
+	sched.opts.Metrics.IncCounter(MetricJobsUnscheduled)
+	sched.notify(func(l SchedulerListener) { l.JobUnscheduled(*job, err) })
+}
+
+// registerWakeup registers the wakeup function with the Trigger of the
+// item, if it is a WakeableTrigger. The caller must hold the lock.
+func (sched *StdScheduler) registerWakeup(it *item, trigger Trigger) {
+	if wakeable, ok := trigger.(WakeableTrigger); ok {
+		wakeable.SetWakeup(func() { sched.wakeup(it, trigger) })
+	}
+}
+
+// wakeup handles the wakeup requested by the WakeableTrigger of the item.
+// Items in flight are woken once they are returned to the queue.
+func (sched *StdScheduler) wakeup(it *item, trigger Trigger) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if it.Trigger != trigger || sched.index[it.key] != it {
+		// the trigger was replaced or the job was removed
+		return
+	}
+	if _, ok := sched.inflight[it]; ok {
+		// the Trigger is advanced from the time of the wakeup
+		it.woken = true
+		it.ahead = nil
+		return
+	}
+
+	sched.wake(it)
+}
+
+// wake recalculates the next run time of the queued item from the current
+// time, if it brings the run time forward. Items which are due are
+// recalculated from the current time once they fire. The caller must hold
+// the lock.
+func (sched *StdScheduler) wake(it *item) {
+	now := sched.nowNano()
+	if it.priority <= now {
+		it.woken = true
+		return
+	}
+	it.woken = false
+
+	nextRunTime, err := it.Trigger.NextFireTime(now)
+	if err != nil || nextRunTime >= it.priority {
+		return
+	}
+	if err := sched.dequeue(it); err != nil {
+		sched.queueFailed("remove", err, "key", it.key)
+		return
+	}
+	it.jitterBase, it.held = 0, 0
+	sched.setPriority(it, nextRunTime)
+	sched.push(it)
+	sched.resetHead()
+}
+
+// execute runs the fire according to the configured execution
+// semantics. The context of the fire is derived from the jobs context,
+// while the loop context bounds the dispatch.
+func (sched *StdScheduler) execute(ctx, jobCtx context.Context, f *fire) {
+	f.start(jobCtx)
+	switch {
+	case sched.opts.BlockingExecution:
+		sched.run(f)
+	case f.item.opts.pool != "":
+		sched.dispatchFire(ctx, sched.namedPool(f.item.opts.pool), f)
+	case sched.usePool():
+		sched.dispatchFire(ctx, sched.defaultPool(), f)
+	default:
+		sched.spawn(f)
+	}
+}
+
+// dispatchFire hands the fire over to the worker pool, applying the
+// overflow policy if the dispatch queue is full. A fire blocked on a
+// named pool waits in its own goroutine, so that the fires of the other
+// pools are not delayed.
+func (sched *StdScheduler) dispatchFire(ctx context.Context, pool *workerPool, f *fire) {
+	select {
+	case pool.dispatch <- f:
+		return
+	default:
+	}
+
+	switch sched.opts.DispatchOverflow {
+	case OverflowDrop:
+		f.cancel()
+		sched.completeFire(f.ack)
+		sched.opts.Logger.Info("Dropping the Job fire, the dispatch queue is full",
+			"key", f.job.Key,
+			"description", f.job.Job.Description(),
+		)
+		sched.recordSkipped(f.item, f.job, "dropped")
+		sched.notify(func(l SchedulerListener) { l.JobDropped(*f.job) })
+	case OverflowSpill:
+		sched.spawn(f)
+	default:
+		if pool.name == "" {
+			sched.awaitWorker(ctx, pool, f)
+			return
+		}
+		sched.wg.Add(1)
+		go func() {
+			defer sched.wg.Done()
+			sched.awaitWorker(ctx, pool, f)
+		}()
+	}
+}
+
+// awaitWorker blocks until the fire is dispatched to a worker of the
+// pool, or the run is stopped.
+func (sched *StdScheduler) awaitWorker(ctx context.Context, pool *workerPool, f *fire) {
+	for {
+		sched.mtx.Lock()
+		limit, resized := pool.limit, pool.resized
+		sched.mtx.Unlock()
+		if limit == 0 {
+			// the pool was disabled in the meantime
+			sched.spawn(f)
+			return
+		}
+
+		select {
+		case pool.dispatch <- f:
+			return
+		case <-resized:
+		case <-ctx.Done():
+			f.cancel()
+			return
+		}
+	}
+}
+
+// spawn executes the fire in a new goroutine.
+func (sched *StdScheduler) spawn(f *fire) {
+	sched.wg.Add(1)
+	go func() {
+		defer sched.wg.Done()
+		sched.run(f)
+	}()
+}
+
+// drainDispatch drops the fires left in the dispatch queues by the
+// previous run. The caller must hold the lock.
+func (sched *StdScheduler) drainDispatch() {
+	drain(sched.dispatch)
+	for _, pool := range sched.pools {
+		drain(pool.dispatch)
+	}
+}
+
+// drain cancels the fires left in the dispatch queue.
+func drain(dispatch chan *fire) {
+	for {
+		select {
+		case f := <-dispatch:
+			f.cancel()
+		default:
+			return
+		}
+	}
+}
+
+// run executes the fire, notifying the listeners before and after the
+// execution. The execution is bounded by the Job timeout and the deadline
+// of the fire, whichever is earlier, if any.
+func (sched *StdScheduler) run(f *fire) {
+	defer sched.completeFire(f.ack)
+	defer f.cancel()
+	if !sched.acquire(f) {
+		return
+	}
+	defer sched.release(f)
+
+	if sched.opts.RateLimiter != nil {
+		if err := sched.opts.RateLimiter.Wait(f.ctx); err != nil {
+			sched.opts.Logger.Debug("The Job fire was canceled while rate limited",
+				"key", f.job.Key,
+				"error", err,
+			)
+			return
+		}
+	}
+	if sched.opts.LockProvider != nil {
+		unlock, ok := sched.lock(f)
+		if !ok {
+			return
+		}
+		defer unlock()
+	}
+	defer sched.track(f)()
+
+	ctx, job := f.ctx, f.job
+	timedOutErr := func() bool { return false }
+	timeout, bounded := job.Timeout, job.Timeout > 0
+	if f.deadline != 0 {
+		untilNext := time.Unix(0, f.deadline).Sub(sched.opts.Clock.Now())
+		if !bounded || untilNext < timeout {
+			timeout, bounded = untilNext, true
+		}
+	}
+	if bounded {
+		parent := ctx
+		timeoutCtx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		ctx = timeoutCtx
+		timedOutErr = func() bool { return timedOut(timeoutCtx, parent) }
+
+		sched.wg.Add(1)
+		go func() {
+			defer sched.wg.Done()
+			<-timeoutCtx.Done()
+			if timedOut(timeoutCtx, parent) {
+				sched.notify(func(l SchedulerListener) { l.JobTimedOut(*job) })
+			}
+		}()
+	}
+
+	sched.notify(func(l SchedulerListener) { l.BeforeJobExecution(*job) })
+
+	start := f.started
+	if !f.catchUp {
+		sched.opts.Metrics.ObserveDuration(MetricSchedulingDelay, start.Sub(time.Unix(0, job.NextRunTime)))
+	}
+	var runCount int64
+	sched.updateStats(f.item, func(stats *jobStats) {
+		stats.lastRunTime = start.UnixNano()
+		stats.runCount++
+		runCount = stats.runCount
+	})
+
+	ctx = withExecutionContext(ctx, &ExecutionContext{
+		Key:                job.Key,
+		TriggerDescription: job.TriggerDescription,
+		ScheduledTime:      time.Unix(0, job.NextRunTime),
+		FireTime:           start,
+		RunCount:           runCount,
+		Misfire:            f.misfire,
+		CatchUp:            f.catchUp,
+		Data:               job.Data,
+		job:                job,
+		notify:             sched.notify,
+	})
+	var finish func(error)
+	if sched.opts.ExecutionHook != nil {
+		ctx, finish = sched.opts.ExecutionHook(ctx, *job)
+	}
+	wrapped := sched.wrapJob(job.Job)
+	err := executeJob(ctx, wrapped)
+	if finish != nil {
+		finish(err)
+	}
+	end := sched.opts.Clock.Now()
+	if err != nil {
+		sched.opts.Logger.Error("The Job execution failed",
+			"key", job.Key,
+			"description", wrapped.Description(),
+			"error", err,
+		)
+	}
+	sched.updateStats(f.item, func(stats *jobStats) {
+		stats.lastCompletedTime = end.UnixNano()
+		stats.lastError = err
+	})
+
+	duration := end.Sub(start)
+	record := ExecutionRecord{
+		ScheduledTime: time.Unix(0, job.NextRunTime),
+		StartTime:     start,
+		Duration:      duration,
+	}
+	var panicErr *jobPanicError
+	switch {
+	case err == nil:
+		record.Outcome = OutcomeOK
+	case errors.As(err, &panicErr):
+		record.Outcome = OutcomePanic
+	case timedOutErr():
+		record.Outcome = OutcomeTimedOut
+	default:
+		record.Outcome = OutcomeError
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	sched.recordExecution(f.item, record)
+	sched.opts.Metrics.IncCounter(MetricJobsExecuted)
+	if err != nil {
+		sched.opts.Metrics.IncCounter(MetricJobsFailed)
+	}
+	sched.opts.Metrics.ObserveDuration(MetricExecutionDuration, duration)
+	sched.notify(func(l SchedulerListener) { l.AfterJobExecution(*job, duration, err) })
+	sched.mtx.Lock()
+	sched.releaseWaiters(job.Key, err)
+	sched.mtx.Unlock()
+
+	if callbackErr := f.item.opts.callback(ctx, job.Job, err); callbackErr != nil {
+		sched.opts.Logger.Error("The Job callback failed",
+			"key", job.Key,
+			"description", job.Job.Description(),
+			"error", callbackErr,
+		)
+	}
+}
+
+// acquire waits for the previous execution of the fired item to return,
+// or for one of its WithMaxConcurrent executions, according to its
+// ConcurrencyPolicy. It returns false if the fire is skipped.
+func (sched *StdScheduler) acquire(f *fire) bool {
+	if f.item.sem == nil {
+		return true
+	}
+
+	if f.item.opts.concurrency != ConcurrencyQueue {
+		select {
+		case f.item.sem <- struct{}{}:
+			return true
+		default:
+			sched.opts.Logger.Info("Skipping the Job fire, the previous execution is running",
+				"key", f.job.Key,
+				"description", f.job.Job.Description(),
+				"max_concurrent", cap(f.item.sem),
+			)
+			sched.recordSkipped(f.item, f.job, "concurrent execution")
+			sched.notify(func(l SchedulerListener) { l.JobSkippedConcurrent(*f.job) })
+			return false
+		}
+	}
+
+	select {
+	case f.item.sem <- struct{}{}:
+		return true
+	case <-f.ctx.Done():
+		return false
+	}
+}
+
+// release allows the next execution of the fired item.
+func (sched *StdScheduler) release(f *fire) {
+	if f.item.sem != nil {
+		<-f.item.sem
+	}
+}
+
+// timedOut reports whether the done context expired due to its own
+// deadline, rather than being canceled or expiring with the parent.
+func timedOut(ctx, parent context.Context) bool {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	deadline, _ := ctx.Deadline()
+	parentDeadline, ok := parent.Deadline()
+	return !ok || parentDeadline.After(deadline)
+}
+
+// wrapJob applies the JobWrappers around the Job, the first one outermost.
+func (sched *StdScheduler) wrapJob(job Job) Job {
+	for i := len(sched.opts.JobWrappers) - 1; i >= 0; i-- {
+		job = sched.opts.JobWrappers[i](job)
+	}
+
+	return job
+}
+
+// executeJob executes the Job, recovering a panic as an error.
+func executeJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &jobPanicError{value: r}
+		}
+	}()
+
+	return job.Execute(ctx)
+}
+
+// jobPanicError is the error of a Job execution which panicked.
+type jobPanicError struct {
+	value any
+}
+
+func (e *jobPanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", e.value)
+}
+
+// updateStats applies the update to the execution stats of the item.
+func (sched *StdScheduler) updateStats(it *item, update func(*jobStats)) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	update(&it.stats)
+}
+
 func (sched *StdScheduler) startFeedReader(ctx context.Context) {
 	defer sched.wg.Done()
 	for {
 		select {
 		case item := <-sched.feeder:
-			func() {
-				sched.mtx.Lock()
-				defer sched.mtx.Unlock()
-
-				heap.Push(sched.queue, item)
-				sched.reset(ctx, time.Unix(0, sched.queue.Head().priority))
-			}()
+			sched.feed(item)
 		case <-ctx.Done():
-			log.Printf("Exit the feed reader.")
+			sched.opts.Logger.Debug("Exit the feed reader")
 			return
 		}
 	}
 }
 
-func (sched *StdScheduler) reset(ctx context.Context, next time.Time) {
+// feed queues the item returning from the execution loop.
+func (sched *StdScheduler) feed(item *item) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	// the tombstone of an item deleted, replaced or cleared while
+	// in flight drops it in push
+	sched.push(item)
+	if item.woken && sched.index[item.key] == item {
+		sched.wake(item)
+	}
+	sched.reportQueueLength()
+	sched.resetHead()
+}
+
+// drainFeeder queues the items left in the feeder buffer. It is called
+// by the execution loop, the only sender to the feeder, once it exits,
+// so that the items are not left out of the queue while the scheduler
+// is stopped.
+func (sched *StdScheduler) drainFeeder() {
+	for {
+		select {
+		case item := <-sched.feeder:
+			sched.feed(item)
+		default:
+			return
+		}
+	}
+}
+
+// push adds the item to the queue and the key index, including items
+// returning from the execution loop. Items removed while in flight are
+// dropped. Items which the queue fails to accept are kept as pending,
+// and their push is retried by the execution loop. The caller must
+// hold the lock.
+func (sched *StdScheduler) push(it *item) {
+	delete(sched.inflight, it)
+	if it.removed {
+		sched.requeued(it)
+		return
+	}
+	it.rescheduled = false
+	sched.index[it.key] = it
+	if err := sched.queue.Push(it.queuedJob()); err != nil {
+		sched.queueFailed("push", err, "key", it.key)
+		sched.pending[it] = struct{}{}
+		return
+	}
+	sched.requeued(it)
+}
+
+func (sched *StdScheduler) reset(next time.Time) {
+	// replace a pending interrupt, so that the latest wakeup
+	// time is not dropped when the channel is already full
+	select {
+	case <-sched.interrupt:
+	default:
+	}
+
 	select {
 	case sched.interrupt <- next:
-	case <-ctx.Done():
 	default:
 	}
 }
+
+// resetHead interrupts the execution loop to reevaluate the head of the
+// queue. When the queue is empty, the loop moves to its idle state.
+// The caller must hold the lock.
+func (sched *StdScheduler) resetHead() {
+	sched.reset(sched.nextTick())
+}
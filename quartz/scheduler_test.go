@@ -1,9 +1,14 @@
 package quartz_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"runtime"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -42,7 +47,7 @@ func TestScheduler(t *testing.T) {
 
 	time.Sleep(time.Second)
 	scheduledJobKeys := sched.GetJobKeys()
-	assertEqual(t, scheduledJobKeys, []int{3668896347, 328790344})
+	assertEqual(t, scheduledJobKeys, []int{jobKeys[0], jobKeys[3]})
 
 	_, err = sched.GetScheduledJob(jobKeys[0])
 	if err != nil {
@@ -56,7 +61,7 @@ func TestScheduler(t *testing.T) {
 
 	scheduledJobKeys = sched.GetJobKeys()
 	assertEqual(t, len(scheduledJobKeys), 1)
-	assertEqual(t, scheduledJobKeys, []int{328790344})
+	assertEqual(t, scheduledJobKeys, []int{jobKeys[3]})
 
 	sched.Clear()
 	sched.Stop()
@@ -263,3 +268,3042 @@ func TestSchedulerCancel(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedulerRescheduleJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{BlockingExecution: true})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	err := sched.RescheduleJob(ctx, 42, quartz.NewSimpleTrigger(time.Second))
+	if !errors.Is(err, quartz.ErrJobNotFound) {
+		t.Fatal("expected ErrJobNotFound, got", err)
+	}
+
+	var n int64
+	job := quartz.NewFunctionJobWithDesc("reschedule", func(_ context.Context) (bool, error) {
+		atomic.AddInt64(&n, 1)
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := sched.RescheduleJob(ctx, job.Key(), quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if atomic.LoadInt64(&n) == 0 {
+		t.Fatal("rescheduled job should have run")
+	}
+
+	scheduledJob, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduledJob.TriggerDescription, quartz.NewSimpleTrigger(10*time.Millisecond).Description())
+}
+
+func TestSchedulerRescheduleInFlightJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{BlockingExecution: true})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+	var n int64
+	job := quartz.NewFunctionJobWithDesc("in-flight", func(_ context.Context) (bool, error) {
+		if atomic.AddInt64(&n, 1) == 1 {
+			close(running)
+			<-release
+		}
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	<-running
+	trigger := quartz.NewSimpleTrigger(time.Hour)
+	if err := sched.RescheduleJob(ctx, job.Key(), trigger); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt64(&n), int64(1))
+
+	scheduledJob, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduledJob.TriggerDescription, trigger.Description())
+	if time.Until(time.Unix(0, scheduledJob.NextRunTime)) < 30*time.Minute {
+		t.Fatal("the new trigger should determine the next run time")
+	}
+}
+
+func TestSchedulerTriggerJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		if err := sched.TriggerJob(ctx, 42); !errors.Is(err, quartz.ErrSchedulerNotStarted) {
+			t.Fatal("expected ErrSchedulerNotStarted, got", err)
+		}
+
+		sched.Start(ctx)
+		if err := sched.TriggerJob(ctx, 42); !errors.Is(err, quartz.ErrJobNotFound) {
+			t.Fatal("expected ErrJobNotFound, got", err)
+		}
+
+		var n int64
+		job := quartz.NewFunctionJobWithDesc("trigger", func(_ context.Context) (bool, error) {
+			atomic.AddInt64(&n, 1)
+			return true, nil
+		})
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		scheduledJob, err := sched.GetScheduledJob(job.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := sched.TriggerJob(ctx, job.Key()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		assertEqual(t, atomic.LoadInt64(&n), int64(1))
+
+		triggeredJob, err := sched.GetScheduledJob(job.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, triggeredJob.NextRunTime, scheduledJob.NextRunTime)
+
+		sched.Stop()
+		if err := sched.TriggerJob(ctx, job.Key()); !errors.Is(err, quartz.ErrSchedulerNotStarted) {
+			t.Fatal("expected ErrSchedulerNotStarted, got", err)
+		}
+	}
+}
+
+func TestSchedulerGetScheduledJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdScheduler()
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	assertEqual(t, len(sched.GetScheduledJobs()), 0)
+
+	intervals := []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour}
+	for i, interval := range intervals {
+		job := quartz.NewShellJob(fmt.Sprintf("echo %d", i))
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(interval)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	jobs := sched.GetScheduledJobs()
+	assertEqual(t, len(jobs), len(intervals))
+	assertEqual(t, jobs[0].Job.Description(), "ShellJob: echo 1")
+	assertEqual(t, jobs[1].Job.Description(), "ShellJob: echo 2")
+	assertEqual(t, jobs[2].Job.Description(), "ShellJob: echo 0")
+	for i := 1; i < len(jobs); i++ {
+		if jobs[i-1].NextRunTime > jobs[i].NextRunTime {
+			t.Fatal("jobs should be sorted by the next run time")
+		}
+	}
+
+	// mutating the snapshot should not affect the queue
+	jobs[0].NextRunTime = 0
+	jobs[0] = nil
+	snapshot := sched.GetScheduledJobs()
+	assertEqual(t, snapshot[0].Job.Description(), "ShellJob: echo 1")
+	assertNotEqual(t, snapshot[0].NextRunTime, 0)
+}
+
+func TestSchedulerRestart(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.Start(ctx)
+
+		var n int64
+		job := quartz.NewFunctionJobWithDesc("restart", func(_ context.Context) (bool, error) {
+			atomic.AddInt64(&n, 1)
+			return true, nil
+		})
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		sched.Stop()
+		sched.Wait(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatal("waiting timed out", err)
+		}
+		if sched.IsStarted() {
+			t.Fatal("scheduler should be stopped")
+		}
+		stopped := atomic.LoadInt64(&n)
+		if stopped == 0 {
+			t.Fatal("job should have run before stop")
+		}
+		assertEqual(t, sched.GetJobKeys(), []int{job.Key()})
+
+		sched.Start(ctx)
+		time.Sleep(50 * time.Millisecond)
+		if !sched.IsStarted() {
+			t.Fatal("scheduler should be running after restart")
+		}
+		if atomic.LoadInt64(&n) <= stopped {
+			t.Fatal("job should run after restart")
+		}
+
+		sched.Stop()
+		sched.Wait(ctx)
+	}
+}
+
+func TestSchedulerRestartWithNewContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched := quartz.NewStdScheduler()
+	sched.Start(ctx)
+	cancel()
+	sched.Wait(context.Background())
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	sched.Start(ctx)
+	// a stale stop of the previous run should not stop the
+	// scheduler
+	time.Sleep(10 * time.Millisecond)
+	if !sched.IsStarted() {
+		t.Fatal("scheduler should be running after restart")
+	}
+	sched.Stop()
+}
+
+func TestSchedulerScheduleBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdScheduler()
+
+	var n int64
+	job := quartz.NewFunctionJobWithDesc("before-start", func(_ context.Context) (bool, error) {
+		atomic.AddInt64(&n, 1)
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatal("scheduling should not block", err)
+	}
+	assertEqual(t, sched.GetJobKeys(), []int{job.Key()})
+
+	sched.Start(ctx)
+	assertEqual(t, atomic.LoadInt64(&n), int64(0))
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt64(&n), int64(1))
+
+	sched.Stop()
+	sched.Wait(ctx)
+
+	// scheduling after stop should not block either
+	other := quartz.NewFunctionJobWithDesc("after-stop", func(_ context.Context) (bool, error) {
+		atomic.AddInt64(&n, 1)
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, other, quartz.NewRunOnceTrigger(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sched.GetJobKeys(), []int{other.Key()})
+
+	sched.Start(ctx)
+	time.Sleep(100 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt64(&n), int64(2))
+	sched.Stop()
+}
+
+func TestSchedulerJobGroups(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+
+	var reports, cleanups int64
+	reportJob := quartz.NewFunctionJobWithDesc("cleanup", func(_ context.Context) (bool, error) {
+		atomic.AddInt64(&reports, 1)
+		return true, nil
+	})
+	cleanupJob := quartz.NewFunctionJobWithDesc("cleanup", func(_ context.Context) (bool, error) {
+		atomic.AddInt64(&cleanups, 1)
+		return true, nil
+	})
+
+	reportKey := quartz.NewJobKeyWithGroup("cleanup", "reports")
+	cleanupKey := quartz.NewJobKeyWithGroup("cleanup", "maintenance")
+	if err := sched.ScheduleJobWithKey(ctx, reportKey, reportJob,
+		quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJobWithKey(ctx, cleanupKey, cleanupJob,
+		quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	shellJob := quartz.NewShellJob("ls")
+	if err := sched.ScheduleJob(ctx, shellJob, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, sched.GetJobKeysWithGroup("reports"), []quartz.JobKey{reportKey})
+	assertEqual(t, sched.GetJobKeysWithGroup("maintenance"), []quartz.JobKey{cleanupKey})
+	assertEqual(t, sched.GetJobKeys(), []int{shellJob.Key()})
+	scheduledJob, err := sched.GetScheduledJob(shellJob.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduledJob.Key, quartz.NewJobKey(strconv.Itoa(shellJob.Key())))
+	assertEqual(t, scheduledJob.Key.Group, quartz.DefaultGroup)
+
+	assertEqual(t, sched.PauseGroup("reports"), 1)
+	assertEqual(t, sched.PauseGroup("reports"), 0)
+
+	sched.Start(ctx)
+	defer sched.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	assertEqual(t, atomic.LoadInt64(&reports), int64(0))
+	if atomic.LoadInt64(&cleanups) == 0 {
+		t.Fatal("jobs in other groups should run")
+	}
+	for _, job := range sched.GetScheduledJobs() {
+		assertEqual(t, job.Paused, job.Key == reportKey)
+	}
+
+	assertEqual(t, sched.ResumeGroup("reports"), 1)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt64(&reports) == 0 {
+		t.Fatal("resumed jobs should run")
+	}
+
+	archiveKey := quartz.NewJobKeyWithGroup("cleanup", "archive")
+	if err := sched.ScheduleJobWithKey(ctx, archiveKey, cleanupJob,
+		quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	assertEqual(t, sched.DeleteJobGroup("archive"), 1)
+	assertEqual(t, sched.DeleteJobGroup("archive"), 0)
+	assertEqual(t, len(sched.GetJobKeysWithGroup("archive")), 0)
+	assertEqual(t, sched.GetJobKeysWithGroup("reports"), []quartz.JobKey{reportKey})
+}
+
+func TestSchedulerDeleteHeadJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdScheduler()
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	fired := make(chan time.Time, 2)
+	job := func(desc string) quartz.Job {
+		return quartz.NewFunctionJobWithDesc(desc, func(_ context.Context) (bool, error) {
+			fired <- time.Now()
+			return true, nil
+		})
+	}
+
+	start := time.Now()
+	head := job("head")
+	if err := sched.ScheduleJob(ctx, head, quartz.NewRunOnceTrigger(100*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJob(ctx, job("next"), quartz.NewRunOnceTrigger(200*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := sched.DeleteJob(head.Key()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(start); elapsed < 190*time.Millisecond || elapsed > 300*time.Millisecond {
+			t.Fatal("the remaining job should fire on time, fired after", elapsed)
+		}
+	case <-ctx.Done():
+		t.Fatal("the remaining job should fire")
+	}
+
+	// deleting the last job moves the loop to the idle state, and
+	// a newly scheduled job fires on time
+	other := job("other")
+	if err := sched.ScheduleJob(ctx, other, quartz.NewRunOnceTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := sched.DeleteJob(other.Key()); err != nil {
+		t.Fatal(err)
+	}
+	start = time.Now()
+	if err := sched.ScheduleJob(ctx, job("last"), quartz.NewRunOnceTrigger(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(start); elapsed > 150*time.Millisecond {
+			t.Fatal("the new job should fire on time, fired after", elapsed)
+		}
+	case <-ctx.Done():
+		t.Fatal("the new job should fire")
+	}
+}
+
+type deletedListener struct {
+	quartz.NoopListener
+	deleted chan quartz.JobKey
+}
+
+func (l *deletedListener) JobDeleted(key quartz.JobKey) {
+	l.deleted <- key
+}
+
+func TestSchedulerClearHeadJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	listener := &deletedListener{deleted: make(chan quartz.JobKey, 2)}
+	sched.AddListener(listener)
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	fired := make(chan time.Time, 1)
+	job := func(desc string) quartz.Job {
+		return quartz.NewFunctionJobWithDesc(desc, func(_ context.Context) (bool, error) {
+			fired <- time.Now()
+			return true, nil
+		})
+	}
+
+	// the loop waits on the head job when it is cleared
+	for i, desc := range []string{"head", "next"} {
+		if err := sched.ScheduleJob(ctx, job(desc),
+			quartz.NewRunOnceTrigger(time.Duration(i+1)*100*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	sched.Clear()
+	assertEqual(t, len(sched.GetJobKeys()), 0)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-listener.deleted:
+		case <-ctx.Done():
+			t.Fatal("the cleared jobs should be reported as deleted")
+		}
+	}
+
+	// a job scheduled right after the clear fires on time
+	start := time.Now()
+	if err := sched.ScheduleJob(ctx, job("last"), quartz.NewRunOnceTrigger(20*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(start); elapsed > 80*time.Millisecond {
+			t.Fatal("the new job should fire on time, fired after", elapsed)
+		}
+	case <-ctx.Done():
+		t.Fatal("the new job should fire")
+	}
+
+	// the timer of the cleared head does not fire a job
+	time.Sleep(200 * time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("no job should fire after the clear")
+	default:
+	}
+}
+
+func TestSchedulerDeleteInFlightJob(t *testing.T) {
+	for _, mode := range []string{"Blocking", "NonBlocking", "WorkerPool"} {
+		for _, op := range []string{"Delete", "DeleteGroup", "Clear"} {
+			t.Run(mode+op, func(t *testing.T) {
+				opts := quartz.StdSchedulerOptions{Logger: quartz.NewNoopLogger()}
+				switch mode {
+				case "Blocking":
+					opts.BlockingExecution = true
+				case "WorkerPool":
+					opts.WorkerLimit = 2
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				sched := quartz.NewStdSchedulerWithOptions(opts)
+				listener := &deletedListener{deleted: make(chan quartz.JobKey, 1)}
+				sched.AddListener(listener)
+				sched.Start(ctx)
+				defer sched.Stop()
+
+				var runs int32
+				started := make(chan struct{})
+				release := make(chan struct{})
+				defer close(release)
+				job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+					if atomic.AddInt32(&runs, 1) == 1 {
+						close(started)
+						<-release
+					}
+					return true, nil
+				})
+				if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(20*time.Millisecond),
+					quartz.WithConcurrencyPolicy(quartz.ConcurrencySkip)); err != nil {
+					t.Fatal(err)
+				}
+				select {
+				case <-started:
+				case <-ctx.Done():
+					t.Fatal("the job should start")
+				}
+
+				switch op {
+				case "Delete":
+					if err := sched.DeleteJob(job.Key()); err != nil {
+						t.Fatal(err)
+					}
+				case "DeleteGroup":
+					assertEqual(t, sched.DeleteJobGroup(quartz.DefaultGroup), 1)
+				case "Clear":
+					sched.Clear()
+				}
+				select {
+				case key := <-listener.deleted:
+					assertEqual(t, key, quartz.NewJobKey(strconv.Itoa(job.Key())))
+				case <-ctx.Done():
+					t.Fatal("the job should be reported as deleted")
+				}
+				release <- struct{}{}
+
+				// the returning execution does not queue the job again
+				time.Sleep(100 * time.Millisecond)
+				assertEqual(t, atomic.LoadInt32(&runs), 1)
+				assertEqual(t, len(sched.GetJobKeys()), 0)
+				_, err := sched.GetScheduledJob(job.Key())
+				assertEqual(t, err, quartz.ErrJobNotFound)
+			})
+		}
+	}
+}
+
+func TestSchedulerShutdown(t *testing.T) {
+	for _, tt := range []string{"Blocking", "NonBlocking", "Worker"} {
+		t.Run(tt, func(t *testing.T) {
+			var opts quartz.StdSchedulerOptions
+			switch tt {
+			case "Blocking":
+				opts.BlockingExecution = true
+			case "Worker":
+				opts.WorkerLimit = 2
+			}
+
+			t.Run("Drain", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				sched := quartz.NewStdSchedulerWithOptions(opts)
+				sched.Start(ctx)
+
+				running := make(chan struct{})
+				var completed int64
+				job := quartz.NewFunctionJobWithDesc("drain", func(ctx context.Context) (bool, error) {
+					close(running)
+					time.Sleep(100 * time.Millisecond)
+					if ctx.Err() != nil {
+						t.Error("job context should not be canceled", ctx.Err())
+					}
+					atomic.AddInt64(&completed, 1)
+					return true, nil
+				})
+				if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+					t.Fatal(err)
+				}
+				<-running
+
+				if err := sched.Shutdown(ctx); err != nil {
+					t.Fatal(err)
+				}
+				assertEqual(t, atomic.LoadInt64(&completed), int64(1))
+				if sched.IsStarted() {
+					t.Fatal("scheduler should be stopped")
+				}
+			})
+			t.Run("Timeout", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				sched := quartz.NewStdSchedulerWithOptions(opts)
+				sched.Start(ctx)
+
+				running := make(chan struct{})
+				canceled := make(chan struct{})
+				job := quartz.NewFunctionJobWithDesc("hang", func(ctx context.Context) (bool, error) {
+					close(running)
+					<-ctx.Done()
+					close(canceled)
+					return false, ctx.Err()
+				})
+				if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+					t.Fatal(err)
+				}
+				<-running
+
+				shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				defer shutdownCancel()
+				if err := sched.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatal("expected deadline exceeded, got", err)
+				}
+
+				select {
+				case <-canceled:
+				case <-ctx.Done():
+					t.Fatal("the job should be canceled after the shutdown timeout")
+				}
+				sched.Wait(ctx)
+			})
+		})
+	}
+}
+
+func TestSchedulerJobTimeout(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true, JobTimeout: 20 * time.Millisecond},
+		{WorkerLimit: 2, JobTimeout: 20 * time.Millisecond},
+		{JobTimeout: 20 * time.Millisecond},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		listener := &recordingListener{}
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.AddListener(listener)
+		sched.Start(ctx)
+
+		var deadlineErr atomic.Value
+		timedOutKey := quartz.NewJobKey("timed-out")
+		timedOutJob := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			<-ctx.Done()
+			deadlineErr.Store(ctx.Err())
+			return false, ctx.Err()
+		})
+		if err := sched.ScheduleJobWithKey(ctx, timedOutKey, timedOutJob,
+			quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+
+		var completed int32
+		completedKey := quartz.NewJobKey("completed")
+		completedJob := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				atomic.AddInt32(&completed, 1)
+				return true, nil
+			}
+		})
+		if err := sched.ScheduleJobWithKey(ctx, completedKey, completedJob,
+			quartz.NewRunOnceTrigger(50*time.Millisecond), quartz.WithTimeout(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(250 * time.Millisecond)
+		sched.Stop()
+		sched.Wait(ctx)
+
+		assertEqual[any](t, deadlineErr.Load(), context.DeadlineExceeded)
+		assertEqual(t, atomic.LoadInt32(&completed), int32(1))
+		assertEqual(t, listener.snapshot().timedOut, []quartz.JobKey{timedOutKey})
+	}
+}
+
+// countingTrigger fires every interval, counting its evaluations.
+type countingTrigger struct {
+	interval time.Duration
+	calls    int32
+}
+
+func (ct *countingTrigger) NextFireTime(prev int64) (int64, error) {
+	atomic.AddInt32(&ct.calls, 1)
+	return prev + ct.interval.Nanoseconds(), nil
+}
+
+func (ct *countingTrigger) Description() string {
+	return "counting"
+}
+
+func TestSchedulerDeadlineAtNextFire(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		opts.Logger = quartz.NewNoopLogger()
+		listener := &recordingListener{}
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.AddListener(listener)
+
+		var mtx sync.Mutex
+		var errs []error
+		var bounds []time.Duration
+		var runs int32
+		pollingKey := quartz.NewJobKey("polling")
+		trigger := &countingTrigger{interval: 40 * time.Millisecond}
+		pollingJob := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			atomic.AddInt32(&runs, 1)
+			execCtx, _ := quartz.ExecutionContextFrom(ctx)
+			deadline, _ := ctx.Deadline()
+			<-ctx.Done()
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			errs = append(errs, ctx.Err())
+			bounds = append(bounds, deadline.Sub(execCtx.ScheduledTime))
+			return false, ctx.Err()
+		})
+		// the next execution starts once the canceled one returns
+		if err := sched.ScheduleJobWithKey(ctx, pollingKey, pollingJob, trigger,
+			quartz.WithDeadlineAtNextFire(), quartz.WithConcurrencyPolicy(quartz.ConcurrencyQueue)); err != nil {
+			t.Fatal(err)
+		}
+		// the earlier deadline of the timeout applies
+		var timeoutBound time.Duration
+		timeoutKey := quartz.NewJobKey("timeout")
+		timeoutJob := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			execCtx, _ := quartz.ExecutionContextFrom(ctx)
+			deadline, _ := ctx.Deadline()
+			<-ctx.Done()
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			timeoutBound = deadline.Sub(execCtx.FireTime)
+			return false, ctx.Err()
+		})
+		if err := sched.ScheduleJobWithKey(ctx, timeoutKey, timeoutJob,
+			quartz.NewRunOnceTrigger(time.Millisecond), quartz.WithDeadlineAtNextFire(),
+			quartz.WithTimeout(10*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+
+		// the last fire of a Trigger is not bounded
+		var lastDeadline atomic.Value
+		lastKey := quartz.NewJobKey("last")
+		lastJob := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			deadline, _ := ctx.Deadline()
+			lastDeadline.Store(deadline)
+			return true, nil
+		})
+		if err := sched.ScheduleJobWithKey(ctx, lastKey, lastJob,
+			quartz.NewRunOnceTrigger(time.Millisecond), quartz.WithDeadlineAtNextFire()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, job := range sched.GetScheduledJobs() {
+			assertEqual(t, job.DeadlineAtNextFire, true)
+		}
+
+		sched.Start(ctx)
+		time.Sleep(150 * time.Millisecond)
+		sched.Stop()
+		sched.Wait(ctx)
+
+		mtx.Lock()
+		if len(errs) < 2 {
+			t.Fatalf("expected at least 2 executions, got %d", len(errs))
+		}
+		assertEqual(t, errs[0], error(context.DeadlineExceeded))
+		for _, bound := range bounds[:len(bounds)-1] {
+			if bound < 35*time.Millisecond || bound > 45*time.Millisecond {
+				t.Fatalf("unexpected deadline %s after the scheduled time", bound)
+			}
+		}
+		if timeoutBound <= 0 || timeoutBound > 20*time.Millisecond {
+			t.Fatalf("unexpected deadline %s after the fire time", timeoutBound)
+		}
+		mtx.Unlock()
+		testDeadline, _ := ctx.Deadline()
+		assertEqual[any](t, lastDeadline.Load(), testDeadline)
+
+		// each fire evaluates the Trigger once, and the Job is
+		// rescheduled with the evaluation
+		assertEqual(t, atomic.LoadInt32(&trigger.calls), atomic.LoadInt32(&runs)+1)
+
+		// reaching either of the deadlines is reported as a timeout
+		timedOut := listener.snapshot().timedOut
+		assertEqual(t, len(timedOut) >= 2, true)
+	}
+}
+
+func TestSchedulerMisfirePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        quartz.StdSchedulerOptions
+		skipped     func(int) bool
+		minExecuted int
+	}{
+		{
+			name: "Skip",
+			opts: quartz.StdSchedulerOptions{
+				MisfirePolicy:     quartz.MisfireSkip,
+				OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+			},
+			skipped:     func(n int) bool { return n >= 3 },
+			minExecuted: 1,
+		},
+		{
+			name: "FireNow",
+			opts: quartz.StdSchedulerOptions{
+				MisfirePolicy:     quartz.MisfireFireNow,
+				OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+			},
+			skipped:     func(n int) bool { return n == 0 },
+			minExecuted: 3,
+		},
+		{
+			name: "RescheduleNext",
+			opts: quartz.StdSchedulerOptions{
+				MisfirePolicy:     quartz.MisfireRescheduleNext,
+				OutdatedThreshold: quartz.DefaultOutdatedThreshold,
+			},
+			skipped:     func(n int) bool { return n == 1 },
+			minExecuted: 2,
+		},
+		{
+			name:        "OutdatedThreshold",
+			opts:        quartz.StdSchedulerOptions{OutdatedThreshold: time.Second},
+			skipped:     func(n int) bool { return n == 0 },
+			minExecuted: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			tt.opts.BlockingExecution = true
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(tt.opts)
+			sched.AddListener(listener)
+
+			// the blocking job delays the fires of the frequent
+			// job, making them outdated
+			if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey("blocking"),
+				quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+					time.Sleep(100 * time.Millisecond)
+					return true, nil
+				}), quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+			frequentKey := quartz.NewJobKey("frequent")
+			if err := sched.ScheduleJobWithKey(ctx, frequentKey, quartz.NewShellJob("ls"),
+				quartz.NewSimpleTrigger(20*time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+
+			sched.Start(ctx)
+			time.Sleep(170 * time.Millisecond)
+			sched.Stop()
+			sched.Wait(ctx)
+
+			events := listener.snapshot()
+			if !tt.skipped(len(events.skipped)) {
+				t.Error("unexpected number of skipped fires", len(events.skipped))
+			}
+			var executed int
+			for _, key := range events.before {
+				if key == frequentKey {
+					executed++
+				}
+			}
+			if executed < tt.minExecuted {
+				t.Error("unexpected number of executions", executed)
+			}
+		})
+	}
+}
+
+func TestSchedulerScheduleFunc(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var calls int32
+	function := func(_ context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+
+	hourlyKey, err := sched.ScheduleFunc(ctx, function, quartz.NewSimpleTrigger(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	onceKey, err := sched.ScheduleOnceAt(ctx, function, time.Now().Add(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNotEqual(t, hourlyKey, onceKey)
+
+	time.Sleep(10 * time.Millisecond)
+	assertEqual(t, len(sched.GetJobKeys()), 2)
+
+	time.Sleep(50 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&calls), int32(1))
+	assertEqual(t, sched.GetJobKeys(), []int{hourlyKey})
+
+	if err := sched.DeleteJob(hourlyKey); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(sched.GetJobKeys()), 0)
+}
+
+func TestSchedulerLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+	if sched.IsStarted() {
+		t.Fatal("scheduler should not be started")
+	}
+	sched.Stop()
+	if err := sched.StopErr(); !errors.Is(err, quartz.ErrSchedulerNotStarted) {
+		t.Fatal("unexpected error", err)
+	}
+
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.StartErr(ctx); !errors.Is(err, quartz.ErrSchedulerAlreadyStarted) {
+		t.Fatal("unexpected error", err)
+	}
+
+	if err := sched.StopErr(); err != nil {
+		t.Fatal(err)
+	}
+	sched.Stop()
+	if err := sched.StopErr(); !errors.Is(err, quartz.ErrSchedulerNotStarted) {
+		t.Fatal("unexpected error", err)
+	}
+	if sched.IsStarted() {
+		t.Fatal("scheduler should be stopped")
+	}
+	sched.Wait(ctx)
+
+	// a stopped StdScheduler can be started again
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.StopErr(); err != nil {
+		t.Fatal(err)
+	}
+	sched.Wait(ctx)
+}
+
+func TestSchedulerWaitErr(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey("blocking"),
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+			close(started)
+			<-release
+			return true, nil
+		}), quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("job was not started")
+	}
+	sched.Stop()
+
+	// the expired waits do not leave their goroutines behind
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		waitCtx, waitCancel := context.WithTimeout(ctx, time.Microsecond)
+		err := sched.WaitErr(waitCtx)
+		waitCancel()
+		assertEqual(t, errors.Is(err, context.DeadlineExceeded), true)
+	}
+	assertEqual(t, runtime.NumGoroutine() < goroutines+10, true)
+
+	release <- struct{}{}
+	assertEqual(t, sched.WaitErr(ctx), nil)
+}
+
+func TestSchedulerIntrospection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now().Truncate(time.Second)
+	clock := quartz.NewMockClock(start)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		WorkerLimit: 1,
+		Clock:       clock,
+		Logger:      quartz.NewNoopLogger(),
+	})
+	_, ok := sched.NextRunTime()
+	assertEqual(t, ok, false)
+	assertEqual(t, sched.JobCount(), 0)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey("blocking"),
+		quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			select {
+			case started <- struct{}{}:
+				<-release
+			case <-ctx.Done():
+			}
+			return true, nil
+		}), quartz.NewSimpleTrigger(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey("idle"),
+		quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sched.JobCount(), 2)
+	next, ok := sched.NextRunTime()
+	assertEqual(t, ok, true)
+	assertEqual(t, next, start.Add(time.Second))
+	assertEqual(t, sched.BusyWorkers(), 0)
+
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+	clock.Advance(time.Second)
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("job was not started")
+	}
+
+	// the executed job is rescheduled to its next fire
+	assertEqual(t, sched.JobCount(), 2)
+	assertEqual(t, sched.BusyWorkers(), 1)
+	deadline := time.Now().Add(time.Second)
+	for next, _ = sched.NextRunTime(); next != start.Add(2*time.Second); next, _ = sched.NextRunTime() {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected next run time %v", next)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// a blocking execution keeps the run time of its fire until the
+	// job returns and is rescheduled
+	blocking := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		BlockingExecution: true,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	if err := blocking.ScheduleJobWithKey(ctx, quartz.NewJobKey("blocking"),
+		quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			select {
+			case started <- struct{}{}:
+				<-release
+			case <-ctx.Done():
+			}
+			return true, nil
+		}), quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := blocking.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer blocking.Stop()
+	clock.Advance(time.Minute)
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("job was not started")
+	}
+	next, ok = blocking.NextRunTime()
+	assertEqual(t, ok, true)
+	assertEqual(t, next, start.Add(time.Second+time.Minute))
+	assertEqual(t, blocking.BusyWorkers(), 0)
+}
+
+func TestSchedulerConcurrentLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+	var calls int64
+	if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt64(&calls, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				switch (i + j) % 3 {
+				case 0:
+					sched.Start(ctx)
+				case 1:
+					sched.Stop()
+				default:
+					_ = sched.IsStarted()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sched.Stop()
+	sched.Wait(ctx)
+	if sched.IsStarted() {
+		t.Fatal("scheduler should be stopped")
+	}
+}
+
+func newBenchmarkScheduler(b *testing.B, size int) *quartz.StdScheduler {
+	b.Helper()
+
+	ctx := context.Background()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	job := quartz.NewShellJob("ls")
+	trigger := quartz.NewSimpleTrigger(time.Hour)
+	for i := 0; i < size; i++ {
+		key := quartz.NewJobKey(strconv.Itoa(i))
+		if err := sched.ScheduleJobWithKey(ctx, key, job, trigger); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return sched
+}
+
+func BenchmarkSchedulerGetScheduledJob(b *testing.B) {
+	for _, size := range []int{10_000, 50_000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			sched := newBenchmarkScheduler(b, size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := sched.GetScheduledJob(i % size); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerFeederBuffer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	queue := quartz.NewJobQueue()
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		FeederBuffer: 64,
+		Queue:        queue,
+		Logger:       quartz.NewNoopLogger(),
+	})
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		job := quartz.NewFunctionJob(func(context.Context) (bool, error) {
+			wg.Done()
+			return true, nil
+		})
+		err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour), quartz.WithStartNow())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	// the jobs left in the feeder buffer are queued once stopped
+	sched.Stop()
+	sched.Wait(ctx)
+	assertEqual(t, queue.Len(), 100)
+	assertEqual(t, sched.JobCount(), 100)
+}
+
+// countingClock counts the resets of the timers of the real clock.
+type countingClock struct {
+	quartz.Clock
+	resets int64
+}
+
+func (c *countingClock) NewTimer(d time.Duration) quartz.Timer {
+	return &countingTimer{Timer: c.Clock.NewTimer(d), clock: c}
+}
+
+type countingTimer struct {
+	quartz.Timer
+	clock *countingClock
+}
+
+func (t *countingTimer) Reset(d time.Duration) bool {
+	atomic.AddInt64(&t.clock.resets, 1)
+	return t.Timer.Reset(d)
+}
+
+func TestSchedulerSubMillisecondInterval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clock := &countingClock{Clock: quartz.NewRealClock()}
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  clock,
+		Logger: quartz.NewNoopLogger(),
+	})
+	var runs int64
+	trigger := quartz.NewSimpleTrigger(500 * time.Microsecond)
+	trigger.Anchored = true
+	job := quartz.NewFunctionJob(func(context.Context) (bool, error) {
+		atomic.AddInt64(&runs, 1)
+		return true, nil
+	})
+	if err := sched.ScheduleJob(ctx, job, trigger); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	sched.Stop()
+	sched.Wait(ctx)
+
+	// the execution loop waits for the sub-millisecond fires instead
+	// of spinning on the timer
+	fired := atomic.LoadInt64(&runs)
+	assertEqual(t, fired > 0, true)
+	assertEqual(t, atomic.LoadInt64(&clock.resets) <= 4*fired+10, true)
+}
+
+func TestSchedulerStartExpireTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  clock,
+		Logger: quartz.NewNoopLogger(),
+	})
+	listener := &recordingListener{}
+	sched.AddListener(listener)
+
+	// the fires before the start time are pushed to it
+	start, expire := now.Add(2*time.Hour+30*time.Minute), now.Add(4*time.Hour)
+	var runs int32
+	job := quartz.NewFunctionJob(func(context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		return true, nil
+	})
+	err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithStartTime(start), quartz.WithExpireTime(expire))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.NextRunTime, start.UnixNano())
+	assertEqual(t, scheduled.StartTime.Equal(start), true)
+	assertEqual(t, scheduled.ExpireTime.Equal(expire), true)
+
+	// a Job which would never fire is rejected
+	err = sched.ScheduleJob(ctx, quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithStartTime(expire), quartz.WithExpireTime(start))
+	if !errors.Is(err, quartz.ErrJobExpired) {
+		t.Fatal("unexpected error", err)
+	}
+	err = sched.RescheduleJob(ctx, job.Key(), quartz.NewRunOnceTriggerAt(now.Add(5*time.Hour)))
+	if !errors.Is(err, quartz.ErrJobExpired) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// the bounds are kept by the exported schedule
+	newScheduler := func() *quartz.StdScheduler {
+		return quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+			Clock:  quartz.NewMockClock(now),
+			Logger: quartz.NewNoopLogger(),
+		})
+	}
+	source, target := newScheduler(), newScheduler()
+	err = source.ScheduleJob(ctx, quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Hour),
+		quartz.WithStartTime(start), quartz.WithExpireTime(expire))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := source.ExportSchedule(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.ImportSchedule(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	imported := target.GetScheduledJobs()
+	assertEqual(t, len(imported), 1)
+	assertEqual(t, imported[0].NextRunTime, start.UnixNano())
+	assertEqual(t, imported[0].StartTime.Equal(start), true)
+	assertEqual(t, imported[0].ExpireTime.Equal(expire), true)
+
+	// the Job fires at the start time and an hour later, and is retired
+	// once its next fire time is after the expire time
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+	for sched.JobCount() > 0 {
+		clock.Advance(30 * time.Minute)
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	assertEqual(t, atomic.LoadInt32(&runs), int32(2))
+	listener.mtx.Lock()
+	defer listener.mtx.Unlock()
+	assertEqual(t, len(listener.unscheduled), 1)
+	assertEqual(t, errors.Is(listener.unscheduled[0], quartz.ErrJobExpired), true)
+}
+
+func TestSchedulerMaxQueueSize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newScheduler := func() *quartz.StdScheduler {
+		return quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+			MaxQueueSize: 2,
+			Logger:       quartz.NewNoopLogger(),
+		})
+	}
+	sched := newScheduler()
+	var runs int32
+	frequent := quartz.NewFunctionJobWithDesc("frequent", func(context.Context) (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		return true, nil
+	})
+	hourly := quartz.NewShellJob("echo hourly")
+	for _, job := range []quartz.Job{frequent, hourly} {
+		if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := sched.ScheduleJob(ctx, quartz.NewShellJob("echo daily"), quartz.NewSimpleTrigger(time.Hour))
+	if !errors.Is(err, quartz.ErrQueueFull) {
+		t.Fatal("unexpected error", err)
+	}
+	err = sched.ScheduleJobs(ctx, []quartz.JobWithTrigger{
+		{Job: quartz.NewShellJob("echo daily"), Trigger: quartz.NewSimpleTrigger(time.Hour)},
+	})
+	if !errors.Is(err, quartz.ErrQueueFull) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// replacing a scheduled Job does not grow the queue
+	err = sched.ScheduleJob(ctx, hourly, quartz.NewSimpleTrigger(time.Hour), quartz.WithReplaceExisting())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the jobs rescheduled after their fires are never rejected
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for atomic.LoadInt32(&runs) < 5 {
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	sched.Stop()
+	sched.Wait(ctx)
+	assertEqual(t, sched.JobCount(), 2)
+
+	if err := sched.DeleteJob(frequent.Key()); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleJob(ctx, quartz.NewShellJob("echo daily"), quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the imported entries beyond the limit fail the whole import
+	var buf bytes.Buffer
+	if err := sched.ExportSchedule(&buf); err != nil {
+		t.Fatal(err)
+	}
+	target := newScheduler()
+	if err := target.ScheduleJob(ctx, quartz.NewShellJob("echo weekly"), quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	err = target.ImportSchedule(ctx, &buf)
+	var batchErr *quartz.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatal("unexpected error", err)
+	}
+	assertEqual(t, len(batchErr.Errors), 1)
+	assertEqual(t, batchErr.Errors[0].Index, 1)
+	assertEqual(t, errors.Is(err, quartz.ErrQueueFull), true)
+	assertEqual(t, target.JobCount(), 1)
+}
+
+func BenchmarkSchedulerBurst(b *testing.B) {
+	const size = 1000
+	for _, buffer := range []int{0, size} {
+		b.Run(fmt.Sprintf("FeederBuffer=%d", buffer), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ctx := context.Background()
+				sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+					FeederBuffer: buffer,
+					Logger:       quartz.NewNoopLogger(),
+				})
+				var wg sync.WaitGroup
+				wg.Add(size)
+				for j := 0; j < size; j++ {
+					job := quartz.NewFunctionJob(func(context.Context) (bool, error) {
+						wg.Done()
+						return true, nil
+					})
+					err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour), quartz.WithStartNow())
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				// the whole burst fires at once, and is queued again
+				if err := sched.StartErr(ctx); err != nil {
+					b.Fatal(err)
+				}
+				wg.Wait()
+				sched.Stop()
+				sched.Wait(ctx)
+			}
+		})
+	}
+}
+
+func BenchmarkSchedulerSimultaneousFires(b *testing.B) {
+	const size = 5000
+	for _, batchSize := range []int{1, 256} {
+		b.Run(fmt.Sprintf("DispatchBatchSize=%d", batchSize), func(b *testing.B) {
+			var tail time.Duration
+			for i := 0; i < b.N; i++ {
+				ctx := context.Background()
+				sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+					DispatchBatchSize: batchSize,
+					Logger:            quartz.NewNoopLogger(),
+				})
+				var mtx sync.Mutex
+				var wg sync.WaitGroup
+				wg.Add(size)
+				for j := 0; j < size; j++ {
+					job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+						defer wg.Done()
+						execCtx, _ := quartz.ExecutionContextFrom(ctx)
+						mtx.Lock()
+						defer mtx.Unlock()
+						if lateness := execCtx.FireTime.Sub(execCtx.ScheduledTime); lateness > tail {
+							tail = lateness
+						}
+						return true, nil
+					})
+					err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour), quartz.WithStartNow())
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				// all of the jobs are due once the scheduler starts
+				if err := sched.StartErr(ctx); err != nil {
+					b.Fatal(err)
+				}
+				wg.Wait()
+				sched.Stop()
+				sched.Wait(ctx)
+			}
+			b.ReportMetric(float64(tail.Microseconds())/1000, "tail-ms")
+		})
+	}
+}
+
+func BenchmarkSchedulerDeleteJob(b *testing.B) {
+	ctx := context.Background()
+	job := quartz.NewShellJob("ls")
+	trigger := quartz.NewSimpleTrigger(time.Hour)
+	for _, size := range []int{10_000, 50_000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			sched := newBenchmarkScheduler(b, size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := i % size
+				if err := sched.DeleteJob(key); err != nil {
+					b.Fatal(err)
+				}
+				// keep the size of the queue constant
+				if err := sched.ScheduleJobWithKey(ctx, quartz.NewJobKey(strconv.Itoa(key)),
+					job, trigger); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerDuplicateKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	key := quartz.NewJobKey("duplicate")
+	if err := sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"),
+		quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	err := sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"),
+		quartz.NewSimpleTrigger(time.Minute))
+	if !errors.Is(err, quartz.ErrJobAlreadyExists) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// a rejected Trigger is not advanced
+	runOnce := quartz.NewRunOnceTrigger(time.Hour)
+	err = sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"), runOnce)
+	if !errors.Is(err, quartz.ErrJobAlreadyExists) {
+		t.Fatal("unexpected error", err)
+	}
+	if err := sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"), runOnce,
+		quartz.WithReplaceExisting()); err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the queued Job
+	if err := sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"),
+		quartz.NewSimpleTrigger(time.Minute), quartz.WithReplaceExisting()); err != nil {
+		t.Fatal(err)
+	}
+	jobs := sched.GetScheduledJobs()
+	assertEqual(t, len(jobs), 1)
+	assertEqual(t, jobs[0].TriggerDescription, quartz.NewSimpleTrigger(time.Minute).Description())
+
+	// replace the Job while it is in flight
+	running := make(chan struct{})
+	release := make(chan struct{})
+	var runs int32
+	if err := sched.ScheduleJobWithKey(ctx, key, quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			close(running)
+		}
+		<-release
+		return true, nil
+	}), quartz.NewSimpleTrigger(10*time.Millisecond), quartz.WithReplaceExisting()); err != nil {
+		t.Fatal(err)
+	}
+	<-running
+	time.Sleep(10 * time.Millisecond)
+	if err := sched.ScheduleJobWithKey(ctx, key, quartz.NewShellJob("ls"),
+		quartz.NewSimpleTrigger(time.Hour), quartz.WithReplaceExisting()); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	time.Sleep(50 * time.Millisecond)
+
+	jobs = sched.GetScheduledJobs()
+	assertEqual(t, len(jobs), 1)
+	assertEqual(t, jobs[0].TriggerDescription, quartz.NewSimpleTrigger(time.Hour).Description())
+}
+
+var errTransient = errors.New("transient")
+
+// flakyTrigger fires every interval, failing the given number of times
+// after its first fire time.
+type flakyTrigger struct {
+	mtx      sync.Mutex
+	interval time.Duration
+	fired    bool
+	failures int
+}
+
+func (ft *flakyTrigger) NextFireTime(prev int64) (int64, error) {
+	ft.mtx.Lock()
+	defer ft.mtx.Unlock()
+
+	if ft.fired && ft.failures > 0 {
+		ft.failures--
+		return 0, errTransient
+	}
+	ft.fired = true
+	return prev + ft.interval.Nanoseconds(), nil
+}
+
+func (ft *flakyTrigger) Description() string {
+	return "flaky"
+}
+
+func TestSchedulerTriggerErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		limit       int
+		failures    int
+		unscheduled error
+	}{
+		{"Expired", 0, 0, quartz.ErrTriggerExpired},
+		{"NoRetry", 0, 1, errTransient},
+		{"Exhausted", 2, 3, errTransient},
+		{"Recovered", 3, 3, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				TriggerRetryLimit:    tt.limit,
+				TriggerRetryInterval: 5 * time.Millisecond,
+				Logger:               quartz.NewNoopLogger(),
+			})
+			sched.AddListener(listener)
+			sched.Start(ctx)
+			defer sched.Stop()
+
+			var trigger quartz.Trigger = &flakyTrigger{interval: 10 * time.Millisecond, failures: tt.failures}
+			if tt.unscheduled == quartz.ErrTriggerExpired {
+				trigger = quartz.NewRunOnceTrigger(10 * time.Millisecond)
+			}
+			var runs int32
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}, trigger); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.unscheduled == nil {
+				// the job runs again once the Trigger recovers
+				for atomic.LoadInt32(&runs) < 2 {
+					if ctx.Err() != nil {
+						t.Fatal("the job should run again")
+					}
+					time.Sleep(time.Millisecond)
+				}
+				assertEqual(t, len(sched.GetJobKeys()), 1)
+				assertEqual(t, len(listener.snapshot().unscheduled), 0)
+				return
+			}
+
+			for len(listener.snapshot().unscheduled) == 0 {
+				if ctx.Err() != nil {
+					t.Fatal("the job should be unscheduled")
+				}
+				time.Sleep(time.Millisecond)
+			}
+			assertEqual(t, errors.Is(listener.snapshot().unscheduled[0], tt.unscheduled), true)
+			assertEqual(t, atomic.LoadInt32(&runs), 1)
+			assertEqual(t, len(sched.GetJobKeys()), 0)
+		})
+	}
+}
+
+func TestSchedulerTriggerRepeatCount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var runs int32
+	key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(10*time.Millisecond, quartz.WithRepeatCount(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RemainingRuns, 3)
+
+	for {
+		if _, err := sched.GetScheduledJob(key); errors.Is(err, quartz.ErrJobNotFound) &&
+			atomic.LoadInt32(&runs) == 3 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("job was not removed after %d runs", atomic.LoadInt32(&runs))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(3))
+
+	key, err = sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err = sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RemainingRuns, -1)
+	assertEqual(t, job.EndTime.IsZero(), true)
+
+	end := time.Now().Add(time.Hour)
+	key, err = sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return nil
+	}, quartz.NewRunOnceTriggerAt(time.Now().Add(time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err = sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RemainingRuns, 1)
+
+	key, err = sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute, quartz.WithEndTime(end), quartz.WithRepeatCount(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err = sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RemainingRuns, 10)
+	assertEqual(t, job.EndTime.UnixNano(), end.UnixNano())
+}
+
+func TestSchedulerScheduleAt(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock: quartz.NewMockClock(now),
+	})
+
+	job := quartz.NewFunctionJob(func(_ context.Context) (bool, error) {
+		return true, nil
+	})
+	err := sched.ScheduleAt(ctx, job, now.Add(-time.Second))
+	assertEqual(t, err, quartz.ErrFireTimeInPast)
+
+	at := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	if err := sched.ScheduleAt(ctx, job, at); err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err := sched.GetScheduledJob(job.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.NextRunTime, at.UnixNano())
+
+	// the functions scheduled in the past are fired immediately
+	key, err := sched.ScheduleOnceAt(ctx, func(_ context.Context) error {
+		return nil
+	}, now.Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err = sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.NextRunTime, now.UnixNano())
+}
+
+func TestSchedulerJobStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	successKey, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return nil
+	}, quartz.NewSimpleTrigger(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	panicKey, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		panic("boom")
+	}, quartz.NewSimpleTrigger(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errFailed := errors.New("failed")
+	failureKey, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return errFailed
+	}, quartz.NewSimpleTrigger(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := sched.GetScheduledJob(successKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RunCount, int64(0))
+	assertEqual(t, job.LastRunTime, int64(0))
+
+	time.Sleep(55 * time.Millisecond)
+
+	job, err = sched.GetScheduledJob(successKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.RunCount < 2 {
+		t.Fatal("unexpected run count", job.RunCount)
+	}
+	if job.LastRunTime == 0 || job.LastRunTime > job.NextRunTime {
+		t.Fatal("unexpected last run time", job.LastRunTime)
+	}
+	assertEqual(t, job.LastError, nil)
+
+	job, err = sched.GetScheduledJob(panicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.RunCount < 2 {
+		t.Fatal("unexpected run count", job.RunCount)
+	}
+	if job.LastError == nil {
+		t.Fatal("expected the panic to be recorded")
+	}
+
+	job, err = sched.GetScheduledJob(failureKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(job.LastError, errFailed) {
+		t.Fatal("unexpected last error", job.LastError)
+	}
+}
+
+func TestSchedulerFireContext(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.Start(ctx)
+
+		if _, ok := quartz.ScheduledJobFromContext(ctx); ok {
+			t.Fatal("unexpected ScheduledJob in the context")
+		}
+
+		fired := make(chan *quartz.ScheduledJob, 1)
+		key := quartz.NewJobKey("context")
+		job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+			scheduled, ok := quartz.ScheduledJobFromContext(ctx)
+			if !ok {
+				return false, errors.New("no ScheduledJob in the context")
+			}
+			fired <- scheduled
+			return true, nil
+		})
+		if err := sched.ScheduleJobWithKey(ctx, key, job,
+			quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case scheduled := <-fired:
+			assertEqual(t, scheduled.Key, key)
+			assertEqual(t, scheduled.Job, quartz.Job(job))
+		case <-ctx.Done():
+			t.Fatal("job did not fire")
+		}
+
+		sched.Stop()
+		sched.Wait(ctx)
+	}
+}
+
+func TestSchedulerExecutionContext(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.Start(ctx)
+
+		if _, ok := quartz.ExecutionContextFrom(ctx); ok {
+			t.Fatal("unexpected ExecutionContext in the context")
+		}
+
+		fired := make(chan *quartz.ExecutionContext, 2)
+		trigger := quartz.NewSimpleTrigger(20 * time.Millisecond)
+		key, err := sched.ScheduleFunc(ctx, func(ctx context.Context) error {
+			execCtx, ok := quartz.ExecutionContextFrom(ctx)
+			if !ok {
+				return errors.New("no ExecutionContext in the context")
+			}
+			select {
+			case fired <- execCtx:
+			default:
+			}
+			return nil
+		}, trigger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scheduled, err := sched.GetScheduledJob(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := int64(1); i <= 2; i++ {
+			select {
+			case execCtx := <-fired:
+				assertEqual(t, execCtx.Key, scheduled.Key)
+				assertEqual(t, execCtx.TriggerDescription, trigger.Description())
+				assertEqual(t, execCtx.RunCount, i)
+				assertEqual(t, execCtx.Misfire, false)
+				if execCtx.ScheduledTime.IsZero() || execCtx.FireTime.Before(execCtx.ScheduledTime) {
+					t.Fatalf("unexpected fire time %v for scheduled time %v",
+						execCtx.FireTime, execCtx.ScheduledTime)
+				}
+			case <-ctx.Done():
+				t.Fatal("job did not fire")
+			}
+		}
+
+		sched.Stop()
+		sched.Wait(ctx)
+	}
+}
+
+func TestSchedulerCancelRunningJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	var running, canceled int32
+	key, err := sched.ScheduleFunc(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&running, 1)
+		<-ctx.Done()
+		if errors.Is(ctx.Err(), context.Canceled) {
+			atomic.AddInt32(&canceled, 1)
+		}
+		return ctx.Err()
+	}, quartz.NewSimpleTrigger(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sched.CancelRunningJob(key); !errors.Is(err, quartz.ErrJobNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// let multiple concurrent runs start, then pause the Job
+	// to stop further runs
+	time.Sleep(50 * time.Millisecond)
+	sched.PauseGroup(quartz.DefaultGroup)
+	time.Sleep(10 * time.Millisecond)
+	assertEqual(t, sched.RunningJobs(), []int{key})
+	started := atomic.LoadInt32(&running)
+	if started < 2 {
+		t.Fatal("expected concurrent runs", started)
+	}
+
+	if err := sched.CancelRunningJob(key); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	assertEqual(t, atomic.LoadInt32(&canceled), started)
+	assertEqual(t, sched.RunningJobs(), []int{})
+	if !sched.IsStarted() {
+		t.Fatal("scheduler should be running")
+	}
+}
+
+type contextValueKey struct{}
+
+func TestSchedulerContextValues(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts quartz.StdSchedulerOptions
+		pool string
+	}{
+		{"Goroutine", quartz.StdSchedulerOptions{}, ""},
+		{"Blocking", quartz.StdSchedulerOptions{BlockingExecution: true}, ""},
+		{"Pool", quartz.StdSchedulerOptions{WorkerLimit: 2}, ""},
+		{"NamedPool", quartz.StdSchedulerOptions{Pools: map[string]int{"io": 1}}, "io"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			ctx := context.WithValue(timeout, contextValueKey{}, "value")
+
+			values := make(chan any, 3)
+			value := func(ctx context.Context) (bool, error) {
+				values <- ctx.Value(contextValueKey{})
+				return true, nil
+			}
+			tt.opts.Logger = quartz.NewNoopLogger()
+			tt.opts.OnStart = []quartz.Job{quartz.NewFunctionJobWithDesc("start", value)}
+			tt.opts.OnStop = []quartz.Job{quartz.NewFunctionJobWithDesc("stop", value)}
+			sched := quartz.NewStdSchedulerWithOptions(tt.opts)
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			type execution struct {
+				value    any
+				key      quartz.JobKey
+				deadline bool
+			}
+			executed := make(chan execution, 1)
+			job := quartz.NewFunctionJob(func(ctx context.Context) (bool, error) {
+				execCtx, _ := quartz.ExecutionContextFrom(ctx)
+				deadline, _ := ctx.Deadline()
+				executed <- execution{
+					value:    ctx.Value(contextValueKey{}),
+					key:      execCtx.Key,
+					deadline: deadline.Before(time.Now().Add(time.Second)),
+				}
+				return true, nil
+			})
+			opts := []quartz.ScheduleOption{quartz.WithTimeout(time.Second)}
+			if tt.pool != "" {
+				opts = append(opts, quartz.WithPool(tt.pool))
+			}
+			if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(0), opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case got := <-executed:
+				// the values of the Start context, along with the per-fire
+				// ExecutionContext and deadline
+				assertEqual(t, got, execution{
+					value:    "value",
+					key:      quartz.NewJobKey(strconv.Itoa(job.Key())),
+					deadline: true,
+				})
+			case <-timeout.Done():
+				t.Fatal(timeout.Err())
+			}
+
+			// the OnStart job signals its execution
+			select {
+			case got := <-values:
+				assertEqual(t, got, any("value"))
+			case <-timeout.Done():
+				t.Fatal(timeout.Err())
+			}
+
+			sched.Stop()
+			sched.Wait(timeout)
+			close(values)
+			var got []any
+			for value := range values {
+				got = append(got, value)
+			}
+			// the OnStop job
+			assertEqual(t, got, []any{"value"})
+		})
+	}
+}
+
+func TestSchedulerRunningExecutions(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   quartz.StdSchedulerOptions
+		pool   string
+		worker bool
+	}{
+		{"Goroutine", quartz.StdSchedulerOptions{}, "", false},
+		{"Blocking", quartz.StdSchedulerOptions{BlockingExecution: true}, "", false},
+		{"Pool", quartz.StdSchedulerOptions{WorkerLimit: 2}, "", true},
+		{"NamedPool", quartz.StdSchedulerOptions{Pools: map[string]int{"io": 1}}, "io", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			tt.opts.Logger = quartz.NewNoopLogger()
+			sched := quartz.NewStdSchedulerWithOptions(tt.opts)
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer sched.Stop()
+
+			started, release := make(chan struct{}), make(chan struct{})
+			job := quartz.NewFunctionJobWithDesc("running", func(context.Context) (bool, error) {
+				close(started)
+				<-release
+				return true, nil
+			})
+			var opts []quartz.ScheduleOption
+			if tt.pool != "" {
+				opts = append(opts, quartz.WithPool(tt.pool))
+			}
+			before := time.Now()
+			if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(0), opts...); err != nil {
+				t.Fatal(err)
+			}
+			<-started
+
+			executions := sched.RunningExecutions()
+			assertEqual(t, len(executions), 1)
+			execution := executions[0]
+			assertEqual(t, execution.Key, quartz.NewJobKey(strconv.Itoa(job.Key())))
+			assertEqual(t, execution.Description, "running")
+			assertEqual(t, execution.Pool, tt.pool)
+			assertEqual(t, execution.Worker > 0, tt.worker)
+			assertEqual(t, execution.ScheduledTime.Before(before), false)
+			assertEqual(t, execution.StartTime.Before(execution.ScheduledTime), false)
+
+			close(release)
+			for len(sched.RunningExecutions()) > 0 {
+				select {
+				case <-ctx.Done():
+					t.Fatal(ctx.Err())
+				case <-time.After(5 * time.Millisecond):
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerConcurrencyPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		policy        quartz.ConcurrencyPolicy
+		limit         int
+		maxConcurrent func(int32) bool
+		skipped       bool
+	}{
+		{"Allow", quartz.ConcurrencyAllow, 0, func(n int32) bool { return n > 1 }, false},
+		{"Skip", quartz.ConcurrencySkip, 0, func(n int32) bool { return n == 1 }, true},
+		{"Queue", quartz.ConcurrencyQueue, 0, func(n int32) bool { return n == 1 }, false},
+		{"MaxConcurrent", quartz.ConcurrencyAllow, 2, func(n int32) bool { return n == 2 }, true},
+		{"MaxConcurrentQueue", quartz.ConcurrencyQueue, 2, func(n int32) bool { return n == 2 }, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				Logger: quartz.NewNoopLogger(),
+			})
+			sched.AddListener(listener)
+			sched.Start(ctx)
+
+			var running, maxRunning, runs int32
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					prev := atomic.LoadInt32(&maxRunning)
+					if n <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, n) {
+						break
+					}
+				}
+				atomic.AddInt32(&runs, 1)
+				time.Sleep(30 * time.Millisecond)
+				return nil
+			}, quartz.NewSimpleTrigger(10*time.Millisecond),
+				quartz.WithConcurrencyPolicy(tt.policy), quartz.WithMaxConcurrent(tt.limit)); err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			sched.Stop()
+			sched.Wait(ctx)
+
+			if n := atomic.LoadInt32(&maxRunning); !tt.maxConcurrent(n) {
+				t.Error("unexpected number of concurrent runs", n)
+			}
+			if atomic.LoadInt32(&runs) < 2 {
+				t.Error("expected multiple runs")
+			}
+			skipped := len(listener.snapshot().skippedConcurrent)
+			assertEqual(t, skipped > 0, tt.skipped)
+		})
+	}
+}
+
+func TestSchedulerFairDispatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clock := quartz.NewMockClock(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		BlockingExecution: true,
+		FairDispatch:      true,
+		Clock:             clock,
+		Logger:            quartz.NewNoopLogger(),
+	})
+
+	var mtx sync.Mutex
+	var executed []string
+	record := func(name string) quartz.Job {
+		return quartz.NewFunctionJobWithDesc(name, func(context.Context) (bool, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			executed = append(executed, name)
+			return true, nil
+		})
+	}
+	for _, job := range []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"frequent", time.Second},
+		{"hourly", time.Hour},
+		{"daily", 24 * time.Hour},
+	} {
+		if err := sched.ScheduleJob(ctx, record(job.name), quartz.NewSimpleTrigger(job.interval)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sched.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	// the lagging fires of the frequent Job do not get ahead of the
+	// other due jobs
+	time.Sleep(10 * time.Millisecond)
+	clock.Advance(48 * time.Hour)
+	for {
+		mtx.Lock()
+		n := len(executed)
+		mtx.Unlock()
+		if n >= 3 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	sched.Stop()
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	assertEqual(t, executed[:3], []string{"frequent", "hourly", "daily"})
+}
+
+func TestSchedulerMaxConcurrent(t *testing.T) {
+	for name, opts := range map[string]quartz.StdSchedulerOptions{
+		"Goroutines":  {},
+		"WorkerLimit": {WorkerLimit: 4},
+		"Pools":       {Pools: map[string]int{"pool": 4}},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			opts.Logger = quartz.NewNoopLogger()
+			sched := quartz.NewStdSchedulerWithOptions(opts)
+			listener := &recordingListener{}
+			sched.AddListener(listener)
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer sched.Stop()
+
+			// the executions panic once released
+			release := make(chan struct{})
+			scheduleOpts := []quartz.ScheduleOption{quartz.WithMaxConcurrent(2)}
+			if opts.Pools != nil {
+				scheduleOpts = append(scheduleOpts, quartz.WithPool("pool"))
+			}
+			key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				<-release
+				panic("released")
+			}, quartz.NewRunOnceTrigger(time.Hour), scheduleOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			waitForRunning := func(expected int) {
+				t.Helper()
+				for {
+					job, err := sched.GetScheduledJob(key)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if job.Running == expected {
+						assertEqual(t, job.MaxConcurrent, 2)
+						return
+					}
+					select {
+					case <-ctx.Done():
+						t.Fatalf("unexpected number of running executions %d", job.Running)
+					case <-time.After(5 * time.Millisecond):
+					}
+				}
+			}
+
+			for round := 0; round < 2; round++ {
+				for i := 0; i < 2; i++ {
+					if err := sched.TriggerJob(ctx, key); err != nil {
+						t.Fatal(err)
+					}
+				}
+				waitForRunning(2)
+
+				// the fire beyond the limit is skipped
+				if err := sched.TriggerJob(ctx, key); err != nil {
+					t.Fatal(err)
+				}
+				for len(listener.snapshot().skippedConcurrent) < round+1 {
+					select {
+					case <-ctx.Done():
+						t.Fatal("the fire is not skipped")
+					case <-time.After(5 * time.Millisecond):
+					}
+				}
+				waitForRunning(2)
+
+				// the slots are released by the panicking executions
+				release <- struct{}{}
+				release <- struct{}{}
+				waitForRunning(0)
+			}
+		})
+	}
+}
+
+func TestSchedulerOutdatedThreshold(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		threshold time.Duration
+		skipped   bool
+	}{
+		{"Default", quartz.DefaultOutdatedThreshold, true},
+		{"Threshold", time.Second, false},
+		{"Disabled", quartz.OutdatedCheckDisabled, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				WorkerLimit:       1,
+				OutdatedThreshold: tt.threshold,
+				Logger:            quartz.NewNoopLogger(),
+			})
+			sched.AddListener(listener)
+
+			// the blocking job occupies the only worker, delaying
+			// the dispatch of the frequent job
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+			var runs int32
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}, quartz.NewSimpleTrigger(20*time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+
+			sched.Start(ctx)
+			time.Sleep(150 * time.Millisecond)
+			sched.Stop()
+			sched.Wait(ctx)
+
+			events := listener.snapshot()
+			assertEqual(t, len(events.skipped) > 0, tt.skipped)
+			for _, lateness := range events.lateness {
+				if lateness < 10*time.Millisecond {
+					t.Error("unexpected lateness", lateness)
+				}
+			}
+			if !tt.skipped && atomic.LoadInt32(&runs) < 3 {
+				t.Error("expected the late fires to run", atomic.LoadInt32(&runs))
+			}
+		})
+	}
+}
+
+func TestSchedulerDispatchOverflow(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		policy   quartz.OverflowPolicy
+		spilling bool
+		dropping bool
+	}{
+		{"Block", quartz.OverflowBlock, false, false},
+		{"Drop", quartz.OverflowDrop, false, true},
+		{"Spill", quartz.OverflowSpill, true, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			listener := &recordingListener{}
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				WorkerLimit:       1,
+				DispatchQueueSize: 1,
+				DispatchOverflow:  tt.policy,
+				OutdatedThreshold: quartz.OutdatedCheckDisabled,
+				Logger:            quartz.NewNoopLogger(),
+			})
+			sched.AddListener(listener)
+
+			// the blocking job occupies the only worker
+			release := make(chan struct{})
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				<-release
+				return nil
+			}, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+			var runs int32
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}, quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+
+			sched.Start(ctx)
+			time.Sleep(100 * time.Millisecond)
+			n := atomic.LoadInt32(&runs)
+			close(release)
+			sched.Stop()
+			sched.Wait(ctx)
+
+			// only the spilled fires run while the worker is busy
+			if (tt.spilling && n < 5) || (!tt.spilling && n != 0) {
+				t.Error("unexpected number of runs", n)
+			}
+			assertEqual(t, len(listener.snapshot().dropped) > 0, tt.dropping)
+		})
+	}
+}
+
+func TestSchedulerSetWorkerLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		WorkerLimit:       1,
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{Limit: 1, Workers: 1})
+
+	var running int32
+	release := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+			atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			<-release
+			return nil
+		}, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// grow the pool, so that all of the jobs run concurrently
+	time.Sleep(20 * time.Millisecond)
+	sched.SetWorkerLimit(3)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&running), int32(3))
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{Limit: 3, Workers: 3, Busy: 3})
+
+	// the excess workers exit once their jobs return
+	sched.SetWorkerLimit(1)
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{Limit: 1, Workers: 3, Busy: 3})
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{Limit: 1, Workers: 1})
+
+	// without the pool, the jobs are executed in goroutines
+	sched.SetWorkerLimit(0)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{})
+	var runs int32
+	if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(35 * time.Millisecond)
+	if atomic.LoadInt32(&runs) == 0 {
+		t.Fatal("jobs should run without the pool")
+	}
+
+	sched.SetWorkerLimit(2)
+	assertEqual(t, sched.WorkerStats().Workers, 2)
+	n := atomic.LoadInt32(&runs)
+	time.Sleep(35 * time.Millisecond)
+	if atomic.LoadInt32(&runs) <= n {
+		t.Fatal("jobs should run in the pool")
+	}
+}
+
+func TestSchedulerPools(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Pools:             map[string]int{"reports": 1, "checks": 2},
+		OutdatedThreshold: quartz.OutdatedCheckDisabled,
+		Logger:            quartz.NewNoopLogger(),
+	})
+	err := sched.ScheduleJob(ctx, quartz.NewShellJob("ls"), quartz.NewSimpleTrigger(time.Second),
+		quartz.WithPool("unknown"))
+	assertEqual(t, errors.Is(err, quartz.ErrPoolNotFound), true)
+
+	sched.Start(ctx)
+	defer sched.Stop()
+	assertEqual(t, sched.WorkerStats(), quartz.WorkerStats{Pools: map[string]quartz.WorkerStats{
+		"reports": {Limit: 1, Workers: 1},
+		"checks":  {Limit: 2, Workers: 2},
+	}})
+
+	// the reports exhaust their pool, and the blocked fire waits for
+	// the worker
+	var reports int32
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 2; i++ {
+		if _, err := sched.ScheduleFunc(ctx, func(ctx context.Context) error {
+			atomic.AddInt32(&reports, 1)
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil
+		}, quartz.NewRunOnceTrigger(time.Millisecond), quartz.WithPool("reports")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// the checks are not delayed by the reports
+	var checks int32
+	if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt32(&checks, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(5*time.Millisecond), quartz.WithPool("checks")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&checks) < 2 {
+		t.Fatal("checks should run while the reports pool is exhausted")
+	}
+	assertEqual(t, atomic.LoadInt32(&reports), int32(1))
+	assertEqual(t, sched.WorkerStats().Pools["reports"], quartz.WorkerStats{Limit: 1, Workers: 1, Busy: 1})
+	assertEqual(t, sched.BusyWorkers() >= 1, true)
+
+	release <- struct{}{}
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&reports), int32(2))
+}
+
+type tickLimiter struct {
+	ticks <-chan time.Time
+}
+
+func (l *tickLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.ticks:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestSchedulerRateLimiter(t *testing.T) {
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{WorkerLimit: 5},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+
+		listener := &recordingListener{}
+		opts.RateLimiter = &tickLimiter{ticks: ticker.C}
+		opts.Logger = quartz.NewNoopLogger()
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.AddListener(listener)
+
+		var runs int32
+		for i := 0; i < 5; i++ {
+			if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}, quartz.NewRunOnceTrigger(time.Millisecond)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sched.Start(ctx)
+		time.Sleep(50 * time.Millisecond)
+		if n := atomic.LoadInt32(&runs); n < 1 || n > 3 {
+			t.Error("unexpected number of rate limited runs", n)
+		}
+		time.Sleep(100 * time.Millisecond)
+		sched.Stop()
+		sched.Wait(ctx)
+
+		// the held back fires run late instead of being skipped
+		assertEqual(t, atomic.LoadInt32(&runs), int32(5))
+		assertEqual(t, len(listener.snapshot().skipped), 0)
+	}
+}
+
+func TestSchedulerScheduleOptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		MisfirePolicy: quartz.MisfireRescheduleNext,
+		Pools:         map[string]int{"reports": 1},
+		Clock:         clock,
+		Logger:        quartz.NewNoopLogger(),
+	})
+
+	var runs int32
+	key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute),
+		quartz.WithPaused(),
+		quartz.WithTimeout(time.Second),
+		quartz.WithPool("reports"),
+		quartz.WithMisfirePolicy(quartz.MisfireFireNow),
+		quartz.WithConcurrencyPolicy(quartz.ConcurrencySkip),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.Paused, true)
+	assertEqual(t, job.Timeout, time.Second)
+	assertEqual(t, job.Pool, "reports")
+	assertEqual(t, job.MisfirePolicy, quartz.MisfireFireNow)
+	assertEqual(t, job.ConcurrencyPolicy, quartz.ConcurrencySkip)
+
+	// the jobs without overrides use the scheduler configuration
+	defaultKey, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, _ = sched.GetScheduledJob(defaultKey)
+	assertEqual(t, job.MisfirePolicy, quartz.MisfireRescheduleNext)
+	assertEqual(t, job.Pool, "")
+
+	// the paused job is not executed
+	sched.Start(ctx)
+	defer sched.Stop()
+	clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assertEqual(t, atomic.LoadInt32(&runs), int32(0))
+}
+
+func TestSchedulerJobCallbacks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		WorkerLimit: 2,
+		Logger:      quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	type outcome struct {
+		job quartz.Job
+		err error
+	}
+	outcomes := make(chan outcome, 3)
+	onError := quartz.WithOnError(func(_ context.Context, job quartz.Job, err error) {
+		outcomes <- outcome{job, err}
+	})
+	onSuccess := quartz.WithOnSuccess(func(_ context.Context, job quartz.Job) {
+		outcomes <- outcome{job, nil}
+	})
+
+	errFailed := errors.New("failed")
+	jobs := []quartz.Job{
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) { return true, nil }),
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) { return false, errFailed }),
+		quartz.NewFunctionJob(func(_ context.Context) (bool, error) { panic("boom") }),
+	}
+	for _, job := range jobs {
+		if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(time.Millisecond),
+			onError, onSuccess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := make(map[quartz.Job]error)
+	for i := 0; i < len(jobs); i++ {
+		select {
+		case o := <-outcomes:
+			results[o.job] = o.err
+		case <-ctx.Done():
+			t.Fatal("callback was not invoked")
+		}
+	}
+	assertEqual(t, results[jobs[0]], nil)
+	assertEqual(t, errors.Is(results[jobs[1]], errFailed), true)
+	assertNotEqual(t, results[jobs[2]], nil)
+}
+
+func TestSchedulerWithJitter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	clock := quartz.NewMockClock(now)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  clock,
+		Logger: quartz.NewNoopLogger(),
+	})
+
+	fired := make(chan time.Time)
+	key, err := sched.ScheduleFunc(ctx, func(ctx context.Context) error {
+		job, _ := quartz.ScheduledJobFromContext(ctx)
+		select {
+		case fired <- time.Unix(0, job.NextRunTime):
+		case <-ctx.Done():
+		}
+		return nil
+	}, quartz.NewSimpleTrigger(time.Minute), quartz.WithJitter(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, _ := sched.GetScheduledJob(key)
+	assertEqual(t, job.Jitter, 10*time.Second)
+
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	// the jittered fire times do not drift from the Trigger
+	for i := 1; i <= 3; i++ {
+		base := now.Add(time.Duration(i) * time.Minute)
+		var next time.Time
+		deadline := time.Now().Add(time.Second)
+		for next.Before(base) && time.Now().Before(deadline) {
+			job, _ = sched.GetScheduledJob(key)
+			next = job.NextRun()
+			time.Sleep(time.Millisecond)
+		}
+		if next.Before(base) || !next.Before(base.Add(10*time.Second)) {
+			t.Fatalf("next run time %v is out of the jitter of %v", next, base)
+		}
+		clock.Advance(next.Sub(clock.Now()))
+		select {
+		case fireTime := <-fired:
+			assertEqual(t, fireTime, next)
+		case <-ctx.Done():
+			t.Fatal("job was not fired")
+		}
+	}
+
+	// the jitter applies to the new Trigger
+	if err := sched.RescheduleJob(ctx, key, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	job, _ = sched.GetScheduledJob(key)
+	base := clock.Now().Add(time.Hour)
+	if next := job.NextRun(); next.Before(base) || !next.Before(base.Add(10*time.Second)) {
+		t.Fatalf("next run time %v is out of the jitter of %v", next, base)
+	}
+}
+
+func TestSchedulerWithStartNow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	cronTrigger, err := quartz.NewCronTrigger("0 0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		trigger quartz.Trigger
+		next    time.Time
+	}{
+		{"cron", cronTrigger, time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{"simple", quartz.NewSimpleTrigger(time.Hour), now.Add(time.Hour + 5*time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			clock := quartz.NewMockClock(now)
+			sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+				OutdatedThreshold: time.Second,
+				Clock:             clock,
+				Logger:            quartz.NewNoopLogger(),
+			})
+
+			var runs int32
+			key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}, tt.trigger, quartz.WithStartNow())
+			if err != nil {
+				t.Fatal(err)
+			}
+			scheduled, err := sched.GetScheduledJob(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, scheduled.NextRunTime, now.UnixNano())
+
+			// the immediate fire is not outdated when the scheduler starts late
+			clock.Advance(5 * time.Minute)
+			sched.Start(ctx)
+			defer sched.Stop()
+			time.Sleep(50 * time.Millisecond)
+
+			assertEqual(t, atomic.LoadInt32(&runs), int32(1))
+			scheduled, err = sched.GetScheduledJob(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, time.Unix(0, scheduled.NextRunTime).UTC(), tt.next)
+		})
+	}
+}
+
+// describedJob overrides the description of the wrapped Job.
+type describedJob struct {
+	quartz.Job
+	desc string
+}
+
+func (j *describedJob) Description() string {
+	return j.desc
+}
+
+func TestSchedulerJobWrappers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mtx sync.Mutex
+	var record []string
+	appendRecord := func(entry string) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		record = append(record, entry)
+	}
+	wrapper := func(name string) func(quartz.Job) quartz.Job {
+		return func(job quartz.Job) quartz.Job {
+			return quartz.WrapJob(job, func(ctx context.Context, job quartz.Job) error {
+				execCtx, ok := quartz.ExecutionContextFrom(ctx)
+				if !ok {
+					t.Error("missing execution context")
+				}
+				appendRecord(fmt.Sprintf("%s>%d", name, execCtx.RunCount))
+				defer appendRecord("<" + name)
+				return job.Execute(ctx)
+			})
+		}
+	}
+
+	logger := &recordingLogger{}
+	done := make(chan struct{})
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: logger,
+		JobWrappers: []func(quartz.Job) quartz.Job{
+			wrapper("outer"),
+			wrapper("inner"),
+			func(job quartz.Job) quartz.Job {
+				return &describedJob{Job: job, desc: "described " + job.Description()}
+			},
+		},
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	job := quartz.NewFunctionJobWithDesc("failing", func(_ context.Context) (bool, error) {
+		defer close(done)
+		appendRecord("job")
+		return false, errors.New("failed")
+	})
+	if err := sched.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(0)); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	time.Sleep(10 * time.Millisecond)
+
+	mtx.Lock()
+	assertEqual(t, record, []string{"outer>1", "inner>1", "job", "<inner", "<outer"})
+	mtx.Unlock()
+
+	// the wrappers pass through the key, and can override the description
+	assertEqual(t, quartz.WrapJob(job, nil).Key(), job.Key())
+	assertEqual(t, quartz.WrapJob(job, nil).Description(), "failing")
+	assertEqual(t, logger.contains("described failing"), true)
+}
+
+func TestSchedulerExecutionHook(t *testing.T) {
+	type spanKey struct{}
+	for _, opts := range []quartz.StdSchedulerOptions{
+		{BlockingExecution: true},
+		{WorkerLimit: 2},
+		{},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		finished := make(chan error, 2)
+		opts.Logger = quartz.NewNoopLogger()
+		opts.ExecutionHook = func(ctx context.Context, job quartz.ScheduledJob) (context.Context, func(error)) {
+			if _, ok := quartz.ExecutionContextFrom(ctx); !ok {
+				t.Error("missing execution context")
+			}
+			return context.WithValue(ctx, spanKey{}, job.Key.String()), func(err error) {
+				finished <- err
+			}
+		}
+		sched := quartz.NewStdSchedulerWithOptions(opts)
+		sched.Start(ctx)
+
+		job := func(ctx context.Context) (bool, error) {
+			scheduled, _ := quartz.ScheduledJobFromContext(ctx)
+			if span, _ := ctx.Value(spanKey{}).(string); span != scheduled.Key.String() {
+				return false, fmt.Errorf("unexpected span %q", span)
+			}
+			return false, errors.New("failed")
+		}
+		if err := sched.ScheduleJob(ctx, quartz.NewFunctionJob(job),
+			quartz.NewRunOnceTrigger(0)); err != nil {
+			t.Fatal(err)
+		}
+		manual := quartz.NewFunctionJob(job)
+		if err := sched.ScheduleJob(ctx, manual, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if err := sched.TriggerJob(ctx, manual.Key()); err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, (<-finished).Error(), "failed")
+		assertEqual(t, (<-finished).Error(), "failed")
+		sched.Stop()
+	}
+}
@@ -0,0 +1,395 @@
+package quartz
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SimulationScheduler implements the quartz.Scheduler interface on a
+// simulated clock, for testing the schedules without waiting for them.
+// The jobs are executed synchronously by RunUntil and Advance, in the
+// order of their fire times, and by their priority and the order they
+// were queued in for the same fire time, invoking the listener callbacks
+// as the StdScheduler does.
+//
+// Jobs may schedule, reschedule and delete jobs, including themselves,
+// while they are executed: the changes take effect for the rest of the
+// simulated window. The ScheduleOptions are recorded on the scheduled
+// jobs, while only WithReplaceExisting, WithPaused, WithStartNow,
+// WithPriority and the callbacks affect the simulation.
+type SimulationScheduler struct {
+	mtx       sync.Mutex
+	now       time.Time
+	ctx       context.Context
+	started   bool
+	index     map[JobKey]*item
+	order     map[*item]uint64
+	seq       uint64
+	listeners []SchedulerListener
+}
+
+// Verify SimulationScheduler satisfies the Scheduler interface.
+var _ Scheduler = (*SimulationScheduler)(nil)
+
+// NewSimulationScheduler returns a new SimulationScheduler whose simulated
+// clock starts at the given time.
+func NewSimulationScheduler(start time.Time) *SimulationScheduler {
+	return &SimulationScheduler{
+		now:   start,
+		ctx:   context.Background(),
+		index: make(map[JobKey]*item),
+		order: make(map[*item]uint64),
+	}
+}
+
+// Now returns the current time of the simulated clock.
+func (sim *SimulationScheduler) Now() time.Time {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	return sim.now
+}
+
+// AddListener registers the listener to be notified of the events of the
+// SimulationScheduler, from the goroutine running the simulation.
+func (sim *SimulationScheduler) AddListener(listener SchedulerListener) {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	sim.listeners = append(sim.listeners, listener)
+}
+
+// Start starts the simulation. The context is passed to the executed
+// jobs, and the simulation is stopped once it is done.
+func (sim *SimulationScheduler) Start(ctx context.Context) {
+	_ = sim.StartErr(ctx)
+}
+
+// StartErr starts the simulation, as Start does, and returns
+// ErrSchedulerAlreadyStarted if the simulation is running.
+func (sim *SimulationScheduler) StartErr(ctx context.Context) error {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	if sim.isRunning() {
+		return ErrSchedulerAlreadyStarted
+	}
+	sim.ctx = ctx
+	sim.started = true
+
+	return nil
+}
+
+// IsStarted determines whether the simulation has been started.
+func (sim *SimulationScheduler) IsStarted() bool {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	return sim.isRunning()
+}
+
+// isRunning reports whether the simulation is started and its context
+// is not done. The caller must hold the lock.
+func (sim *SimulationScheduler) isRunning() bool {
+	return sim.started && sim.ctx.Err() == nil
+}
+
+// ScheduleJob schedules the Job using the Trigger, from the current time
+// of the simulated clock.
+func (sim *SimulationScheduler) ScheduleJob(
+	ctx context.Context,
+	job Job,
+	trigger Trigger,
+	opts ...ScheduleOption,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	options := newScheduleOptions(opts)
+	it := &item{
+		Job:     job,
+		Trigger: trigger,
+		key:     intJobKey(job.Key()),
+		opts:    options,
+		paused:  options.paused,
+	}
+
+	sim.mtx.Lock()
+	existing := sim.index[it.key]
+	if existing != nil && !options.replace {
+		sim.mtx.Unlock()
+		return ErrJobAlreadyExists
+	}
+
+	it.priority = sim.now.UnixNano()
+	if !options.startNow {
+		nextRunTime, err := trigger.NextFireTime(it.priority)
+		if err != nil {
+			sim.mtx.Unlock()
+			return err
+		}
+		it.priority = nextRunTime
+	}
+	if existing != nil {
+		delete(sim.order, existing)
+	}
+	sim.seq++
+	sim.index[it.key] = it
+	sim.order[it] = sim.seq
+	scheduled := *it.scheduledJob()
+	sim.mtx.Unlock()
+
+	sim.notify(func(l SchedulerListener) { l.JobScheduled(scheduled) })
+	return nil
+}
+
+// GetJobKeys returns the keys of all of the scheduled jobs.
+func (sim *SimulationScheduler) GetJobKeys() []int {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	keys := make([]int, 0, len(sim.index))
+	for _, it := range sim.items() {
+		if key, ok := it.key.intKey(); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// GetScheduledJob returns the ScheduledJob with the specified key.
+func (sim *SimulationScheduler) GetScheduledJob(key int) (*ScheduledJob, error) {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	if it, ok := sim.index[intJobKey(key)]; ok {
+		return it.scheduledJob(), nil
+	}
+
+	return nil, ErrJobNotFound
+}
+
+// GetScheduledJobs returns a snapshot of all of the scheduled jobs,
+// ordered by their next run time.
+func (sim *SimulationScheduler) GetScheduledJobs() []*ScheduledJob {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	jobs := make([]*ScheduledJob, 0, len(sim.index))
+	for _, it := range sim.items() {
+		jobs = append(jobs, it.scheduledJob())
+	}
+
+	return jobs
+}
+
+// DeleteJob removes the Job with the specified key.
+func (sim *SimulationScheduler) DeleteJob(key int) error {
+	sim.mtx.Lock()
+	it, ok := sim.index[intJobKey(key)]
+	if ok {
+		sim.remove(it)
+	}
+	sim.mtx.Unlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	sim.notify(func(l SchedulerListener) { l.JobDeleted(it.key) })
+	return nil
+}
+
+// Clear removes all of the scheduled jobs.
+func (sim *SimulationScheduler) Clear() {
+	sim.mtx.Lock()
+	items := sim.items()
+	for _, it := range items {
+		sim.remove(it)
+	}
+	sim.mtx.Unlock()
+
+	for _, it := range items {
+		key := it.key
+		sim.notify(func(l SchedulerListener) { l.JobDeleted(key) })
+	}
+}
+
+// Wait returns at once, as the jobs are executed synchronously.
+func (sim *SimulationScheduler) Wait(context.Context) {}
+
+// Stop stops the simulation. The jobs are no longer executed until the
+// simulation is started again.
+func (sim *SimulationScheduler) Stop() {
+	_ = sim.StopErr()
+}
+
+// StopErr stops the simulation, as Stop does, and returns
+// ErrSchedulerNotStarted if the simulation was not running.
+func (sim *SimulationScheduler) StopErr() error {
+	sim.mtx.Lock()
+	defer sim.mtx.Unlock()
+
+	running := sim.isRunning()
+	sim.started = false
+	if !running {
+		return ErrSchedulerNotStarted
+	}
+
+	return nil
+}
+
+// Advance advances the simulated clock by the duration, executing the
+// jobs which are due in the meantime.
+func (sim *SimulationScheduler) Advance(d time.Duration) {
+	sim.RunUntil(sim.Now().Add(d))
+}
+
+// RunUntil advances the simulated clock to the given time, executing the
+// jobs which are due until then, including the fires which are due at the
+// given time. The clock is set to the fire time of each of the executions.
+// Unless the simulation is started, the clock is advanced without
+// executing the jobs, which are executed once due after the start.
+func (sim *SimulationScheduler) RunUntil(t time.Time) {
+	for {
+		sim.mtx.Lock()
+		it := sim.head()
+		if !sim.isRunning() || it == nil || it.priority > t.UnixNano() {
+			if t.After(sim.now) {
+				sim.now = t
+			}
+			sim.mtx.Unlock()
+			return
+		}
+		fireTime := time.Unix(0, it.priority).In(sim.now.Location())
+		if fireTime.After(sim.now) {
+			sim.now = fireTime
+		}
+		ctx := sim.ctx
+		job := it.scheduledJob()
+		sim.mtx.Unlock()
+
+		if !job.Paused {
+			sim.execute(ctx, it, job)
+		}
+		sim.reschedule(it, job)
+	}
+}
+
+// execute runs the fire of the Job, notifying the listeners before and
+// after the execution.
+func (sim *SimulationScheduler) execute(ctx context.Context, it *item, job *ScheduledJob) {
+	sim.notify(func(l SchedulerListener) { l.BeforeJobExecution(*job) })
+
+	sim.mtx.Lock()
+	it.stats.lastRunTime = sim.now.UnixNano()
+	it.stats.runCount++
+	runCount := it.stats.runCount
+	sim.mtx.Unlock()
+
+	ctx = context.WithValue(ctx, scheduledJobKey{}, *job)
+	ctx = withExecutionContext(ctx, &ExecutionContext{
+		Key:                job.Key,
+		TriggerDescription: job.TriggerDescription,
+		ScheduledTime:      time.Unix(0, job.NextRunTime),
+		FireTime:           time.Unix(0, job.NextRunTime),
+		RunCount:           runCount,
+		Data:               job.Data,
+		job:                job,
+		notify:             sim.notify,
+	})
+	err := executeJob(ctx, job.Job)
+
+	sim.mtx.Lock()
+	it.stats.lastCompletedTime = sim.now.UnixNano()
+	it.stats.lastError = err
+	sim.mtx.Unlock()
+
+	sim.notify(func(l SchedulerListener) { l.AfterJobExecution(*job, 0, err) })
+	_ = it.opts.callback(ctx, job.Job, err)
+}
+
+// reschedule advances the executed item to the next fire time of its
+// Trigger, unless the item was removed or replaced by the execution.
+func (sim *SimulationScheduler) reschedule(it *item, job *ScheduledJob) {
+	sim.mtx.Lock()
+	if sim.index[it.key] != it {
+		sim.mtx.Unlock()
+		return
+	}
+
+	nextRunTime, err := it.Trigger.NextFireTime(it.priority)
+	if err != nil {
+		sim.remove(it)
+		sim.mtx.Unlock()
+		sim.notify(func(l SchedulerListener) { l.JobUnscheduled(*job, err) })
+		return
+	}
+	it.priority = nextRunTime
+	sim.seq++
+	sim.order[it] = sim.seq
+	sim.mtx.Unlock()
+}
+
+// head returns the item with the earliest fire time, the first one by
+// the priority and the queuing order among the items with the same fire
+// time. The caller must hold the lock.
+func (sim *SimulationScheduler) head() *item {
+	var head *item
+	for it := range sim.order {
+		if head == nil || sim.before(it, head) {
+			head = it
+		}
+	}
+
+	return head
+}
+
+// before reports whether the item fires before the other one, as ordered
+// by the JobQueue. The caller must hold the lock.
+func (sim *SimulationScheduler) before(it, other *item) bool {
+	switch {
+	case it.priority != other.priority:
+		return it.priority < other.priority
+	case it.opts.priority != other.opts.priority:
+		return it.opts.priority > other.opts.priority
+	default:
+		return sim.order[it] < sim.order[other]
+	}
+}
+
+// items returns the scheduled items ordered by their fire times. The
+// caller must hold the lock.
+func (sim *SimulationScheduler) items() []*item {
+	items := make([]*item, 0, len(sim.order))
+	for it := range sim.order {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return sim.before(items[i], items[j])
+	})
+
+	return items
+}
+
+// remove removes the item. The caller must hold the lock.
+func (sim *SimulationScheduler) remove(it *item) {
+	delete(sim.index, it.key)
+	delete(sim.order, it)
+}
+
+// notify invokes the callback for each of the listeners, outside of the
+// lock.
+func (sim *SimulationScheduler) notify(callback func(SchedulerListener)) {
+	sim.mtx.Lock()
+	listeners := sim.listeners
+	sim.mtx.Unlock()
+
+	for _, listener := range listeners {
+		callback(listener)
+	}
+}
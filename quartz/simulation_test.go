@@ -0,0 +1,146 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSimulationScheduler(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sim := quartz.NewSimulationScheduler(start)
+	listener := &recordingListener{}
+	sim.AddListener(listener)
+
+	var fires []string
+	record := func(name string) quartz.Job {
+		return quartz.NewFunctionJobWithDesc(name, func(ctx context.Context) (bool, error) {
+			job, _ := quartz.ScheduledJobFromContext(ctx)
+			assertEqual(t, sim.Now().UnixNano(), job.NextRunTime)
+			fires = append(fires, name)
+			return true, nil
+		})
+	}
+
+	twiceDaily := record("twice-daily")
+	if err := sim.ScheduleJob(ctx, twiceDaily, quartz.NewSimpleTrigger(12*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	daily, err := quartz.NewCronTrigger("0 0 0 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.ScheduleJob(ctx, record("daily"), daily); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.ScheduleJob(ctx, twiceDaily, daily); !errors.Is(err, quartz.ErrJobAlreadyExists) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// the jobs are not executed until the simulation is started
+	sim.Advance(time.Hour)
+	assertEqual(t, len(fires), 0)
+	if err := sim.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sim.RunUntil(start.Add(30 * 24 * time.Hour))
+	assertEqual(t, sim.Now(), start.Add(30*24*time.Hour))
+	counts := make(map[string]int)
+	for _, name := range fires {
+		counts[name]++
+	}
+	assertEqual(t, counts, map[string]int{"twice-daily": 60, "daily": 30})
+
+	// the jobs due at the same time fire in the order they were
+	// queued in, the daily job being queued before the second fire
+	// of the twice daily one
+	assertEqual(t, fires[:4], []string{"twice-daily", "daily", "twice-daily", "twice-daily"})
+	assertEqual(t, len(listener.before), 90)
+	assertEqual(t, len(listener.after), 90)
+
+	job, err := sim.GetScheduledJob(twiceDaily.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, job.RunCount, int64(60))
+	assertEqual(t, job.NextRunTime, start.Add(30*24*time.Hour+12*time.Hour).UnixNano())
+
+	if err := sim.StopErr(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Stop()
+	if err := sim.StopErr(); !errors.Is(err, quartz.ErrSchedulerNotStarted) {
+		t.Fatal("unexpected error", err)
+	}
+	sim.Advance(24 * time.Hour)
+	assertEqual(t, len(fires), 90)
+	sim.Clear()
+	assertEqual(t, len(sim.GetScheduledJobs()), 0)
+	assertEqual(t, len(listener.deleted), 2)
+}
+
+func TestSimulationSchedulerReschedulingJobs(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sim := quartz.NewSimulationScheduler(start)
+	listener := &recordingListener{}
+	sim.AddListener(listener)
+	if err := sim.StartErr(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the job schedules its follow-up, doubling the delay each time
+	var fireTimes []time.Time
+	var job quartz.Job
+	delay := time.Minute
+	job = quartz.NewFunctionJobWithDesc("backoff", func(ctx context.Context) (bool, error) {
+		fireTimes = append(fireTimes, sim.Now())
+		delay *= 2
+		return true, sim.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(delay), quartz.WithReplaceExisting())
+	})
+	if err := sim.ScheduleJob(ctx, job, quartz.NewRunOnceTrigger(delay)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the job deletes itself after its third fire
+	var runs int
+	var self quartz.Job
+	self = quartz.NewFunctionJobWithDesc("self", func(_ context.Context) (bool, error) {
+		runs++
+		if runs == 3 {
+			return true, sim.DeleteJob(self.Key())
+		}
+		return true, nil
+	})
+	if err := sim.ScheduleJob(ctx, self, quartz.NewSimpleTrigger(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.ScheduleJob(ctx, quartz.NewShellJob("ls"), quartz.NewRunOnceTrigger(time.Hour),
+		quartz.WithPaused()); err != nil {
+		t.Fatal(err)
+	}
+
+	sim.Advance(20 * time.Minute)
+	assertEqual(t, fireTimes, []time.Time{
+		start.Add(time.Minute),
+		start.Add(3 * time.Minute),
+		start.Add(7 * time.Minute),
+		start.Add(15 * time.Minute),
+	})
+	assertEqual(t, runs, 3)
+	assertEqual(t, listener.deleted, []quartz.JobKey{quartz.NewJobKey(strconv.Itoa(self.Key()))})
+	assertEqual(t, len(listener.unscheduled), 0)
+
+	// the paused job is not executed, and its expired Trigger
+	// unschedules it
+	sim.Advance(time.Hour)
+	assertEqual(t, len(listener.unscheduled), 1)
+	assertEqual(t, errors.Is(listener.unscheduled[0], quartz.ErrTriggerExpired), true)
+	assertEqual(t, len(sim.GetJobKeys()), 1)
+}
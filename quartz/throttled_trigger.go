@@ -0,0 +1,102 @@
+package quartz
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ThrottledTrigger implements the quartz.Trigger interface; wraps a Trigger,
+// delaying its fire times so that the consecutive fires are at least the
+// minimum gap apart.
+type ThrottledTrigger struct {
+	trigger Trigger
+	minGap  time.Duration
+
+	mtx       sync.Mutex
+	last      int64 // the last returned fire time
+	lastFired int64 // the last fire time followed by a NextFireTime call
+	started   bool
+	fired     bool
+	delay     time.Duration
+}
+
+// Verify ThrottledTrigger satisfies the WakeableTrigger interface.
+var _ WakeableTrigger = (*ThrottledTrigger)(nil)
+
+// Verify ThrottledTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*ThrottledTrigger)(nil)
+
+// NewThrottledTrigger returns a new ThrottledTrigger wrapping the given
+// Trigger.
+func NewThrottledTrigger(trigger Trigger, minGap time.Duration) *ThrottledTrigger {
+	return &ThrottledTrigger{
+		trigger: trigger,
+		minGap:  minGap,
+	}
+}
+
+// NextFireTime returns the next time at which the ThrottledTrigger is scheduled to fire.
+// The fire time is the later of the next fire time of the wrapped Trigger and the last
+// fire time plus the minimum gap. A call with prev before the last returned fire time
+// recalculates it, so that the superseded fire time is not taken into account.
+func (tt *ThrottledTrigger) NextFireTime(prev int64) (int64, error) {
+	tt.mtx.Lock()
+	defer tt.mtx.Unlock()
+
+	if tt.started && prev >= tt.last {
+		tt.lastFired = tt.last
+		tt.fired = true
+	}
+
+	next, err := tt.trigger.NextFireTime(prev)
+	if err != nil {
+		return 0, err
+	}
+
+	tt.delay = 0
+	if tt.fired {
+		earliest := tt.lastFired + tt.minGap.Nanoseconds()
+		if earliest < tt.lastFired {
+			earliest = math.MaxInt64
+		}
+		if next < earliest {
+			tt.delay = time.Duration(earliest - next)
+			next = earliest
+		}
+	}
+	tt.last = next
+	tt.started = true
+
+	return next, nil
+}
+
+// SetWakeup registers the wakeup function with the wrapped Trigger, if it
+// is a WakeableTrigger.
+func (tt *ThrottledTrigger) SetWakeup(wakeup func()) {
+	if wakeable, ok := tt.trigger.(WakeableTrigger); ok {
+		wakeable.SetWakeup(wakeup)
+	}
+}
+
+// RemainingRepeats returns the remaining repeats of the wrapped Trigger.
+func (tt *ThrottledTrigger) RemainingRepeats() (int, bool) {
+	return remainingRepeats(tt.trigger)
+}
+
+// EndTime returns the end time of the wrapped Trigger, and false if it
+// has none.
+func (tt *ThrottledTrigger) EndTime() (time.Time, bool) {
+	return triggerEndTime(tt.trigger)
+}
+
+// Description returns the description of the trigger, including the delay
+// of the last returned fire time.
+func (tt *ThrottledTrigger) Description() string {
+	tt.mtx.Lock()
+	defer tt.mtx.Unlock()
+
+	return fmt.Sprintf("%s throttled with min gap: %s, delay: %s",
+		tt.trigger.Description(), tt.minGap, tt.delay)
+}
@@ -0,0 +1,140 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// sequenceTrigger returns the fire times of the sequence, regardless of
+// prev.
+type sequenceTrigger struct {
+	next []int64
+}
+
+func (st *sequenceTrigger) NextFireTime(_ int64) (int64, error) {
+	next := st.next[0]
+	st.next = st.next[1:]
+	return next, nil
+}
+
+func (st *sequenceTrigger) Description() string {
+	return "sequenceTrigger"
+}
+
+func TestThrottledTrigger(t *testing.T) {
+	second := int64(time.Second)
+	inner := &sequenceTrigger{next: []int64{
+		fromEpoch + second,
+		fromEpoch + second + second/5,
+		fromEpoch + 3*second,
+		fromEpoch + second/2, // in the past
+	}}
+	trigger := quartz.NewThrottledTrigger(inner, time.Second)
+	assertEqual(t, trigger.Description(), "sequenceTrigger throttled with min gap: 1s, delay: 0s")
+
+	prev := fromEpoch
+	for _, expected := range []int64{
+		fromEpoch + second,
+		fromEpoch + 2*second,
+		fromEpoch + 3*second,
+		fromEpoch + 4*second,
+	} {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, expected)
+		prev = next
+	}
+	assertEqual(t, trigger.Description(), "sequenceTrigger throttled with min gap: 1s, delay: 3.5s")
+}
+
+func TestThrottledTriggerSuperseded(t *testing.T) {
+	second := int64(time.Second)
+	inner := &sequenceTrigger{next: []int64{
+		fromEpoch + 10*second,
+		fromEpoch + second,
+		fromEpoch + 2*second,
+	}}
+	trigger := quartz.NewThrottledTrigger(inner, time.Minute)
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+10*second)
+
+	// the recalculation does not count the superseded fire time
+	next, err = trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+second)
+
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+second+int64(time.Minute))
+}
+
+func TestThrottledTriggerLimits(t *testing.T) {
+	trigger := quartz.NewThrottledTrigger(quartz.NewSimpleTrigger(time.Second, quartz.WithRepeatCount(1)),
+		time.Minute)
+	remaining, limited := trigger.RemainingRepeats()
+	assertEqual(t, remaining, 1)
+	assertEqual(t, limited, true)
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestSchedulerThrottledTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clock := quartz.NewMockClock(time.Unix(0, fromEpoch))
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock:  clock,
+		Logger: quartz.NewNoopLogger(),
+	})
+	sched.Start(ctx)
+	defer sched.Stop()
+
+	runs := make(chan struct{}, 8)
+	external := quartz.NewExternalTrigger()
+	trigger := quartz.NewThrottledTrigger(
+		quartz.NewUnionTrigger(quartz.NewSimpleTrigger(24*time.Hour), external),
+		time.Second,
+	)
+	if _, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+		runs <- struct{}{}
+		return nil
+	}, trigger); err != nil {
+		t.Fatal(err)
+	}
+
+	expectRun := func(expected bool) {
+		t.Helper()
+		timeout := 50 * time.Millisecond
+		if expected {
+			timeout = 5 * time.Second
+		}
+		select {
+		case <-runs:
+			if !expected {
+				t.Fatal("unexpected run")
+			}
+		case <-time.After(timeout):
+			if expected {
+				t.Fatal("expected run")
+			}
+		}
+	}
+
+	external.Fire()
+	expectRun(true)
+
+	// the second event is delayed by the min gap
+	external.Fire()
+	expectRun(false)
+	clock.Advance(time.Second)
+	expectRun(true)
+}
@@ -0,0 +1,148 @@
+package quartz
+
+import "time"
+
+// TimeTrigger is the time.Time based version of the Trigger interface.
+// A TimeTrigger is scheduled using the TimeTriggerAdapter, while the
+// NanoTriggerAdapter exposes a Trigger as a TimeTrigger.
+type TimeTrigger interface {
+	// NextFireTime returns the next time at which the Trigger is scheduled to fire.
+	NextFireTime(prev time.Time) (time.Time, error)
+
+	// Description returns the description of the Trigger.
+	Description() string
+}
+
+// locatedTrigger is implemented by the Triggers evaluated in a location.
+type locatedTrigger interface {
+	Location() *time.Location
+}
+
+// triggerLocation returns the location of the Trigger, or nil if the
+// Trigger is not evaluated in a location.
+func triggerLocation(trigger interface{}) *time.Location {
+	if t, ok := trigger.(locatedTrigger); ok {
+		return t.Location()
+	}
+
+	return nil
+}
+
+// TimeTriggerAdapter implements the quartz.Trigger interface; adapts a
+// TimeTrigger, so that it can be scheduled.
+type TimeTriggerAdapter struct {
+	trigger  TimeTrigger
+	location *time.Location
+}
+
+// Verify TimeTriggerAdapter satisfies the Trigger interface.
+var _ Trigger = (*TimeTriggerAdapter)(nil)
+
+// Verify TimeTriggerAdapter satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*TimeTriggerAdapter)(nil)
+
+// NewTimeTriggerAdapter returns a new TimeTriggerAdapter wrapping the given
+// TimeTrigger. The previous fire times are passed to the TimeTrigger in the
+// location, a nil location defaults to the location of the TimeTrigger, if
+// it has one, or UTC.
+func NewTimeTriggerAdapter(trigger TimeTrigger, location *time.Location) *TimeTriggerAdapter {
+	if location == nil {
+		location = triggerLocation(trigger)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &TimeTriggerAdapter{
+		trigger:  trigger,
+		location: location,
+	}
+}
+
+// NextFireTime returns the next time at which the wrapped TimeTrigger is scheduled
+// to fire.
+func (ta *TimeTriggerAdapter) NextFireTime(prev int64) (int64, error) {
+	next, err := ta.trigger.NextFireTime(time.Unix(0, prev).In(ta.location))
+	if err != nil {
+		return 0, err
+	}
+
+	return next.UnixNano(), nil
+}
+
+// Location returns the location of the TimeTriggerAdapter.
+func (ta *TimeTriggerAdapter) Location() *time.Location {
+	return ta.location
+}
+
+// RemainingRepeats returns the remaining repeats of the wrapped TimeTrigger.
+func (ta *TimeTriggerAdapter) RemainingRepeats() (int, bool) {
+	if t, ok := ta.trigger.(interface{ RemainingRepeats() (int, bool) }); ok {
+		return t.RemainingRepeats()
+	}
+
+	return 0, false
+}
+
+// EndTime returns the end time of the wrapped TimeTrigger, and false if
+// it has none.
+func (ta *TimeTriggerAdapter) EndTime() (time.Time, bool) {
+	if t, ok := ta.trigger.(interface{ EndTime() (time.Time, bool) }); ok {
+		return t.EndTime()
+	}
+
+	return time.Time{}, false
+}
+
+// Description returns the description of the wrapped TimeTrigger.
+func (ta *TimeTriggerAdapter) Description() string {
+	return ta.trigger.Description()
+}
+
+// NanoTriggerAdapter implements the quartz.TimeTrigger interface; adapts a
+// Trigger to the time.Time based interface.
+type NanoTriggerAdapter struct {
+	trigger  Trigger
+	location *time.Location
+}
+
+// Verify NanoTriggerAdapter satisfies the TimeTrigger interface.
+var _ TimeTrigger = (*NanoTriggerAdapter)(nil)
+
+// NewNanoTriggerAdapter returns a new NanoTriggerAdapter wrapping the given
+// Trigger. The fire times are returned in the location, a nil location
+// defaults to the location of the Trigger, if it has one, or UTC.
+func NewNanoTriggerAdapter(trigger Trigger, location *time.Location) *NanoTriggerAdapter {
+	if location == nil {
+		location = triggerLocation(trigger)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &NanoTriggerAdapter{
+		trigger:  trigger,
+		location: location,
+	}
+}
+
+// NextFireTime returns the next time at which the wrapped Trigger is scheduled
+// to fire.
+func (na *NanoTriggerAdapter) NextFireTime(prev time.Time) (time.Time, error) {
+	next, err := na.trigger.NextFireTime(prev.UnixNano())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, next).In(na.location), nil
+}
+
+// Location returns the location of the NanoTriggerAdapter.
+func (na *NanoTriggerAdapter) Location() *time.Location {
+	return na.location
+}
+
+// Description returns the description of the wrapped Trigger.
+func (na *NanoTriggerAdapter) Description() string {
+	return na.trigger.Description()
+}
@@ -0,0 +1,103 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// nextHourTrigger fires at the start of the next hour, on the wall clock
+// of the previous fire time.
+type nextHourTrigger struct{}
+
+func (nextHourTrigger) NextFireTime(prev time.Time) (time.Time, error) {
+	return time.Date(prev.Year(), prev.Month(), prev.Day(), prev.Hour()+1, 0, 0, 0, prev.Location()), nil
+}
+
+func (nextHourTrigger) Description() string {
+	return "nextHourTrigger"
+}
+
+func TestTimeTriggerAdapter(t *testing.T) {
+	// India Standard Time is offset by half an hour
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trigger := quartz.NewTimeTriggerAdapter(nextHourTrigger{}, loc)
+	assertEqual(t, trigger.Description(), "nextHourTrigger")
+	assertEqual(t, trigger.Location(), loc)
+
+	prev := time.Date(2024, 6, 1, 10, 15, 0, 0, loc)
+	next, err := trigger.NextFireTime(prev.UnixNano())
+	assertEqual(t, err, nil)
+	assertEqual(t, next, time.Date(2024, 6, 1, 11, 0, 0, 0, loc).UnixNano())
+
+	trigger = quartz.NewTimeTriggerAdapter(nextHourTrigger{}, nil)
+	assertEqual(t, trigger.Location(), time.UTC)
+}
+
+func TestNanoTriggerAdapter(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cronTrigger, err := quartz.NewCronTriggerWithLoc("0 0 9 * * ?", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the location defaults to the one of the Trigger
+	trigger := quartz.NewNanoTriggerAdapter(cronTrigger, nil)
+	assertEqual(t, trigger.Description(), cronTrigger.Description())
+	next, err := trigger.NextFireTime(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	assertEqual(t, err, nil)
+	assertEqual(t, next.String(), time.Date(2024, 6, 1, 9, 0, 0, 0, loc).String())
+
+	trigger = quartz.NewNanoTriggerAdapter(quartz.NewSimpleTrigger(time.Minute), nil)
+	assertEqual(t, trigger.Location(), time.UTC)
+
+	_, err = quartz.NewNanoTriggerAdapter(quartz.NewRunOnceTriggerAt(time.Unix(0, fromEpoch)), nil).
+		NextFireTime(time.Unix(0, fromEpoch+1))
+	assertEqual(t, err, quartz.ErrFireTimeInPast)
+}
+
+func TestScheduledJobNextRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 6, 1, 10, 15, 0, 0, loc)
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Clock: quartz.NewMockClock(now),
+	})
+
+	noop := func(_ context.Context) error { return nil }
+	key, err := sched.ScheduleFunc(ctx, noop, quartz.NewTimeTriggerAdapter(nextHourTrigger{}, loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err := sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.Location, loc)
+	assertEqual(t, scheduled.NextRun().String(), time.Date(2024, 6, 1, 11, 0, 0, 0, loc).String())
+
+	key, err = sched.ScheduleFunc(ctx, noop, quartz.NewSimpleTrigger(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err = sched.GetScheduledJob(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, scheduled.Location, nil)
+	assertEqual(t, scheduled.NextRun().Equal(now.Add(time.Minute)), true)
+}
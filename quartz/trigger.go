@@ -3,9 +3,19 @@ package quartz
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
 	"time"
 )
 
+// ErrTriggerExpired is returned by NextFireTime when the Trigger will
+// never fire again.
+var ErrTriggerExpired = errors.New("trigger is expired")
+
+// ErrFireTimeInPast is returned by the RunOnceTrigger with an absolute
+// fire time which is already in the past when it is scheduled.
+var ErrFireTimeInPast = errors.New("fire time is in the past")
+
 // Trigger represents the mechanism by which Jobs are scheduled.
 type Trigger interface {
 	// NextFireTime returns the next time at which the Trigger is scheduled to fire.
@@ -15,42 +25,401 @@ type Trigger interface {
 	Description() string
 }
 
+// PreviewTrigger is implemented by the Triggers whose fire times can be
+// computed in advance, without changing the state of the Trigger.
+type PreviewTrigger interface {
+	Trigger
+
+	// NextN returns the next n fire times following from.
+	NextN(from int64, n int) ([]int64, error)
+}
+
+// WakeableTrigger is implemented by the Triggers whose next fire time
+// can move earlier between the NextFireTime calls, e.g. on an external
+// event. The StdScheduler registers a wakeup function with the Trigger
+// once it is scheduled.
+type WakeableTrigger interface {
+	Trigger
+
+	// SetWakeup registers the function which the Trigger calls to have
+	// its next fire time recalculated from the current time. The
+	// function must not be called while holding a lock required by
+	// NextFireTime.
+	SetWakeup(wakeup func())
+}
+
+// StatefulTrigger is implemented by the Triggers exposing the state of
+// their limits, which is reported by the ScheduledJob of the Job.
+type StatefulTrigger interface {
+	Trigger
+
+	// RemainingRepeats returns the number of the fire times the Trigger
+	// can still produce, and false if it is not limited by a repeat count.
+	RemainingRepeats() (int, bool)
+
+	// EndTime returns the time after which the Trigger no longer fires,
+	// and false if it has none.
+	EndTime() (time.Time, bool)
+}
+
+// TriggerOption configures the limits of a Trigger.
+type TriggerOption func(*triggerLimits)
+
+// WithEndTime sets the end time of the Trigger. The Trigger expires
+// once its next fire time is after the end time.
+func WithEndTime(end time.Time) TriggerOption {
+	return func(limits *triggerLimits) {
+		limits.endTime = end.UnixNano()
+	}
+}
+
+// WithRepeatCount limits the number of the fire times produced by the
+// Trigger, after which it expires. Non-positive values are ignored.
+func WithRepeatCount(n int) TriggerOption {
+	return func(limits *triggerLimits) {
+		limits.repeatCount = n
+	}
+}
+
+// triggerLimits holds the optional limits of a Trigger along with the
+// number of the fire times produced so far.
+type triggerLimits struct {
+	endTime     int64
+	repeatCount int
+	fired       int
+}
+
+func newTriggerLimits(opts []TriggerOption) triggerLimits {
+	var limits triggerLimits
+	for _, opt := range opts {
+		opt(&limits)
+	}
+
+	return limits
+}
+
+// check returns ErrTriggerExpired if the next fire time is not
+// permitted, given the number of the fire times produced before it.
+func (l *triggerLimits) check(next int64, fired int) error {
+	if l.repeatCount > 0 && fired >= l.repeatCount {
+		return ErrTriggerExpired
+	}
+	if l.endTime != 0 && next > l.endTime {
+		return ErrTriggerExpired
+	}
+
+	return nil
+}
+
+// nextFireTime returns the fire time following prev, as computed by the
+// next function, counting it towards the repeat count.
+func (l *triggerLimits) nextFireTime(next func(int64) (int64, error), prev int64) (int64, error) {
+	nextTime, err := next(prev)
+	if err != nil {
+		return 0, err
+	}
+	if err := l.check(nextTime, l.fired); err != nil {
+		return 0, err
+	}
+	l.fired++
+
+	return nextTime, nil
+}
+
+// nextN returns the n fire times following from, as computed by the next
+// function, without changing the state of the limits. The fire times
+// computed before an error is encountered are returned along with it.
+func (l *triggerLimits) nextN(next func(int64) (int64, error), from int64, n int) ([]int64, error) {
+	times := make([]int64, 0, n)
+	for fired := l.fired; len(times) < n; fired++ {
+		nextTime, err := next(from)
+		if err == nil {
+			err = l.check(nextTime, fired)
+		}
+		if err != nil {
+			return times, err
+		}
+		times = append(times, nextTime)
+		from = nextTime
+	}
+
+	return times, nil
+}
+
+// remainingRepeats returns the remaining repeats of the Trigger, if it
+// exposes them, as the StatefulTrigger does.
+func remainingRepeats(trigger Trigger) (int, bool) {
+	if t, ok := trigger.(interface{ RemainingRepeats() (int, bool) }); ok {
+		return t.RemainingRepeats()
+	}
+
+	return 0, false
+}
+
+// triggerEndTime returns the end time of the Trigger, and false if it
+// has none.
+func triggerEndTime(trigger Trigger) (time.Time, bool) {
+	if t, ok := trigger.(StatefulTrigger); ok {
+		return t.EndTime()
+	}
+
+	return time.Time{}, false
+}
+
+// remainingRepeats returns the number of the fire times the Trigger can
+// still produce, and false if its repeat count is not limited.
+func (l *triggerLimits) remainingRepeats() (int, bool) {
+	if l.repeatCount <= 0 {
+		return 0, false
+	}
+	if l.fired >= l.repeatCount {
+		return 0, true
+	}
+
+	return l.repeatCount - l.fired, true
+}
+
+// end returns the end time of the limits, and false if it is not set.
+func (l *triggerLimits) end() (time.Time, bool) {
+	if l.endTime == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, l.endTime), true
+}
+
 // SimpleTrigger implements the quartz.Trigger interface; uses a fixed interval.
 type SimpleTrigger struct {
 	Interval time.Duration
+
+	// InitialDelay, if positive, replaces the Interval before the
+	// first fire time of the SimpleTrigger.
+	InitialDelay time.Duration
+
+	// Anchored, when set, anchors the fire times to the first fire
+	// time of the SimpleTrigger: the Nth fire time is the first one
+	// plus N intervals, and the SimpleTrigger advanced from a later
+	// time, e.g. when a late fire is rescheduled from the current
+	// time according to the MisfirePolicy, moves to the next of these
+	// slots, skipping the passed ones. The fire times do not drift,
+	// so that the long-run frequency is exact. Otherwise, each fire
+	// time is the interval after the time it is advanced from.
+	Anchored bool
+
+	limits  triggerLimits
+	started bool
+	anchor  int64
 }
 
-// Verify SimpleTrigger satisfies the Trigger interface.
-var _ Trigger = (*SimpleTrigger)(nil)
+// Verify SimpleTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*SimpleTrigger)(nil)
+
+// Verify SimpleTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*SimpleTrigger)(nil)
 
 // NewSimpleTrigger returns a new SimpleTrigger using the given interval.
-func NewSimpleTrigger(interval time.Duration) *SimpleTrigger {
+// The options can limit the SimpleTrigger by an end time or a repeat count.
+func NewSimpleTrigger(interval time.Duration, opts ...TriggerOption) *SimpleTrigger {
 	return &SimpleTrigger{
 		Interval: interval,
+		limits:   newTriggerLimits(opts),
+	}
+}
+
+// NewSimpleTriggerWithDelay returns a new SimpleTrigger which first fires
+// after the initial delay, and then at the given interval. The options can
+// limit the SimpleTrigger by an end time or a repeat count.
+func NewSimpleTriggerWithDelay(initialDelay, interval time.Duration, opts ...TriggerOption) *SimpleTrigger {
+	return &SimpleTrigger{
+		Interval:     interval,
+		InitialDelay: initialDelay,
+		limits:       newTriggerLimits(opts),
 	}
 }
 
 // NextFireTime returns the next time at which the SimpleTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the limits of the SimpleTrigger are exhausted.
 func (st *SimpleTrigger) NextFireTime(prev int64) (int64, error) {
-	next := prev + st.Interval.Nanoseconds()
-	return next, nil
+	next, err := st.limits.nextFireTime(st.nextFunc(st.started, st.anchor), prev)
+	if err == nil {
+		st.started = true
+		if st.Anchored && st.anchor == 0 {
+			st.anchor = next
+		}
+	}
+
+	return next, err
+}
+
+// NextN returns the next n fire times of the SimpleTrigger following from.
+// If the SimpleTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (st *SimpleTrigger) NextN(from int64, n int) ([]int64, error) {
+	started, anchor := st.started, st.anchor
+	return st.limits.nextN(func(prev int64) (int64, error) {
+		next, err := st.nextFunc(started, anchor)(prev)
+		started = true
+		if st.Anchored && anchor == 0 {
+			anchor = next
+		}
+		return next, err
+	}, from, n)
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (st *SimpleTrigger) RemainingRepeats() (int, bool) {
+	return st.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the SimpleTrigger, and false if it has none.
+func (st *SimpleTrigger) EndTime() (time.Time, bool) {
+	return st.limits.end()
+}
+
+// nextFunc returns the function computing the next fire time, which uses
+// the initial delay unless the SimpleTrigger has started, and the slots
+// following the anchor once it is set.
+func (st *SimpleTrigger) nextFunc(started bool, anchor int64) func(int64) (int64, error) {
+	return func(prev int64) (int64, error) {
+		interval := st.Interval.Nanoseconds()
+		switch {
+		case !started && st.InitialDelay > 0:
+			return prev + st.InitialDelay.Nanoseconds(), nil
+		case anchor == 0 || interval <= 0:
+			return prev + interval, nil
+		case prev < anchor:
+			return anchor, nil
+		default:
+			return anchor + ((prev-anchor)/interval+1)*interval, nil
+		}
+	}
 }
 
 // Description returns the description of the trigger.
 func (st *SimpleTrigger) Description() string {
-	return fmt.Sprintf("SimpleTrigger with interval: %d", st.Interval)
+	desc := fmt.Sprintf("SimpleTrigger with interval: %d", st.Interval)
+	if st.InitialDelay > 0 {
+		desc += fmt.Sprintf(", initial delay: %d", st.InitialDelay)
+	}
+	if st.Anchored {
+		desc += ", anchored"
+	}
+
+	return desc
+}
+
+// AlignedTrigger implements the quartz.Trigger interface; fires at the
+// multiples of a fixed interval counted from midnight in its location,
+// e.g. at :00, :05, :10 for a 5-minute interval, regardless of when it
+// was scheduled.
+//
+// The multiples are counted anew from each midnight. For intervals that
+// do not divide a day evenly, the last fire of a day is followed by a fire
+// at the next midnight, earlier than the full interval. Intervals of a day
+// or longer fire at midnight daily.
+type AlignedTrigger struct {
+	Interval time.Duration
+	location *time.Location
+	limits   triggerLimits
+}
+
+// Verify AlignedTrigger satisfies the PreviewTrigger interface.
+var _ PreviewTrigger = (*AlignedTrigger)(nil)
+
+// Verify AlignedTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*AlignedTrigger)(nil)
+
+// NewAlignedTrigger returns a new AlignedTrigger using the given interval
+// and location. A nil location defaults to UTC. The options can limit the
+// AlignedTrigger by an end time or a repeat count.
+func NewAlignedTrigger(interval time.Duration, location *time.Location,
+	opts ...TriggerOption) *AlignedTrigger {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &AlignedTrigger{
+		Interval: interval,
+		location: location,
+		limits:   newTriggerLimits(opts),
+	}
+}
+
+// NextFireTime returns the next time at which the AlignedTrigger is scheduled to fire.
+// ErrTriggerExpired is returned once the limits of the AlignedTrigger are exhausted.
+func (at *AlignedTrigger) NextFireTime(prev int64) (int64, error) {
+	return at.limits.nextFireTime(at.next, prev)
+}
+
+// NextN returns the next n fire times of the AlignedTrigger following from.
+// If the AlignedTrigger expires earlier, the fire times computed so far are
+// returned along with ErrTriggerExpired.
+func (at *AlignedTrigger) NextN(from int64, n int) ([]int64, error) {
+	return at.limits.nextN(at.next, from, n)
+}
+
+// Location returns the location of the AlignedTrigger.
+func (at *AlignedTrigger) Location() *time.Location {
+	return at.location
+}
+
+// RemainingRepeats implements the StatefulTrigger interface.
+func (at *AlignedTrigger) RemainingRepeats() (int, bool) {
+	return at.limits.remainingRepeats()
+}
+
+// EndTime returns the end time of the AlignedTrigger, and false if it has none.
+func (at *AlignedTrigger) EndTime() (time.Time, bool) {
+	return at.limits.end()
+}
+
+func (at *AlignedTrigger) next(prev int64) (int64, error) {
+	if at.Interval <= 0 {
+		return 0, fmt.Errorf("invalid aligned trigger interval: %s", at.Interval)
+	}
+
+	t := time.Unix(0, prev).In(at.location)
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, at.location)
+	nextMidnight := time.Date(year, month, day+1, 0, 0, 0, 0, at.location)
+
+	elapsed := t.Sub(midnight)
+	next := midnight.Add((elapsed/at.Interval + 1) * at.Interval)
+	if !next.Before(nextMidnight) {
+		next = nextMidnight
+	}
+
+	return next.UnixNano(), nil
+}
+
+// Description returns the description of the trigger.
+func (at *AlignedTrigger) Description() string {
+	return fmt.Sprintf("AlignedTrigger with interval: %s %s", at.Interval, at.location)
 }
 
 // RunOnceTrigger implements the quartz.Trigger interface.
 // This type of Trigger can only be fired once and will expire immediately.
 type RunOnceTrigger struct {
-	Delay   time.Duration
+	Delay time.Duration
+
+	// At is the absolute fire time, which takes precedence over
+	// the Delay if set.
+	At time.Time
+
+	// FireIfPast makes the RunOnceTrigger fire immediately if At is
+	// in the past when scheduled. Otherwise, ErrFireTimeInPast is
+	// returned.
+	FireIfPast bool
+
 	expired bool
 }
 
 // Verify RunOnceTrigger satisfies the Trigger interface.
 var _ Trigger = (*RunOnceTrigger)(nil)
 
+// Verify RunOnceTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*RunOnceTrigger)(nil)
+
 // NewRunOnceTrigger returns a new RunOnceTrigger with the given delay time.
 func NewRunOnceTrigger(delay time.Duration) *RunOnceTrigger {
 	return &RunOnceTrigger{
@@ -59,16 +428,51 @@ func NewRunOnceTrigger(delay time.Duration) *RunOnceTrigger {
 	}
 }
 
+// NewRunOnceTriggerAt returns a new RunOnceTrigger firing at the given time.
+func NewRunOnceTriggerAt(at time.Time) *RunOnceTrigger {
+	return &RunOnceTrigger{
+		At:      at,
+		expired: false,
+	}
+}
+
 // NextFireTime returns the next time at which the RunOnceTrigger is scheduled to fire.
-// Sets exprired to true afterwards.
+// Sets expired to true afterwards, unless the fire time is in the past.
 func (ot *RunOnceTrigger) NextFireTime(prev int64) (int64, error) {
-	if !ot.expired {
-		next := prev + ot.Delay.Nanoseconds()
+	if ot.expired {
+		return 0, ErrTriggerExpired
+	}
+
+	if ot.At.IsZero() {
 		ot.expired = true
-		return next, nil
+		return prev + ot.Delay.Nanoseconds(), nil
 	}
 
-	return 0, errors.New("RunOnce trigger is expired")
+	next := ot.At.UnixNano()
+	if next < prev {
+		if !ot.FireIfPast {
+			return 0, ErrFireTimeInPast
+		}
+		next = prev
+	}
+	ot.expired = true
+
+	return next, nil
+}
+
+// RemainingRepeats returns the number of the fire times the RunOnceTrigger
+// can still produce, which is 1 until it has fired and 0 afterwards.
+func (ot *RunOnceTrigger) RemainingRepeats() (int, bool) {
+	if ot.expired {
+		return 0, true
+	}
+
+	return 1, true
+}
+
+// EndTime returns false, as the RunOnceTrigger has no end time.
+func (ot *RunOnceTrigger) EndTime() (time.Time, bool) {
+	return time.Time{}, false
 }
 
 // Description returns the description of the trigger.
@@ -78,5 +482,90 @@ func (ot *RunOnceTrigger) Description() string {
 		status = "expired"
 	}
 
+	if !ot.At.IsZero() {
+		return fmt.Sprintf("RunOnceTrigger at %s (%s).", ot.At.Format(time.RFC3339Nano), status)
+	}
+
 	return fmt.Sprintf("RunOnceTrigger (%s).", status)
 }
+
+// JitterTrigger implements the quartz.Trigger interface; wraps a Trigger,
+// delaying each of its fire times by a random offset in [0, maxJitter).
+// The offsets do not accumulate, as the wrapped Trigger is advanced from
+// its own previous fire time.
+type JitterTrigger struct {
+	trigger   Trigger
+	maxJitter time.Duration
+
+	mtx      sync.Mutex
+	rand     *rand.Rand
+	lastBase int64
+	lastNext int64
+}
+
+// Verify JitterTrigger satisfies the Trigger interface.
+var _ Trigger = (*JitterTrigger)(nil)
+
+// Verify JitterTrigger satisfies the StatefulTrigger interface.
+var _ StatefulTrigger = (*JitterTrigger)(nil)
+
+// NewTriggerWithJitter returns a new JitterTrigger wrapping the given
+// Trigger, using a time seeded random source.
+func NewTriggerWithJitter(trigger Trigger, maxJitter time.Duration) *JitterTrigger {
+	return NewTriggerWithJitterSource(trigger, maxJitter, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewTriggerWithJitterSource returns a new JitterTrigger wrapping the
+// given Trigger, using the given random source.
+func NewTriggerWithJitterSource(trigger Trigger, maxJitter time.Duration,
+	source rand.Source) *JitterTrigger {
+	return &JitterTrigger{
+		trigger:   trigger,
+		maxJitter: maxJitter,
+		rand:      rand.New(source),
+	}
+}
+
+// NextFireTime returns the next time at which the JitterTrigger is scheduled to fire.
+// The fire time is never earlier than prev.
+func (jt *JitterTrigger) NextFireTime(prev int64) (int64, error) {
+	jt.mtx.Lock()
+	defer jt.mtx.Unlock()
+
+	base := prev
+	if jt.lastNext != 0 && prev == jt.lastNext {
+		base = jt.lastBase
+	}
+
+	next, err := jt.trigger.NextFireTime(base)
+	if err != nil {
+		return 0, err
+	}
+	jt.lastBase = next
+
+	if jt.maxJitter > 0 {
+		next += jt.rand.Int63n(jt.maxJitter.Nanoseconds())
+	}
+	if next < prev {
+		next = prev
+	}
+	jt.lastNext = next
+
+	return next, nil
+}
+
+// RemainingRepeats returns the remaining repeats of the wrapped Trigger.
+func (jt *JitterTrigger) RemainingRepeats() (int, bool) {
+	return remainingRepeats(jt.trigger)
+}
+
+// EndTime returns the end time of the wrapped Trigger, and false if it
+// has none.
+func (jt *JitterTrigger) EndTime() (time.Time, bool) {
+	return triggerEndTime(jt.trigger)
+}
+
+// Description returns the description of the trigger.
+func (jt *JitterTrigger) Description() string {
+	return fmt.Sprintf("%s with jitter: %s", jt.trigger.Description(), jt.maxJitter)
+}
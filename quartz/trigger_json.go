@@ -0,0 +1,303 @@
+package quartz
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrUnknownTriggerType is returned when decoding a Trigger of a type
+// which is not registered.
+var ErrUnknownTriggerType = errors.New("unknown trigger type")
+
+var triggerRegistry = newTypeRegistry[Trigger]("trigger", ErrUnknownTriggerType)
+
+func init() {
+	RegisterTrigger("cron", func() Trigger { return &CronTrigger{} })
+	RegisterTrigger("simple", func() Trigger { return &SimpleTrigger{} })
+	RegisterTrigger("aligned", func() Trigger { return &AlignedTrigger{} })
+	RegisterTrigger("run_once", func() Trigger { return &RunOnceTrigger{} })
+	RegisterTrigger("backoff", func() Trigger { return &BackoffTrigger{} })
+}
+
+// RegisterTrigger makes a Trigger type available to MarshalTrigger and
+// UnmarshalTrigger under the given type name. The factory returns a new
+// zero value of the type, which has to support the JSON encoding. The
+// built-in triggers are registered as "cron", "simple", "aligned",
+// "run_once" and "backoff". RegisterTrigger panics if the type name or
+// the type is already registered.
+func RegisterTrigger(typeName string, factory func() Trigger) {
+	triggerRegistry.register(typeName, factory)
+}
+
+// MarshalTrigger returns the JSON encoding of the registered Trigger,
+// extended with its type name in the "type" field.
+func MarshalTrigger(trigger Trigger) ([]byte, error) {
+	return triggerRegistry.marshal(trigger)
+}
+
+// UnmarshalTrigger decodes a Trigger encoded by MarshalTrigger, using the
+// registered type of its "type" field.
+func UnmarshalTrigger(data []byte) (Trigger, error) {
+	return triggerRegistry.unmarshal(data)
+}
+
+// jsonDuration encodes a time.Duration as its string representation.
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(duration)
+
+	return nil
+}
+
+// triggerLimitsJSON is the JSON representation of the triggerLimits.
+type triggerLimitsJSON struct {
+	EndTime     *time.Time `json:"end_time,omitempty"`
+	RepeatCount int        `json:"repeat_count,omitempty"`
+	Fired       int        `json:"fired,omitempty"`
+}
+
+func (l *triggerLimits) toJSON() triggerLimitsJSON {
+	limits := triggerLimitsJSON{
+		RepeatCount: l.repeatCount,
+		Fired:       l.fired,
+	}
+	if l.endTime != 0 {
+		endTime := time.Unix(0, l.endTime).UTC()
+		limits.EndTime = &endTime
+	}
+
+	return limits
+}
+
+func (l *triggerLimits) fromJSON(limits triggerLimitsJSON) {
+	l.endTime = 0
+	if limits.EndTime != nil {
+		l.endTime = limits.EndTime.UnixNano()
+	}
+	l.repeatCount = limits.RepeatCount
+	l.fired = limits.Fired
+}
+
+type cronTriggerJSON struct {
+	Expression string `json:"expression"`
+	Location   string `json:"location"`
+	triggerLimitsJSON
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ct *CronTrigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cronTriggerJSON{
+		Expression:        ct.expression,
+		Location:          ct.location.String(),
+		triggerLimitsJSON: ct.limits.toJSON(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (ct *CronTrigger) UnmarshalJSON(data []byte) error {
+	var v cronTriggerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(v.Location)
+	if err != nil {
+		return err
+	}
+	trigger, err := NewCronTriggerWithLoc(v.Expression, location)
+	if err != nil {
+		return err
+	}
+
+	*ct = *trigger
+	ct.limits.fromJSON(v.triggerLimitsJSON)
+
+	return nil
+}
+
+type simpleTriggerJSON struct {
+	Interval     jsonDuration `json:"interval"`
+	InitialDelay jsonDuration `json:"initial_delay,omitempty"`
+	Started      bool         `json:"started,omitempty"`
+	Anchored     bool         `json:"anchored,omitempty"`
+	Anchor       *time.Time   `json:"anchor,omitempty"`
+	triggerLimitsJSON
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (st *SimpleTrigger) MarshalJSON() ([]byte, error) {
+	v := simpleTriggerJSON{
+		Interval:          jsonDuration(st.Interval),
+		InitialDelay:      jsonDuration(st.InitialDelay),
+		Started:           st.started,
+		Anchored:          st.Anchored,
+		triggerLimitsJSON: st.limits.toJSON(),
+	}
+	if st.anchor != 0 {
+		anchor := time.Unix(0, st.anchor).UTC()
+		v.Anchor = &anchor
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (st *SimpleTrigger) UnmarshalJSON(data []byte) error {
+	var v simpleTriggerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	st.Interval = time.Duration(v.Interval)
+	st.InitialDelay = time.Duration(v.InitialDelay)
+	st.started = v.Started
+	st.Anchored = v.Anchored
+	st.anchor = 0
+	if v.Anchor != nil {
+		st.anchor = v.Anchor.UnixNano()
+	}
+	st.limits.fromJSON(v.triggerLimitsJSON)
+
+	return nil
+}
+
+type alignedTriggerJSON struct {
+	Interval jsonDuration `json:"interval"`
+	Location string       `json:"location"`
+	triggerLimitsJSON
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (at *AlignedTrigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(alignedTriggerJSON{
+		Interval:          jsonDuration(at.Interval),
+		Location:          at.location.String(),
+		triggerLimitsJSON: at.limits.toJSON(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (at *AlignedTrigger) UnmarshalJSON(data []byte) error {
+	var v alignedTriggerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(v.Location)
+	if err != nil {
+		return err
+	}
+
+	at.Interval = time.Duration(v.Interval)
+	at.location = location
+	at.limits.fromJSON(v.triggerLimitsJSON)
+
+	return nil
+}
+
+type runOnceTriggerJSON struct {
+	Delay      jsonDuration `json:"delay,omitempty"`
+	At         *time.Time   `json:"at,omitempty"`
+	FireIfPast bool         `json:"fire_if_past,omitempty"`
+	Expired    bool         `json:"expired"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ot *RunOnceTrigger) MarshalJSON() ([]byte, error) {
+	v := runOnceTriggerJSON{
+		Delay:      jsonDuration(ot.Delay),
+		FireIfPast: ot.FireIfPast,
+		Expired:    ot.expired,
+	}
+	if !ot.At.IsZero() {
+		v.At = &ot.At
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (ot *RunOnceTrigger) UnmarshalJSON(data []byte) error {
+	var v runOnceTriggerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	ot.Delay = time.Duration(v.Delay)
+	ot.At = time.Time{}
+	if v.At != nil {
+		ot.At = *v.At
+	}
+	ot.FireIfPast = v.FireIfPast
+	ot.expired = v.Expired
+
+	return nil
+}
+
+type backoffTriggerJSON struct {
+	Initial     jsonDuration `json:"initial"`
+	Max         jsonDuration `json:"max"`
+	Factor      float64      `json:"factor"`
+	MaxAttempts int          `json:"max_attempts"`
+	FullJitter  bool         `json:"full_jitter,omitempty"`
+	Attempt     int          `json:"attempt"`
+	Interval    jsonDuration `json:"interval"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The random source
+// of the full jitter is not encoded.
+func (bt *BackoffTrigger) MarshalJSON() ([]byte, error) {
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+
+	return json.Marshal(backoffTriggerJSON{
+		Initial:     jsonDuration(bt.initial),
+		Max:         jsonDuration(bt.max),
+		Factor:      bt.factor,
+		MaxAttempts: bt.maxAttempts,
+		FullJitter:  bt.rand != nil,
+		Attempt:     bt.attempt,
+		Interval:    jsonDuration(bt.interval),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The full jitter
+// uses a time seeded random source.
+func (bt *BackoffTrigger) UnmarshalJSON(data []byte) error {
+	var v backoffTriggerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+
+	bt.initial = time.Duration(v.Initial)
+	bt.max = time.Duration(v.Max)
+	bt.factor = v.Factor
+	if bt.factor < 1 {
+		bt.factor = 1
+	}
+	bt.maxAttempts = v.MaxAttempts
+	bt.rand = nil
+	if v.FullJitter {
+		bt.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	bt.attempt = v.Attempt
+	bt.interval = time.Duration(v.Interval)
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package quartz_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func roundTrip(t *testing.T, trigger quartz.Trigger) quartz.Trigger {
+	t.Helper()
+
+	data, err := quartz.MarshalTrigger(trigger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := quartz.UnmarshalTrigger(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, fmt.Sprintf("%T", decoded), fmt.Sprintf("%T", trigger))
+	assertEqual(t, decoded.Description(), trigger.Description())
+
+	return decoded
+}
+
+func TestTriggerJSON(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Unix(0, fromEpoch).Add(time.Hour)
+
+	cronTrigger, err := quartz.NewCronTriggerWithLoc("0 0 9 * * MON-FRI", loc, quartz.WithEndTime(end))
+	if err != nil {
+		t.Fatal(err)
+	}
+	everyTrigger, err := quartz.NewCronTrigger("@every 90s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simpleTrigger := quartz.NewSimpleTrigger(time.Minute, quartz.WithRepeatCount(5))
+	if _, err := simpleTrigger.NextFireTime(fromEpoch); err != nil {
+		t.Fatal(err)
+	}
+	backoffTrigger := quartz.NewBackoffTrigger(time.Second, time.Minute, 2, 10)
+	for i := 0; i < 3; i++ {
+		if _, err := backoffTrigger.NextFireTime(fromEpoch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, trigger := range []quartz.Trigger{
+		cronTrigger,
+		everyTrigger,
+		simpleTrigger,
+		quartz.NewSimpleTriggerWithDelay(time.Second, time.Minute),
+		quartz.NewAlignedTrigger(5*time.Minute, loc),
+		quartz.NewRunOnceTrigger(time.Second),
+		quartz.NewRunOnceTriggerAt(end),
+		backoffTrigger,
+	} {
+		t.Run(trigger.Description(), func(t *testing.T) {
+			decoded := roundTrip(t, trigger)
+			for i := 0; i < 3; i++ {
+				next, err := trigger.NextFireTime(fromEpoch)
+				decodedNext, decodedErr := decoded.NextFireTime(fromEpoch)
+				assertEqual(t, decodedNext, next)
+				assertEqual(t, decodedErr, err)
+			}
+		})
+	}
+}
+
+func TestTriggerJSONState(t *testing.T) {
+	// the remaining repeat count is restored
+	simpleTrigger := quartz.NewSimpleTrigger(time.Minute, quartz.WithRepeatCount(3))
+	if _, err := simpleTrigger.NextFireTime(fromEpoch); err != nil {
+		t.Fatal(err)
+	}
+	decoded := roundTrip(t, simpleTrigger).(*quartz.SimpleTrigger)
+	remaining, limited := decoded.RemainingRepeats()
+	assertEqual(t, remaining, 2)
+	assertEqual(t, limited, true)
+
+	// the started state is restored
+	delayedTrigger := quartz.NewSimpleTriggerWithDelay(time.Second, time.Minute)
+	if _, err := delayedTrigger.NextFireTime(fromEpoch); err != nil {
+		t.Fatal(err)
+	}
+	next, err := roundTrip(t, delayedTrigger).NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Minute))
+
+	// the anchor is restored
+	anchoredTrigger := quartz.NewSimpleTrigger(time.Minute)
+	anchoredTrigger.Anchored = true
+	if _, err := anchoredTrigger.NextFireTime(fromEpoch); err != nil {
+		t.Fatal(err)
+	}
+	next, err = roundTrip(t, anchoredTrigger).NextFireTime(fromEpoch + int64(150*time.Second))
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(3*time.Minute))
+
+	// the expiry is restored
+	runOnceTrigger := quartz.NewRunOnceTrigger(time.Second)
+	if _, err := runOnceTrigger.NextFireTime(fromEpoch); err != nil {
+		t.Fatal(err)
+	}
+	_, err = roundTrip(t, runOnceTrigger).NextFireTime(fromEpoch)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+
+	data, err := quartz.MarshalTrigger(quartz.NewSimpleTrigger(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `{"interval":"1m0s","type":"simple"}`)
+}
+
+type customTrigger struct {
+	Offset int64 `json:"offset"`
+}
+
+func (c *customTrigger) NextFireTime(prev int64) (int64, error) {
+	return prev + c.Offset, nil
+}
+
+func (c *customTrigger) Description() string {
+	return fmt.Sprintf("customTrigger %d", c.Offset)
+}
+
+var registerCustomTrigger sync.Once
+
+func TestRegisterTrigger(t *testing.T) {
+	registerCustomTrigger.Do(func() {
+		quartz.RegisterTrigger("custom", func() quartz.Trigger { return &customTrigger{} })
+	})
+	roundTrip(t, &customTrigger{Offset: 42})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic on the duplicate registration")
+		}
+	}()
+	quartz.RegisterTrigger("custom", func() quartz.Trigger { return &customTrigger{} })
+}
+
+func TestUnmarshalTriggerUnknown(t *testing.T) {
+	_, err := quartz.UnmarshalTrigger([]byte(`{"type":"unknown","expression":"* * * * *"}`))
+	if !errors.Is(err, quartz.ErrUnknownTriggerType) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = quartz.MarshalTrigger(quartz.NewUnionTrigger())
+	if !errors.Is(err, quartz.ErrUnknownTriggerType) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = quartz.UnmarshalTrigger([]byte(`{"type":"cron","expression":"x","location":"UTC"}`))
+	assertNotEqual(t, err, nil)
+}
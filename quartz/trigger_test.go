@@ -1,6 +1,7 @@
 package quartz_test
 
 import (
+	"math/rand"
 	"testing"
 	"time"
 
@@ -36,5 +37,342 @@ func TestRunOnceTrigger(t *testing.T) {
 
 	next, err = trigger.NextFireTime(next)
 	assertEqual(t, next, 0)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestSimpleTriggerNextN(t *testing.T) {
+	var trigger quartz.PreviewTrigger = quartz.NewSimpleTrigger(time.Second * 5)
+
+	times, err := trigger.NextN(fromEpoch, 3)
+	assertEqual(t, err, nil)
+	assertEqual(t, len(times), 3)
+	assertEqual(t, times[0], 1577836805000000000)
+	assertEqual(t, times[2], 1577836815000000000)
+}
+
+func TestSimpleTriggerRepeatCount(t *testing.T) {
+	trigger := quartz.NewSimpleTrigger(time.Second*5, quartz.WithRepeatCount(2))
+
+	remaining, limited := trigger.RemainingRepeats()
+	assertEqual(t, remaining, 2)
+	assertEqual(t, limited, true)
+
+	// the preview does not count towards the repeat count
+	times, err := trigger.NextN(fromEpoch, 3)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+	assertEqual(t, len(times), 2)
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, next, 1577836805000000000)
+	assertEqual(t, err, nil)
+
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, next, 1577836810000000000)
+	assertEqual(t, err, nil)
+
+	remaining, _ = trigger.RemainingRepeats()
+	assertEqual(t, remaining, 0)
+
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestSimpleTriggerEndTime(t *testing.T) {
+	end := time.Unix(0, fromEpoch).Add(12 * time.Second)
+	trigger := quartz.NewSimpleTrigger(time.Second*5, quartz.WithEndTime(end))
+
+	_, limited := trigger.RemainingRepeats()
+	assertEqual(t, limited, false)
+	endTime, ok := trigger.EndTime()
+	assertEqual(t, endTime, end)
+	assertEqual(t, ok, true)
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, next, 1577836805000000000)
+	assertEqual(t, err, nil)
+
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, next, 1577836810000000000)
+	assertEqual(t, err, nil)
+
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestStatefulTriggers(t *testing.T) {
+	end := time.Unix(0, fromEpoch).Add(time.Hour)
+	cronTrigger, err := quartz.NewCronTrigger("0 * * * * *", quartz.WithEndTime(end))
+	assertEqual(t, err, nil)
+	triggers := []quartz.StatefulTrigger{
+		cronTrigger,
+		quartz.NewCalendarTrigger(quartz.NewSimpleTrigger(time.Minute, quartz.WithEndTime(end)),
+			quartz.NewDateCalendar(time.UTC)),
+		quartz.NewTriggerWithJitter(quartz.NewSimpleTrigger(time.Minute, quartz.WithEndTime(end)),
+			time.Second),
+	}
+	for _, trigger := range triggers {
+		endTime, ok := trigger.EndTime()
+		assertEqual(t, endTime.UnixNano(), end.UnixNano())
+		assertEqual(t, ok, true)
+	}
+
+	runOnce := quartz.NewRunOnceTrigger(time.Second)
+	remaining, limited := runOnce.RemainingRepeats()
+	assertEqual(t, remaining, 1)
+	assertEqual(t, limited, true)
+	_, err = runOnce.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	remaining, _ = runOnce.RemainingRepeats()
+	assertEqual(t, remaining, 0)
+	_, ok := runOnce.EndTime()
+	assertEqual(t, ok, false)
+}
+
+func TestSimpleTriggerInitialDelay(t *testing.T) {
+	simpleTrigger := quartz.NewSimpleTriggerWithDelay(5*time.Second, 10*time.Minute)
+	assertEqual(t, simpleTrigger.Description(),
+		"SimpleTrigger with interval: 600000000000, initial delay: 5000000000")
+
+	// the preview does not start the trigger
+	for i := 0; i < 2; i++ {
+		next, err := simpleTrigger.NextN(fromEpoch, 3)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, []int64{
+			fromEpoch + int64(5*time.Second),
+			fromEpoch + int64(5*time.Second+10*time.Minute),
+			fromEpoch + int64(5*time.Second+20*time.Minute),
+		})
+	}
+
+	prev := fromEpoch
+	for _, expected := range []time.Duration{5 * time.Second, 10 * time.Minute, 10 * time.Minute} {
+		next, err := simpleTrigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, prev+int64(expected))
+		prev = next
+	}
+
+	// the delay is not used again once started, regardless of prev
+	next, err := simpleTrigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(10*time.Minute))
+}
+
+func TestSimpleTriggerAnchored(t *testing.T) {
+	const interval = 250 * time.Millisecond
+	anchored := quartz.NewSimpleTrigger(interval)
+	anchored.Anchored = true
+	assertEqual(t, anchored.Description(), "SimpleTrigger with interval: 250000000, anchored")
+	drifting := quartz.NewSimpleTrigger(interval)
+
+	// the fires are advanced from the late executions
+	prevAnchored, prevDrifting := fromEpoch, fromEpoch
+	for i := 1; i <= 1000; i++ {
+		late := int64(i%7) * int64(time.Millisecond)
+		next, err := anchored.NextFireTime(prevAnchored)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, fromEpoch+int64(i)*int64(interval))
+		prevAnchored = next + late
+
+		next, err = drifting.NextFireTime(prevDrifting)
+		assertEqual(t, err, nil)
+		prevDrifting = next + late
+	}
+	assertEqual(t, prevDrifting-prevAnchored > int64(time.Second), true)
+
+	// the passed slots are skipped, and the preview follows the slots
+	next, err := anchored.NextFireTime(prevAnchored + int64(interval))
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+1002*int64(interval))
+	times, err := anchored.NextN(next+int64(time.Millisecond), 2)
+	assertEqual(t, err, nil)
+	assertEqual(t, times, []int64{fromEpoch + 1003*int64(interval), fromEpoch + 1004*int64(interval)})
+
+	// the slots are anchored to the first fire time after the delay
+	delayed := quartz.NewSimpleTriggerWithDelay(time.Second, time.Minute)
+	delayed.Anchored = true
+	next, err = delayed.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Second))
+	next, err = delayed.NextFireTime(next + int64(90*time.Second))
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Second+2*time.Minute))
+}
+
+func TestSimpleTriggerDescribeHuman(t *testing.T) {
+	assertEqual(t, quartz.NewSimpleTrigger(5*time.Minute).DescribeHuman(), "every 5 minutes")
+	assertEqual(t, quartz.NewSimpleTrigger(time.Hour).DescribeHuman(), "every hour")
+	assertEqual(t, quartz.NewSimpleTrigger(1500*time.Millisecond).DescribeHuman(), "every 1.5s")
+	assertEqual(t, quartz.NewSimpleTriggerWithDelay(5*time.Second, 10*time.Minute).DescribeHuman(),
+		"every 10 minutes, first after 5 seconds")
+
+	endTime := time.Unix(0, fromEpoch).Add(48 * time.Hour)
+	simpleTrigger := quartz.NewSimpleTrigger(36*time.Hour, quartz.WithRepeatCount(1),
+		quartz.WithEndTime(endTime))
+	assertEqual(t, simpleTrigger.DescribeHuman(), "every 36 hours, once, until 2020-01-03T00:00:00Z")
+}
+
+func TestJitterTrigger(t *testing.T) {
+	const maxJitter = 2 * time.Second
+	trigger := quartz.NewTriggerWithJitterSource(quartz.NewSimpleTrigger(time.Second*5),
+		maxJitter, rand.NewSource(1))
+	assertEqual(t, trigger.Description(), "SimpleTrigger with interval: 5000000000 with jitter: 2s")
+
+	expected := rand.New(rand.NewSource(1))
+	prev := fromEpoch
+	for i := int64(1); i <= 5; i++ {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		// the jitter does not accumulate
+		base := fromEpoch + i*5*time.Second.Nanoseconds()
+		assertEqual(t, next, base+expected.Int63n(maxJitter.Nanoseconds()))
+		prev = next
+	}
+}
+
+func TestJitterTriggerNotBeforePrev(t *testing.T) {
+	trigger := quartz.NewTriggerWithJitterSource(quartz.NewSimpleTrigger(time.Second),
+		10*time.Second, rand.NewSource(1))
+
+	prev := fromEpoch
+	for i := 0; i < 100; i++ {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		if next < prev {
+			t.Fatalf("next fire time %d is before the previous one %d", next, prev)
+		}
+		prev = next
+	}
+
+	trigger = quartz.NewTriggerWithJitterSource(quartz.NewRunOnceTrigger(time.Second),
+		time.Second, rand.NewSource(1))
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestRunOnceTriggerAt(t *testing.T) {
+	at := time.Unix(0, fromEpoch).Add(time.Hour)
+	trigger := quartz.NewRunOnceTriggerAt(at)
+	assertEqual(t, trigger.Description(), "RunOnceTrigger at 2020-01-01T01:00:00Z (valid).")
+
+	next, err := trigger.NextFireTime(fromEpoch + time.Minute.Nanoseconds())
+	assertEqual(t, next, at.UnixNano())
+	assertEqual(t, err, nil)
+
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+
+	past := at.Add(time.Second).UnixNano()
+	trigger = quartz.NewRunOnceTriggerAt(at)
+	_, err = trigger.NextFireTime(past)
+	assertEqual(t, err, quartz.ErrFireTimeInPast)
+	assertEqual(t, trigger.Description(), "RunOnceTrigger at 2020-01-01T01:00:00Z (valid).")
+	remaining, _ := trigger.RemainingRepeats()
+	assertEqual(t, remaining, 1)
+
+	next, err = trigger.NextFireTime(fromEpoch)
+	assertEqual(t, next, at.UnixNano())
+	assertEqual(t, err, nil)
+
+	trigger = quartz.NewRunOnceTriggerAt(at)
+	trigger.FireIfPast = true
+	next, err = trigger.NextFireTime(past)
+	assertEqual(t, next, past)
+	assertEqual(t, err, nil)
+}
+
+func TestAlignedTrigger(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		location *time.Location
+		prev     time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "5 minutes",
+			interval: 5 * time.Minute,
+			prev:     time.Date(2024, 6, 1, 10, 7, 30, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 10, 10, 0, 0, time.UTC),
+				time.Date(2024, 6, 1, 10, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "on the boundary",
+			interval: 5 * time.Minute,
+			prev:     time.Date(2024, 6, 1, 10, 10, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 10, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "7 minutes across midnight",
+			interval: 7 * time.Minute,
+			prev:     time.Date(2024, 6, 1, 23, 50, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 23, 55, 0, 0, time.UTC),
+				time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 2, 0, 7, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "location",
+			interval: 5 * time.Hour,
+			location: loc,
+			prev:     time.Date(2024, 6, 1, 12, 0, 0, 0, loc),
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 15, 0, 0, 0, loc),
+				time.Date(2024, 6, 1, 20, 0, 0, 0, loc),
+				time.Date(2024, 6, 2, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:     "longer than a day",
+			interval: 36 * time.Hour,
+			prev:     time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := quartz.NewAlignedTrigger(tt.interval, tt.location)
+			times, err := trigger.NextN(tt.prev.UnixNano(), len(tt.expected))
+			assertEqual(t, err, nil)
+			for i, expected := range tt.expected {
+				assertEqual(t, times[i], expected.UnixNano())
+			}
+		})
+	}
+}
+
+func TestAlignedTriggerRestart(t *testing.T) {
+	// the fire times do not depend on the scheduling time, unlike
+	// the SimpleTrigger ones
+	aligned := quartz.NewAlignedTrigger(5*time.Minute, nil)
+	simple := quartz.NewSimpleTrigger(5 * time.Minute)
+	for _, prev := range []int64{fromEpoch + int64(time.Minute), fromEpoch + int64(3*time.Minute)} {
+		next, err := aligned.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, fromEpoch+int64(5*time.Minute))
+
+		next, err = simple.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, next, prev+int64(5*time.Minute))
+	}
+	assertEqual(t, aligned.Description(), "AlignedTrigger with interval: 5m0s UTC")
+
+	_, err := quartz.NewAlignedTrigger(0, nil).NextFireTime(fromEpoch)
 	assertNotEqual(t, err, nil)
 }
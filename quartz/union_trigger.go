@@ -0,0 +1,98 @@
+package quartz
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// UnionTrigger implements the quartz.Trigger interface; fires at the
+// earliest of the fire times of its member triggers. The members firing
+// at the same instant result in a single fire.
+type UnionTrigger struct {
+	triggers []Trigger
+
+	mtx     sync.Mutex
+	next    []int64
+	expired []bool
+	woken   []bool
+}
+
+// Verify UnionTrigger satisfies the WakeableTrigger interface.
+var _ WakeableTrigger = (*UnionTrigger)(nil)
+
+// NewUnionTrigger returns a new UnionTrigger combining the given triggers.
+func NewUnionTrigger(triggers ...Trigger) *UnionTrigger {
+	return &UnionTrigger{
+		triggers: triggers,
+		next:     make([]int64, len(triggers)),
+		expired:  make([]bool, len(triggers)),
+		woken:    make([]bool, len(triggers)),
+	}
+}
+
+// NextFireTime returns the next time at which the UnionTrigger is scheduled to fire.
+// Only the members due at prev or woken since the last call are advanced, so that the
+// others keep their schedule.
+// ErrTriggerExpired is returned once all of the members are expired.
+func (ut *UnionTrigger) NextFireTime(prev int64) (int64, error) {
+	ut.mtx.Lock()
+	defer ut.mtx.Unlock()
+
+	var next int64
+	found := false
+	for i, trigger := range ut.triggers {
+		if ut.expired[i] {
+			continue
+		}
+		if ut.next[i] <= prev || ut.woken[i] {
+			ut.woken[i] = false
+			memberNext, err := trigger.NextFireTime(prev)
+			if err != nil {
+				ut.expired[i] = true
+				continue
+			}
+			ut.next[i] = memberNext
+		}
+		if !found || ut.next[i] < next {
+			next = ut.next[i]
+			found = true
+		}
+	}
+
+	if !found {
+		return 0, ErrTriggerExpired
+	}
+
+	return next, nil
+}
+
+// SetWakeup registers the wakeup function with the members which are
+// WakeableTriggers, so that the woken members are advanced by the next
+// NextFireTime call.
+func (ut *UnionTrigger) SetWakeup(wakeup func()) {
+	for i, trigger := range ut.triggers {
+		wakeable, ok := trigger.(WakeableTrigger)
+		if !ok {
+			continue
+		}
+		i := i
+		wakeable.SetWakeup(func() {
+			ut.mtx.Lock()
+			ut.woken[i] = true
+			ut.mtx.Unlock()
+
+			wakeup()
+		})
+	}
+}
+
+// Description returns the description of the trigger.
+func (ut *UnionTrigger) Description() string {
+	descriptions := make([]string, len(ut.triggers))
+	for i, trigger := range ut.triggers {
+		descriptions[i] = trigger.Description()
+	}
+
+	return fmt.Sprintf("UnionTrigger of: %s", strings.Join(descriptions, "; "))
+}
@@ -0,0 +1,98 @@
+package quartz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestUnionTrigger(t *testing.T) {
+	daily, err := quartz.NewCronTrigger("0 0 2 * * ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekly, err := quartz.NewCronTrigger("0 0 14 ? * SUN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := quartz.NewUnionTrigger(daily, weekly)
+	assertEqual(t, trigger.Description(),
+		"UnionTrigger of: CronTrigger 0 0 2 * * ? UTC; CronTrigger 0 0 14 ? * SUN UTC")
+
+	prev := time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC).UnixNano() // Saturday
+	for _, expected := range []time.Time{
+		time.Date(2024, 6, 9, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 9, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 10, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 11, 2, 0, 0, 0, time.UTC),
+	} {
+		prev, err = trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		assertEqual(t, time.Unix(0, prev).UTC(), expected)
+	}
+}
+
+func TestUnionTriggerSameInstant(t *testing.T) {
+	trigger := quartz.NewUnionTrigger(
+		quartz.NewSimpleTrigger(2*time.Second),
+		quartz.NewSimpleTrigger(3*time.Second),
+	)
+
+	prev := fromEpoch
+	var offsets []int64
+	for i := 0; i < 5; i++ {
+		next, err := trigger.NextFireTime(prev)
+		assertEqual(t, err, nil)
+		offsets = append(offsets, (next-fromEpoch)/int64(time.Second))
+		prev = next
+	}
+	// the members coinciding at 6s result in a single fire
+	for i, expected := range []int64{2, 3, 4, 6, 8} {
+		assertEqual(t, offsets[i], expected)
+	}
+}
+
+func TestUnionTriggerExpired(t *testing.T) {
+	trigger := quartz.NewUnionTrigger(
+		quartz.NewRunOnceTrigger(time.Second),
+		quartz.NewSimpleTrigger(time.Second, quartz.WithRepeatCount(2)),
+	)
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Second))
+
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+2*int64(time.Second))
+
+	_, err = trigger.NextFireTime(next)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+
+	_, err = quartz.NewUnionTrigger().NextFireTime(fromEpoch)
+	assertEqual(t, err, quartz.ErrTriggerExpired)
+}
+
+func TestUnionTriggerWakeup(t *testing.T) {
+	external := quartz.NewExternalTrigger()
+	trigger := quartz.NewUnionTrigger(quartz.NewSimpleTrigger(time.Hour), external)
+
+	var wakeups int
+	trigger.SetWakeup(func() { wakeups++ })
+
+	next, err := trigger.NextFireTime(fromEpoch)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Hour))
+
+	// the woken member is advanced before its fire time
+	external.Fire()
+	assertEqual(t, wakeups, 1)
+	next, err = trigger.NextFireTime(fromEpoch + 10)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+10)
+
+	next, err = trigger.NextFireTime(next)
+	assertEqual(t, err, nil)
+	assertEqual(t, next, fromEpoch+int64(time.Hour))
+}
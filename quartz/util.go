@@ -1,40 +1,15 @@
 package quartz
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
-	"strconv"
+	"math"
 	"strings"
+	"sync"
 	"time"
 )
 
-func indexes(search []string, target []string) ([]int, error) {
-	searchIndexes := make([]int, 0, len(search))
-	for _, a := range search {
-		index := intVal(target, a)
-		if index == -1 {
-			return nil, fmt.Errorf("invalid cron field: %s", a)
-		}
-		searchIndexes = append(searchIndexes, index)
-	}
-
-	return searchIndexes, nil
-}
-
-func sliceAtoi(sa []string) ([]int, error) {
-	si := make([]int, 0, len(sa))
-	for _, a := range sa {
-		i, err := strconv.Atoi(a)
-		if err != nil {
-			return si, err
-		}
-
-		si = append(si, i)
-	}
-
-	return si, nil
-}
-
 func fillRange(from, to int) ([]int, error) {
 	if to < from {
 		return nil, cronError("fillRange")
@@ -65,15 +40,6 @@ func fillStep(from, step, max int) ([]int, error) {
 	return arr, nil
 }
 
-func normalize(field string, dict []string) int {
-	i, err := strconv.Atoi(field)
-	if err == nil {
-		return i
-	}
-
-	return intVal(dict, field)
-}
-
 func inScope(i, min, max int) bool {
 	if i >= min && i <= max {
 		return true
@@ -88,6 +54,9 @@ func cronError(cause string) error {
 
 func intVal(target []string, search string) int {
 	uSearch := strings.ToUpper(search)
+	if abbreviation, ok := fullNames[uSearch]; ok {
+		uSearch = abbreviation
+	}
 	for i, v := range target {
 		if v == uSearch {
 			return i
@@ -97,10 +66,81 @@ func intVal(target []string, search string) int {
 	return -1 // TODO: return error
 }
 
-// atoi implements an unsafe strconv.Atoi.
-func atoi(str string) int {
-	i, _ := strconv.Atoi(str)
-	return i
+// daysIn returns the number of days in the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdayOf returns the day of the week of the day of the month.
+func weekdayOf(year int, month time.Month, day int) time.Weekday {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+}
+
+// lastDayOfMonth resolves the L day-of-month value.
+func lastDayOfMonth(year int, month time.Month) []int {
+	return []int{daysIn(year, month)}
+}
+
+// lastWeekdayOfMonth resolves the LW day-of-month value.
+func lastWeekdayOfMonth(year int, month time.Month) []int {
+	last := daysIn(year, month)
+	switch weekdayOf(year, month, last) {
+	case time.Saturday:
+		return []int{last - 1}
+	case time.Sunday:
+		return []int{last - 2}
+	}
+
+	return []int{last}
+}
+
+// nearestWeekday resolves the W day-of-month value, which does not cross
+// the month boundaries. Months without the given day are skipped.
+func nearestWeekday(day int) func(int, time.Month) []int {
+	return func(year int, month time.Month) []int {
+		last := daysIn(year, month)
+		if day > last {
+			return nil
+		}
+
+		switch weekdayOf(year, month, day) {
+		case time.Saturday:
+			if day == 1 {
+				return []int{day + 2}
+			}
+			return []int{day - 1}
+		case time.Sunday:
+			if day == last {
+				return []int{day - 2}
+			}
+			return []int{day + 1}
+		}
+
+		return []int{day}
+	}
+}
+
+// lastWeekday resolves the L day-of-week value.
+func lastWeekday(weekday time.Weekday) func(int, time.Month) []int {
+	return func(year int, month time.Month) []int {
+		last := daysIn(year, month)
+		offset := (int(weekdayOf(year, month, last)) - int(weekday) + 7) % 7
+		return []int{last - offset}
+	}
+}
+
+// nthWeekday resolves the # day-of-week value. Months without the nth
+// weekday are skipped.
+func nthWeekday(weekday time.Weekday, n int) func(int, time.Month) []int {
+	return func(year int, month time.Month) []int {
+		first := 1 + (int(weekday)-int(weekdayOf(year, month, 1))+7)%7
+		day := first + 7*(n-1)
+		if day > daysIn(year, month) {
+			return nil
+		}
+
+		return []int{day}
+	}
 }
 
 // NowNano returns the current UTC Unix time in nanoseconds.
@@ -108,8 +148,27 @@ func NowNano() int64 {
 	return time.Now().UTC().UnixNano()
 }
 
-func isOutdated(_time int64) bool {
-	return _time < NowNano()-(10*time.Millisecond).Nanoseconds()
+// unixNano returns the time in Unix nanoseconds, or zero for the zero time.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+// unixTime returns the time of the Unix nanoseconds, or the zero time for
+// zero.
+func unixTime(nano int64) time.Time {
+	if nano == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nano)
+}
+
+func isOutdated(_time, now int64, threshold time.Duration) bool {
+	return _time < now-threshold.Nanoseconds()
 }
 
 // HashCode calculates and returns a hash code for the given string.
@@ -118,3 +177,70 @@ func HashCode(s string) int {
 	h.Write([]byte(s))
 	return int(h.Sum32())
 }
+
+// HashKey returns a Job key derived from the parts identifying the Job,
+// the 64-bit FNV-1a hash of the parts truncated to the lower half of the
+// non-negative range of int, the upper half being reserved for the keys
+// returned by UniqueJobKey. Each part is hashed along with its length, so
+// that different parts never concatenate to the same key input.
+func HashKey(parts ...string) int {
+	h := fnv.New64a()
+	var size [binary.MaxVarintLen64]byte
+	for _, part := range parts {
+		n := binary.PutUvarint(size[:], uint64(len(part)))
+		h.Write(size[:n])
+		h.Write([]byte(part))
+	}
+
+	return int(h.Sum64() & (math.MaxInt >> 1))
+}
+
+// waitGroup is a WaitGroup which can be waited on using a channel, so
+// that waiting with a deadline does not leave a goroutine behind.
+type waitGroup struct {
+	mtx     sync.Mutex
+	counter int
+	idle    chan struct{}
+}
+
+// Add adds delta to the counter, releasing the waiters once it is zero.
+func (wg *waitGroup) Add(delta int) {
+	wg.mtx.Lock()
+	defer wg.mtx.Unlock()
+
+	wg.counter += delta
+	if wg.counter < 0 {
+		panic("quartz: negative waitGroup counter")
+	}
+	if wg.counter == 0 && wg.idle != nil {
+		close(wg.idle)
+		wg.idle = nil
+	}
+}
+
+// Done decrements the counter.
+func (wg *waitGroup) Done() {
+	wg.Add(-1)
+}
+
+// Wait blocks until the counter is zero.
+func (wg *waitGroup) Wait() {
+	<-wg.Idle()
+}
+
+// Idle returns a channel which is closed once the counter is zero.
+func (wg *waitGroup) Idle() <-chan struct{} {
+	wg.mtx.Lock()
+	defer wg.mtx.Unlock()
+
+	if wg.counter == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	if wg.idle == nil {
+		wg.idle = make(chan struct{})
+	}
+
+	return wg.idle
+}
@@ -24,4 +24,14 @@ func assertNotEqual[T any](t *testing.T, a T, b T) {
 func TestUtils(t *testing.T) {
 	hash := quartz.HashCode("foo")
 	assertEqual(t, hash, 2851307223)
+
+	assertEqual(t, quartz.HashKey("foo"), quartz.HashKey("foo"))
+	assertNotEqual(t, quartz.HashKey("ab", "c"), quartz.HashKey("a", "bc"))
+	assertNotEqual(t, quartz.HashKey("foo", ""), quartz.HashKey("foo"))
+	unique := quartz.UniqueJobKey()
+	for _, parts := range [][]string{{}, {"foo"}, {"foo", "bar"}, {"ShellJob", "ls"}} {
+		if key := quartz.HashKey(parts...); key < 0 || key >= unique {
+			t.Fatal("key out of range", key)
+		}
+	}
 }
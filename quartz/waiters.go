@@ -0,0 +1,71 @@
+package quartz
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrJobDeleted is returned by WaitForJob when the Job is deleted, or
+// leaves the scheduler, before its next execution completes.
+var ErrJobDeleted = errors.New("the Job was deleted before it was executed")
+
+// WaitForJob blocks until the next execution of the Job with the
+// specified key completes, in any of the dispatch modes, and returns the
+// error of the execution. It returns ErrJobNotFound if the Job is not
+// scheduled, ErrJobDeleted if the Job is deleted before it is executed,
+// or the error of the context once it is done.
+func (sched *StdScheduler) WaitForJob(ctx context.Context, key int) error {
+	jobKey := intJobKey(key)
+	waiter := make(chan error, 1)
+
+	sched.mtx.Lock()
+	if sched.findItem(jobKey) == nil {
+		sched.mtx.Unlock()
+		return ErrJobNotFound
+	}
+	sched.waiters[jobKey] = append(sched.waiters[jobKey], waiter)
+	sched.mtx.Unlock()
+
+	select {
+	case err := <-waiter:
+		return err
+	case <-ctx.Done():
+		sched.mtx.Lock()
+		sched.removeWaiter(jobKey, waiter)
+		sched.mtx.Unlock()
+
+		// the execution may have completed in the meantime
+		select {
+		case err := <-waiter:
+			return err
+		default:
+			return ctx.Err()
+		}
+	}
+}
+
+// releaseWaiters passes the error, nil on success, to the waiters of the
+// Job with the specified key. The caller must hold the lock.
+func (sched *StdScheduler) releaseWaiters(key JobKey, err error) {
+	for _, waiter := range sched.waiters[key] {
+		waiter <- err
+	}
+	delete(sched.waiters, key)
+}
+
+// removeWaiter removes the waiter of the Job with the specified key,
+// which is no longer waiting. The caller must hold the lock.
+func (sched *StdScheduler) removeWaiter(key JobKey, waiter chan error) {
+	waiters := sched.waiters[key]
+	for i, w := range waiters {
+		if w == waiter {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(sched.waiters, key)
+		return
+	}
+	sched.waiters[key] = waiters
+}
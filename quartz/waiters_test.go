@@ -0,0 +1,91 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+func TestSchedulerWaitForJob(t *testing.T) {
+	jobErr := errors.New("job error")
+	for name, opts := range map[string]quartz.StdSchedulerOptions{
+		"spawn":    {},
+		"workers":  {WorkerLimit: 2},
+		"blocking": {BlockingExecution: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			opts.Logger = quartz.NewNoopLogger()
+			sched := quartz.NewStdSchedulerWithOptions(opts)
+			if err := sched.StartErr(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer sched.Stop()
+
+			var runs int32
+			key, err := sched.ScheduleFunc(ctx, func(_ context.Context) error {
+				if atomic.AddInt32(&runs, 1) == 2 {
+					return jobErr
+				}
+				return nil
+			}, quartz.NewSimpleTrigger(20*time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := sched.WaitForJob(ctx, key); err != nil {
+				t.Fatal(err)
+			}
+			if err := sched.WaitForJob(ctx, key); !errors.Is(err, jobErr) {
+				t.Fatal("unexpected error", err)
+			}
+		})
+	}
+}
+
+func TestSchedulerWaitForJobErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sched := quartz.NewStdSchedulerWithOptions(quartz.StdSchedulerOptions{
+		Logger: quartz.NewNoopLogger(),
+	})
+	if err := sched.WaitForJob(ctx, 1); !errors.Is(err, quartz.ErrJobNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	job := quartz.NewShellJob("ls")
+	if err := sched.ScheduleJob(ctx, job, quartz.NewSimpleTrigger(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer timeoutCancel()
+	if err := sched.WaitForJob(timeoutCtx, job.Key()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// the waiters are released once the Job is deleted
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- sched.WaitForJob(ctx, job.Key()) }()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := sched.DeleteJob(job.Key()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			assertEqual(t, errors.Is(err, quartz.ErrJobDeleted), true)
+		case <-ctx.Done():
+			t.Fatal("the waiter was not released")
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package quartz
+
+import "context"
+
+// WorkerStats describes the worker pool of the StdScheduler.
+type WorkerStats struct {
+	// Limit is the effective size of the worker pool, zero if the
+	// jobs are not executed by a worker pool.
+	Limit int
+
+	// Workers is the number of the running workers, which exceeds
+	// the Limit while the excess workers finish their current jobs.
+	Workers int
+
+	// Busy is the number of the workers executing a job.
+	Busy int
+
+	// Pools holds the stats of the named worker pools, set when
+	// the Pools of the StdSchedulerOptions are configured.
+	Pools map[string]WorkerStats
+}
+
+// workerPool tracks the workers of a single run of the StdScheduler.
+type workerPool struct {
+	ctx     context.Context
+	name    string
+	limit   int
+	workers int
+	busy    int
+
+	// lastWorker is the id of the most recently started worker.
+	lastWorker int
+
+	// dispatch holds the fires waiting for a worker of the pool.
+	dispatch chan *fire
+
+	// resized is closed to wake up the idle workers and the
+	// blocked dispatches when the pool is resized.
+	resized chan struct{}
+}
+
+// SetWorkerLimit changes the size of the worker pool at runtime. Growing
+// the pool starts new workers, while the excess workers exit once they
+// finish their current jobs. A limit of 0 disables the pool, executing
+// each of the jobs in its own goroutine. The limit is ignored when
+// BlockingExecution is set.
+func (sched *StdScheduler) SetWorkerLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	sched.workerLimit = limit
+	if !sched.isRunning() {
+		return
+	}
+
+	sched.pool.limit = limit
+	sched.growWorkers(sched.pool)
+	close(sched.pool.resized)
+	sched.pool.resized = make(chan struct{})
+}
+
+// WorkerStats returns the current state of the worker pool.
+func (sched *StdScheduler) WorkerStats() WorkerStats {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	var stats WorkerStats
+	if !sched.opts.BlockingExecution {
+		stats.Limit = sched.workerLimit
+	}
+	running := sched.pool != nil && sched.isRunning()
+	if running {
+		stats.Workers = sched.pool.workers
+		stats.Busy = sched.pool.busy
+	}
+	if len(sched.opts.Pools) > 0 {
+		stats.Pools = make(map[string]WorkerStats, len(sched.opts.Pools))
+		for name, limit := range sched.opts.Pools {
+			var poolStats WorkerStats
+			if !sched.opts.BlockingExecution {
+				poolStats.Limit = limit
+			}
+			if pool := sched.pools[name]; running && pool != nil {
+				poolStats.Workers = pool.workers
+				poolStats.Busy = pool.busy
+			}
+			stats.Pools[name] = poolStats
+		}
+	}
+
+	return stats
+}
+
+// BusyWorkers returns the number of the workers executing a job, across
+// all of the worker pools, zero if the jobs are not executed by a pool.
+func (sched *StdScheduler) BusyWorkers() int {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	if sched.pool == nil || !sched.isRunning() {
+		return 0
+	}
+
+	busy := sched.pool.busy
+	for _, pool := range sched.pools {
+		busy += pool.busy
+	}
+
+	return busy
+}
+
+// startWorkers starts the worker pool of the run when the worker limit
+// is greater than 0, along with the named worker pools. The caller must
+// hold the lock.
+func (sched *StdScheduler) startWorkers(ctx context.Context) {
+	sched.pool = &workerPool{
+		ctx:      ctx,
+		limit:    sched.workerLimit,
+		dispatch: sched.dispatch,
+		resized:  make(chan struct{}),
+	}
+	sched.growWorkers(sched.pool)
+
+	sched.pools = make(map[string]*workerPool, len(sched.opts.Pools))
+	for name, limit := range sched.opts.Pools {
+		pool := &workerPool{
+			ctx:      ctx,
+			name:     name,
+			limit:    limit,
+			dispatch: make(chan *fire, sched.opts.DispatchQueueSize),
+			resized:  make(chan struct{}),
+		}
+		sched.pools[name] = pool
+		sched.growWorkers(pool)
+	}
+}
+
+// growWorkers starts the workers missing up to the limit of the pool.
+// The caller must hold the lock.
+func (sched *StdScheduler) growWorkers(pool *workerPool) {
+	if sched.opts.BlockingExecution {
+		return
+	}
+
+	for ; pool.workers < pool.limit; pool.workers++ {
+		pool.lastWorker++
+		sched.wg.Add(1)
+		go sched.worker(pool, pool.lastWorker)
+	}
+}
+
+// usePool determines whether the fires are dispatched to the worker pool.
+func (sched *StdScheduler) usePool() bool {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	return sched.workerLimit > 0
+}
+
+// defaultPool returns the worker pool of the current run.
+func (sched *StdScheduler) defaultPool() *workerPool {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	return sched.pool
+}
+
+// namedPool returns the named worker pool of the current run.
+func (sched *StdScheduler) namedPool(name string) *workerPool {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	return sched.pools[name]
+}
+
+// worker executes the dispatched fires until the run is stopped, or
+// until the worker is in excess of the worker limit. The last worker
+// does not exit while fires are waiting in the dispatch queue.
+func (sched *StdScheduler) worker(pool *workerPool, id int) {
+	defer sched.wg.Done()
+
+	for {
+		sched.mtx.Lock()
+		if pool.workers > pool.limit && (pool.workers > 1 || len(pool.dispatch) == 0) {
+			pool.workers--
+			sched.mtx.Unlock()
+			return
+		}
+		resized := pool.resized
+		sched.mtx.Unlock()
+
+		select {
+		case <-pool.ctx.Done():
+			sched.mtx.Lock()
+			pool.workers--
+			sched.mtx.Unlock()
+			return
+		case <-resized:
+		case f := <-pool.dispatch:
+			f.pool, f.worker = pool.name, id
+			sched.setBusy(pool, 1)
+			sched.run(f)
+			sched.setBusy(pool, -1)
+		}
+	}
+}
+
+func (sched *StdScheduler) setBusy(pool *workerPool, delta int) {
+	sched.mtx.Lock()
+	defer sched.mtx.Unlock()
+
+	pool.busy += delta
+}